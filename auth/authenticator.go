@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+type Config struct {
+	HMACSecretFile   string
+	RSAPublicKeyFile string
+	JWKSFile         string
+	APIKeysFile      string
+	Issuer           string
+	Audience         string
+}
+
+// Enabled reports whether any credential source has been configured.
+func (c Config) Enabled() bool {
+	return c.HMACSecretFile != "" || c.RSAPublicKeyFile != "" || c.JWKSFile != "" || c.APIKeysFile != ""
+}
+
+type apiKey struct {
+	Name   string   `json:"name"`
+	Key    string   `json:"key"`
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+}
+
+type Authenticator struct {
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	jwks       map[string]any
+	apiKeys    map[[sha256.Size]byte]apiKey
+	parser     *jwt.Parser
+}
+
+func New(cfg Config) (*Authenticator, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("auth: no credential source configured")
+	}
+
+	a := &Authenticator{apiKeys: make(map[[sha256.Size]byte]apiKey)}
+
+	if cfg.HMACSecretFile != "" {
+		secret, err := os.ReadFile(cfg.HMACSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+
+		a.hmacSecret = []byte(strings.TrimSpace(string(secret)))
+
+		if len(a.hmacSecret) == 0 {
+			return nil, fmt.Errorf("auth: HMAC secret file %s is empty", cfg.HMACSecretFile)
+		}
+	}
+
+	if cfg.RSAPublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+
+		a.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("auth: parsing RSA public key: %w", err)
+		}
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		a.jwks = keys
+	}
+
+	if cfg.APIKeysFile != "" {
+		err := a.loadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+func (a *Authenticator) loadAPIKeys(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+
+	var keys []apiKey
+
+	err = json.Unmarshal(data, &keys)
+	if err != nil {
+		return fmt.Errorf("auth: parsing API keys %s: %w", file, err)
+	}
+
+	for i, key := range keys {
+		if key.Name == "" || key.Key == "" {
+			return fmt.Errorf("auth: API key %d must have a name and a key", i)
+		}
+
+		a.apiKeys[sha256.Sum256([]byte(key.Key))] = key
+	}
+
+	return nil
+}
+
+// VerifyToken validates a bearer JWT and returns the identity named by its subject.
+func (a *Authenticator) VerifyToken(token string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := a.parser.ParseWithClaims(token, claims, a.keyFunc)
+	if err != nil {
+		return nil, errors.Unauthenticated{Reason: "invalid token"}
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.Unauthenticated{Reason: "token has no subject"}
+	}
+
+	tenant, _ := claims["tenant"].(string)
+
+	return &Identity{Subject: subject, Method: MethodJWT, Roles: stringsClaim(claims, "roles"), Tenant: tenant}, nil
+}
+
+// VerifyAPIKey looks up a static API key. Keys are compared by their SHA-256 digest so that the
+// lookup does not leak how much of a key matched.
+func (a *Authenticator) VerifyAPIKey(key string) (*Identity, error) {
+	entry, ok := a.apiKeys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, errors.Unauthenticated{Reason: "invalid API key"}
+	}
+
+	return &Identity{Subject: entry.Name, Method: MethodAPIKey, Roles: entry.Roles, Tenant: entry.Tenant}, nil
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		if key, ok := a.jwks[kid]; ok {
+			return key, nil
+		}
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.hmacSecret != nil {
+			return a.hmacSecret, nil
+		}
+
+	case *jwt.SigningMethodRSA:
+		if a.rsaKey != nil {
+			return a.rsaKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no key configured for %s", token.Method.Alg())
+}
+
+// stringsClaim reads a claim that is either a JSON array of strings or a single space separated
+// string.
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return strings.Fields(v)
+
+	case []any:
+		values := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
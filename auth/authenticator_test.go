@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+const testHMACSecret = "super-secret-signing-key"
+
+type testKeys struct {
+	cfg      Config
+	rsaKey   *rsa.PrivateKey
+	jwksRSA  *rsa.PrivateKey
+	jwksHMAC []byte
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	jwksHMAC := []byte("jwks-hmac-secret")
+
+	set := map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(jwksRSA.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksRSA.E)).Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "hmac-1",
+				"k":   base64.RawURLEncoding.EncodeToString(jwksHMAC),
+			},
+			{
+				"kty": "RSA",
+				"kid": "enc-1",
+				"use": "enc",
+			},
+		},
+	}
+
+	jwksJSON, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	apiKeys := `[{"name": "batch-job", "key": "batch-job-key", "roles": ["reader"], "tenant": "acme"}]`
+
+	return &testKeys{
+		cfg: Config{
+			HMACSecretFile:   writeTestFile(t, dir, "hmac.secret", []byte(testHMACSecret+"\n")),
+			RSAPublicKeyFile: writeTestFile(t, dir, "rsa.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+			JWKSFile:         writeTestFile(t, dir, "jwks.json", jwksJSON),
+			APIKeysFile:      writeTestFile(t, dir, "api_keys.json", []byte(apiKeys)),
+		},
+		rsaKey:   rsaKey,
+		jwksRSA:  jwksRSA,
+		jwksHMAC: jwksHMAC,
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "alice",
+		"iss": "issuer",
+		"aud": "user-detail-management",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func Test_New(t *testing.T) {
+	keys := newTestKeys(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"All sources", keys.cfg, false},
+		{"Only API keys", Config{APIKeysFile: keys.cfg.APIKeysFile}, false},
+		{"No sources", Config{}, true},
+		{"Missing HMAC secret file", Config{HMACSecretFile: filepath.Join(dir, "missing")}, true},
+		{"Empty HMAC secret", Config{HMACSecretFile: writeTestFile(t, dir, "empty", []byte("\n"))}, true},
+		{"Invalid RSA public key", Config{RSAPublicKeyFile: keys.cfg.HMACSecretFile}, true},
+		{"Invalid JWKS", Config{JWKSFile: keys.cfg.HMACSecretFile}, true},
+		{"JWKS key without kid", Config{JWKSFile: writeTestFile(t, dir, "nokid.json", []byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`))}, true},
+		{"JWKS unsupported kty", Config{JWKSFile: writeTestFile(t, dir, "ec.json", []byte(`{"keys":[{"kty":"EC","kid":"ec-1"}]}`))}, true},
+		{"API key without name", Config{APIKeysFile: writeTestFile(t, dir, "keys.json", []byte(`[{"key":"k"}]`))}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(tt.cfg)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, a)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, a)
+		})
+	}
+}
+
+func Test_VerifyToken(t *testing.T) {
+	keys := newTestKeys(t)
+
+	cfg := keys.cfg
+	cfg.Issuer = "issuer"
+	cfg.Audience = "user-detail-management"
+
+	a, err := New(cfg)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	noExpiry := validClaims()
+	delete(noExpiry, "exp")
+
+	noSubject := validClaims()
+	delete(noSubject, "sub")
+
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "other-service"
+
+	rolesArray := validClaims()
+	rolesArray["roles"] = []string{"reader", "admin"}
+
+	rolesString := validClaims()
+	rolesString["roles"] = "reader admin"
+
+	withTenant := validClaims()
+	withTenant["tenant"] = "acme"
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *Identity
+		wantErr error
+	}{
+		{
+			"HS256 with configured secret",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", validClaims()),
+			&Identity{Subject: "alice", Method: MethodJWT}, nil,
+		},
+		{
+			"RS256 with configured public key",
+			signToken(t, jwt.SigningMethodRS256, keys.rsaKey, "", validClaims()),
+			&Identity{Subject: "alice", Method: MethodJWT}, nil,
+		},
+		{
+			"RS256 with JWKS key",
+			signToken(t, jwt.SigningMethodRS256, keys.jwksRSA, "rsa-1", validClaims()),
+			&Identity{Subject: "alice", Method: MethodJWT}, nil,
+		},
+		{
+			"HS256 with JWKS key",
+			signToken(t, jwt.SigningMethodHS256, keys.jwksHMAC, "hmac-1", validClaims()),
+			&Identity{Subject: "alice", Method: MethodJWT}, nil,
+		},
+		{
+			"Roles as array",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", rolesArray),
+			&Identity{Subject: "alice", Method: MethodJWT, Roles: []string{"reader", "admin"}}, nil,
+		},
+		{
+			"Roles as space separated string",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", rolesString),
+			&Identity{Subject: "alice", Method: MethodJWT, Roles: []string{"reader", "admin"}}, nil,
+		},
+		{
+			"Tenant claim",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", withTenant),
+			&Identity{Subject: "alice", Method: MethodJWT, Tenant: "acme"}, nil,
+		},
+		{
+			"Wrong HMAC secret",
+			signToken(t, jwt.SigningMethodHS256, []byte("wrong"), "", validClaims()),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Unknown RSA key",
+			signToken(t, jwt.SigningMethodRS256, otherKey, "", validClaims()),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Algorithm confusion with JWKS RSA kid",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "rsa-1", validClaims()),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Disallowed algorithm",
+			signToken(t, jwt.SigningMethodHS512, []byte(testHMACSecret), "", validClaims()),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Expired token",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", expired),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Token without expiry",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", noExpiry),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Wrong audience",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", wrongAudience),
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+		{
+			"Token without subject",
+			signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", noSubject),
+			nil, errors.Unauthenticated{Reason: "token has no subject"},
+		},
+		{
+			"Malformed token",
+			"not-a-jwt",
+			nil, errors.Unauthenticated{Reason: "invalid token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := a.VerifyToken(tt.token)
+
+			assert.Equal(t, tt.want, identity)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_VerifyAPIKey(t *testing.T) {
+	keys := newTestKeys(t)
+
+	a, err := New(Config{APIKeysFile: keys.cfg.APIKeysFile})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     string
+		want    *Identity
+		wantErr error
+	}{
+		{"Known key", "batch-job-key", &Identity{Subject: "batch-job", Method: MethodAPIKey, Roles: []string{"reader"}, Tenant: "acme"}, nil},
+		{"Unknown key", "guess", nil, errors.Unauthenticated{Reason: "invalid API key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := a.VerifyAPIKey(tt.key)
+
+			assert.Equal(t, tt.want, identity)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "context"
+
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Identity describes the authenticated caller of an RPC.
+type Identity struct {
+	Subject string
+	Method  string
+	Roles   []string
+	// Tenant pins the caller to a single tenant. It is empty for callers that may act on any
+	// tenant.
+	Tenant string
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller identity stored in ctx, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+
+	return identity, ok && identity != nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// loadJWKS reads a JSON Web Key Set from file. Only RSA ("RSA") and symmetric ("oct") signing
+// keys are supported; keys meant for encryption are skipped.
+func loadJWKS(file string) (map[string]any, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	var set jwks
+
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parsing JWKS %s: %w", file, err)
+	}
+
+	keys := make(map[string]any)
+
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if key.Kid == "" {
+			return nil, fmt.Errorf("auth: JWKS key %d has no kid", i)
+		}
+
+		switch key.Kty {
+		case "RSA":
+			pub, err := rsaPublicKeyFromJWK(key)
+			if err != nil {
+				return nil, fmt.Errorf("auth: JWKS key %s: %w", key.Kid, err)
+			}
+
+			keys[key.Kid] = pub
+
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("auth: JWKS key %s: invalid k", key.Kid)
+			}
+
+			keys[key.Kid] = secret
+
+		default:
+			return nil, fmt.Errorf("auth: JWKS key %s: unsupported kty %q", key.Kid, key.Kty)
+		}
+	}
+
+	return keys, nil
+}
+
+func rsaPublicKeyFromJWK(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(n) == 0 {
+		return nil, fmt.Errorf("invalid modulus")
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(e) == 0 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// AnyRole grants a method to every authenticated caller regardless of its roles.
+const AnyRole = "*"
+
+// Policy maps fully qualified gRPC method names (e.g. "/user.UserService/Get") to the roles
+// allowed to call them. Methods that are not listed are denied.
+type Policy struct {
+	methods map[string]map[string]bool
+}
+
+// LoadPolicy reads a policy from a JSON file of the form {"<full method>": ["role", ...]}.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	var methods map[string][]string
+
+	err = json.Unmarshal(data, &methods)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parsing policy %s: %w", file, err)
+	}
+
+	return NewPolicy(methods), nil
+}
+
+func NewPolicy(methods map[string][]string) *Policy {
+	p := &Policy{methods: make(map[string]map[string]bool)}
+
+	for method, roles := range methods {
+		p.methods[method] = make(map[string]bool)
+
+		for _, role := range roles {
+			p.methods[method][role] = true
+		}
+	}
+
+	return p
+}
+
+// Allowed reports whether a caller holding roles may invoke method.
+func (p *Policy) Allowed(method string, roles []string) bool {
+	allowed, ok := p.methods[method]
+	if !ok {
+		return false
+	}
+
+	if allowed[AnyRole] {
+		return true
+	}
+
+	for _, role := range roles {
+		if allowed[role] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "policy.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"/user.UserService/Get": ["reader"]}`), 0o600))
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`["reader"]`), 0o600))
+
+	policy, err := LoadPolicy(valid)
+	assert.NoError(t, err)
+	assert.True(t, policy.Allowed("/user.UserService/Get", []string{"reader"}))
+
+	_, err = LoadPolicy(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadPolicy(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func Test_PolicyAllowed(t *testing.T) {
+	policy := NewPolicy(map[string][]string{
+		"/user.UserService/Get":    {"reader", "admin"},
+		"/user.UserService/Delete": {"admin"},
+		"/user.UserService/Ping":   {AnyRole},
+		"/user.UserService/Nobody": {},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		roles  []string
+		want   bool
+	}{
+		{"Role listed", "/user.UserService/Get", []string{"reader"}, true},
+		{"One of several roles listed", "/user.UserService/Delete", []string{"reader", "admin"}, true},
+		{"Role not listed", "/user.UserService/Delete", []string{"reader"}, false},
+		{"No roles", "/user.UserService/Get", nil, false},
+		{"Any role", "/user.UserService/Ping", nil, true},
+		{"Method without roles", "/user.UserService/Nobody", []string{"admin"}, false},
+		{"Unlisted method", "/user.UserService/Create", []string{"admin"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Allowed(tt.method, tt.roles))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Package backoff holds the helpers waiting between the attempts of a retried call.
+package backoff
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Jitter picks a wait in [d/2, d), so that callers failing together do not retry together.
+func Jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+
+	return half + rand.N(half)
+}
+
+// Sleep waits for d or until ctx is done, in which case it returns the error of ctx.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
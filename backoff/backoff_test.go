@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Jitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := Jitter(time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.Less(t, d, time.Second)
+	}
+
+	assert.Equal(t, time.Duration(1), Jitter(1))
+}
+
+func Test_Sleep(t *testing.T) {
+	assert.NoError(t, Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, Sleep(ctx, time.Hour))
+}
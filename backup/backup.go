@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// Version is the version of the files written by Write, whose heights are in centimeters and which
+// hold the merges of the tenant. Read also reads files of version 2, without merges, and of version
+// 1, written before heights were stored in centimeters, as long as their heights are plausible ones
+// in centimeters, and rejects any other version.
+const (
+	Version        = 3
+	minReadVersion = 1
+)
+
+type File struct {
+	CreatedAt time.Time
+	// Tenant is the tenant the snapshot was taken from. A file can be restored into any tenant.
+	Tenant   string
+	Snapshot store.Snapshot
+}
+
+// envelope is the layout of a backup file: a JSON object holding the format version, the
+// creation time, the tenant and the snapshot of its users, along with the SHA-256 checksum of the
+// snapshot as written.
+type envelope struct {
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Tenant    string          `json:"tenant"`
+	SHA256    string          `json:"sha256"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+}
+
+type snapshot struct {
+	LastInsertedID int           `json:"last_inserted_id"`
+	Users          []models.User `json:"users"`
+	Merges         []merge       `json:"merges,omitempty"`
+}
+
+type merge struct {
+	Revision int64       `json:"revision"`
+	MergedAt time.Time   `json:"merged_at"`
+	Survivor models.User `json:"survivor"`
+	Merged   models.User `json:"merged"`
+	Result   models.User `json:"result"`
+}
+
+// Write writes f to w as a backup file.
+func Write(w io.Writer, f File) error {
+	snap := snapshot{LastInsertedID: f.Snapshot.LastInsertedID, Users: f.Snapshot.Users}
+
+	for _, record := range f.Snapshot.Merges {
+		snap.Merges = append(snap.Merges, merge(record))
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return json.NewEncoder(w).Encode(envelope{
+		Version:   Version,
+		CreatedAt: f.CreatedAt.UTC(),
+		Tenant:    f.Tenant,
+		SHA256:    hex.EncodeToString(sum[:]),
+		Snapshot:  data,
+	})
+}
+
+// Read reads a backup file from r. It returns an errors.InvalidBackup when the file is of an
+// unknown version, does not match its checksum or holds an inconsistent snapshot.
+func Read(r io.Reader) (File, error) {
+	var env envelope
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&env); err != nil {
+		if err == io.EOF {
+			return File{}, errors.InvalidBackup{Reason: "empty file"}
+		}
+
+		return File{}, errors.InvalidBackup{Reason: "malformed file: " + err.Error()}
+	}
+
+	if env.Version < minReadVersion || env.Version > Version {
+		return File{}, errors.InvalidBackup{Reason: fmt.Sprintf("unsupported version %d", env.Version)}
+	}
+
+	sum := sha256.Sum256(env.Snapshot)
+	if hex.EncodeToString(sum[:]) != env.SHA256 {
+		return File{}, errors.InvalidBackup{Reason: "checksum mismatch"}
+	}
+
+	var snap snapshot
+
+	snapDec := json.NewDecoder(bytes.NewReader(env.Snapshot))
+	snapDec.DisallowUnknownFields()
+
+	if err := snapDec.Decode(&snap); err != nil {
+		return File{}, errors.InvalidBackup{Reason: "malformed snapshot: " + err.Error()}
+	}
+
+	if err := validate(snap); err != nil {
+		return File{}, err
+	}
+
+	file := File{
+		CreatedAt: env.CreatedAt,
+		Tenant:    env.Tenant,
+		Snapshot:  store.Snapshot{LastInsertedID: snap.LastInsertedID, Users: snap.Users},
+	}
+
+	for _, record := range snap.Merges {
+		file.Snapshot.Merges = append(file.Snapshot.Merges, store.MergeRecord(record))
+	}
+
+	return file, nil
+}
+
+// validate checks that the users of snap are sorted by unique IDs no greater than the last
+// inserted ID, as the store hands them out, that their heights are plausible, and that merges are
+// made between users with two such IDs.
+func validate(snap snapshot) error {
+	previousID := 0
+
+	for _, usr := range snap.Users {
+		if usr.ID <= previousID {
+			return errors.InvalidBackup{Reason: fmt.Sprintf("user %d is out of order", usr.ID)}
+		}
+
+		if usr.ID > snap.LastInsertedID {
+			return errors.InvalidBackup{Reason: fmt.Sprintf("user %d is past the last inserted ID %d", usr.ID, snap.LastInsertedID)}
+		}
+
+		if !(models.Height{Value: usr.Height}).Plausible() {
+			return errors.InvalidBackup{Reason: fmt.Sprintf("user %d has an implausible height of %g centimeters", usr.ID, usr.Height)}
+		}
+
+		previousID = usr.ID
+	}
+
+	for i, record := range snap.Merges {
+		survivorID, mergedID := record.Survivor.ID, record.Merged.ID
+
+		if survivorID <= 0 || mergedID <= 0 || survivorID == mergedID || record.Result.ID != survivorID ||
+			max(survivorID, mergedID) > snap.LastInsertedID {
+			return errors.InvalidBackup{Reason: fmt.Sprintf("merge %d is inconsistent", i+1)}
+		}
+	}
+
+	return nil
+}
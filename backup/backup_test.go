@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+func testFile() File {
+	return File{
+		CreatedAt: time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+		Tenant:    "acme",
+		Snapshot: store.Snapshot{
+			LastInsertedID: 5,
+			Users: []models.User{
+				{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180},
+				{ID: 4, Fname: "Jane", City: "Boston", Phone: "0987654321", Height: 167.64, Married: true},
+			},
+			Merges: []store.MergeRecord{{
+				Revision: 7,
+				MergedAt: time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC),
+				Survivor: models.User{ID: 1, Fname: "John", City: "New York", Height: 180},
+				Merged:   models.User{ID: 2, Fname: "Johnny", Phone: "1234567890", Height: 180},
+				Result:   models.User{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180},
+			}},
+		},
+	}
+}
+
+func Test_WriteRead(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, testFile()))
+
+	got, err := Read(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, testFile(), got)
+}
+
+func Test_Read(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, Write(&buf, testFile()))
+
+	valid := buf.String()
+
+	tests := []struct {
+		name string
+		file string
+		err  error
+	}{
+		{"Empty file", "", errors.InvalidBackup{Reason: "empty file"}},
+		{"Unsupported version", strings.Replace(valid, `"version":3`, `"version":4`, 1), errors.InvalidBackup{Reason: "unsupported version 4"}},
+		{"Tampered snapshot", strings.Replace(valid, `"John"`, `"Jim"`, 1), errors.InvalidBackup{Reason: "checksum mismatch"}},
+		{
+			"Users out of order",
+			withChecksum(t, `{"last_inserted_id":5,"users":[{"id":4,"height":180},{"id":1,"height":180}]}`),
+			errors.InvalidBackup{Reason: "user 1 is out of order"},
+		},
+		{
+			"User past the last inserted ID",
+			withChecksum(t, `{"last_inserted_id":3,"users":[{"id":4}]}`),
+			errors.InvalidBackup{Reason: "user 4 is past the last inserted ID 3"},
+		},
+		{
+			"Implausible height",
+			withChecksum(t, `{"last_inserted_id":5,"users":[{"id":4,"height":5.9}]}`),
+			errors.InvalidBackup{Reason: "user 4 has an implausible height of 5.9 centimeters"},
+		},
+		{
+			"Merge of a user into itself",
+			withChecksum(t, `{"last_inserted_id":5,"users":[],"merges":[{"survivor":{"id":4},"merged":{"id":4},"result":{"id":4}}]}`),
+			errors.InvalidBackup{Reason: "merge 1 is inconsistent"},
+		},
+		{
+			"Merge past the last inserted ID",
+			withChecksum(t, `{"last_inserted_id":5,"users":[],"merges":[{"survivor":{"id":4},"merged":{"id":6},"result":{"id":4}}]}`),
+			errors.InvalidBackup{Reason: "merge 1 is inconsistent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.file))
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+
+	_, err := Read(strings.NewReader(`{"version":1,"users":[]}`))
+	assert.ErrorContains(t, err, "invalid backup: malformed file")
+
+	got, err := Read(strings.NewReader(strings.Replace(valid, `"version":3`, `"version":1`, 1)))
+	require.NoError(t, err, "files of version 1 are read")
+	assert.Equal(t, testFile(), got)
+}
+
+// withChecksum returns a backup file holding snapshot as is, with a valid checksum.
+func withChecksum(t *testing.T, snapshot string) string {
+	t.Helper()
+
+	sum := sha256.Sum256([]byte(snapshot))
+	env := envelope{Version: Version, SHA256: hex.EncodeToString(sum[:]), Snapshot: json.RawMessage(snapshot)}
+
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	return string(data)
+}
@@ -0,0 +1,303 @@
+// Package client is a Go client for the UserService. It speaks in models types, applies a default
+// deadline to every call, retries calls the server could not be reached for, and turns the
+// statuses returned by the server back into the errors package types. Heights are sent and
+// returned in centimeters, the unit the server stores them in.
+package client
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ssshekhu53/user-detail-management/backoff"
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+const (
+	DefaultTimeout        = 5 * time.Second
+	DefaultMaxRetries     = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 2 * time.Second
+)
+
+// idempotencyKeyMetadataKey carries the key the server replays creates by.
+const idempotencyKeyMetadataKey = "x-idempotency-key"
+
+type Config struct {
+	// Timeout bounds every call, retries included, made with a context without a deadline.
+	Timeout time.Duration
+	// MaxRetries is how many times a call failing with Unavailable is retried. A negative value
+	// disables retries.
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry. It doubles on every retry, up to
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Token, APIKey and Tenant are sent in the authorization, x-api-key and x-tenant-id metadata
+	// of every call when set.
+	Token  string
+	APIKey string
+	Tenant string
+}
+
+// withDefaults fills the unset fields of c with their default value.
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultMaxRetries
+	}
+
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultInitialBackoff
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+
+	return c
+}
+
+type Client struct {
+	cfg  Config
+	rpc  pb.UserServiceClient
+	conn *grpc.ClientConn
+
+	// sleep waits for d or until ctx is done. It is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New returns a client making its calls over conn, which the caller keeps ownership of.
+func New(conn grpc.ClientConnInterface, cfg Config) *Client {
+	return &Client{cfg: cfg.withDefaults(), rpc: pb.NewUserServiceClient(conn), sleep: backoff.Sleep}
+}
+
+// Dial returns a client connected to target. opts must at least set the transport credentials.
+// The connection is closed by Close.
+func Dial(target string, cfg Config, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New(conn, cfg)
+	c.conn = conn
+
+	return c, nil
+}
+
+// Close closes the connection opened by Dial. It does nothing for clients created by New.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// Create creates usr, whose ID is ignored. Every attempt carries the same idempotency key, so a
+// retried create never creates the user twice.
+func (c *Client) Create(ctx context.Context, usr models.User) (*models.User, error) {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyMetadataKey, key)
+
+	return call(ctx, c, func(ctx context.Context) (*models.User, error) {
+		created, err := c.rpc.Create(ctx, &pb.UserRequest{
+			Fname:   usr.Fname,
+			City:    usr.City,
+			Phone:   usr.Phone,
+			Height:  usr.Height,
+			Married: usr.Married,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return userFromGRPC(created), nil
+	})
+}
+
+// Get returns every user of the tenant.
+func (c *Client) Get(ctx context.Context) ([]models.User, error) {
+	return call(ctx, c, func(ctx context.Context) ([]models.User, error) {
+		users, err := c.rpc.Get(ctx, &emptypb.Empty{})
+
+		return usersFromGRPC(users), err
+	})
+}
+
+func (c *Client) GetByID(ctx context.Context, id int) (*models.User, error) {
+	return call(ctx, c, func(ctx context.Context) (*models.User, error) {
+		usr, err := c.rpc.GetByID(ctx, &pb.UserID{Id: int32(id)})
+		if err != nil {
+			return nil, err
+		}
+
+		return userFromGRPC(usr), nil
+	})
+}
+
+// GetByIDs returns the users with the given IDs. Unknown IDs are skipped.
+func (c *Client) GetByIDs(ctx context.Context, ids ...int) ([]models.User, error) {
+	req := &pb.UserIDs{Ids: make([]int32, 0, len(ids))}
+
+	for _, id := range ids {
+		req.Ids = append(req.Ids, int32(id))
+	}
+
+	return call(ctx, c, func(ctx context.Context) ([]models.User, error) {
+		users, err := c.rpc.GetByIDs(ctx, req)
+
+		return usersFromGRPC(users), err
+	})
+}
+
+// Update replaces every field of the user with the ID of usr.
+func (c *Client) Update(ctx context.Context, usr models.User) (*models.User, error) {
+	return call(ctx, c, func(ctx context.Context) (*models.User, error) {
+		updated, err := c.rpc.Update(ctx, &pb.UserUpdateRequest{
+			Id:      int32(usr.ID),
+			Fname:   usr.Fname,
+			City:    usr.City,
+			Phone:   usr.Phone,
+			Height:  usr.Height,
+			Married: usr.Married,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return userFromGRPC(updated), nil
+	})
+}
+
+// Delete deletes the user with the given ID. When the response to a deletion is lost and the call
+// is retried, the retry fails with errors.UserNotFound.
+func (c *Client) Delete(ctx context.Context, id int) error {
+	_, err := call(ctx, c, func(ctx context.Context) (*emptypb.Empty, error) {
+		return c.rpc.Delete(ctx, &pb.UserID{Id: int32(id)})
+	})
+
+	return err
+}
+
+// Search returns the users matching every set filter.
+func (c *Client) Search(ctx context.Context, filters models.Filters) ([]models.User, error) {
+	req := &pb.Filters{}
+
+	if filters.Fname != nil {
+		req.Fname = *filters.Fname
+	}
+
+	if filters.City != nil {
+		req.City = *filters.City
+	}
+
+	if filters.Phone != nil {
+		req.Phone = *filters.Phone
+	}
+
+	if filters.Height != nil {
+		req.Height = *filters.Height
+	}
+
+	return call(ctx, c, func(ctx context.Context) ([]models.User, error) {
+		users, err := c.rpc.Search(ctx, req)
+
+		return usersFromGRPC(users), err
+	})
+}
+
+// call runs fn with the credentials of c attached to ctx, under the default deadline when ctx has
+// none, retrying it with exponential backoff while it fails with Unavailable. Its error is
+// converted by fromStatus.
+func call[T any](ctx context.Context, c *Client, fn func(ctx context.Context) (T, error)) (T, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+
+	ctx = c.outgoing(ctx)
+
+	delay := c.cfg.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := fn(ctx)
+		if err == nil || status.Code(err) != codes.Unavailable || attempt >= c.cfg.MaxRetries {
+			return resp, fromStatus(err)
+		}
+
+		if sleepErr := c.sleep(ctx, backoff.Jitter(delay)); sleepErr != nil {
+			return resp, fromStatus(err)
+		}
+
+		delay = min(2*delay, c.cfg.MaxBackoff)
+	}
+}
+
+func (c *Client) outgoing(ctx context.Context) context.Context {
+	var pairs []string
+
+	if c.cfg.Token != "" {
+		pairs = append(pairs, "authorization", "Bearer "+c.cfg.Token)
+	}
+
+	if c.cfg.APIKey != "" {
+		pairs = append(pairs, "x-api-key", c.cfg.APIKey)
+	}
+
+	if c.cfg.Tenant != "" {
+		pairs = append(pairs, "x-tenant-id", c.cfg.Tenant)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+func newIdempotencyKey() (string, error) {
+	var key [16]byte
+
+	if _, err := cryptorand.Read(key[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key[:]), nil
+}
+
+func userFromGRPC(usr *pb.User) *models.User {
+	return &models.User{
+		ID:      int(usr.GetId()),
+		Fname:   usr.GetFname(),
+		City:    usr.GetCity(),
+		Phone:   usr.GetPhone(),
+		Height:  usr.GetHeight(),
+		Married: usr.GetMarried(),
+	}
+}
+
+func usersFromGRPC(users *pb.Users) []models.User {
+	list := make([]models.User, 0, len(users.GetUsers()))
+
+	for _, usr := range users.GetUsers() {
+		list = append(list, *userFromGRPC(usr))
+	}
+
+	return list
+}
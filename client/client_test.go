@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	handlerUser "github.com/ssshekhu53/user-detail-management/handler/user"
+	"github.com/ssshekhu53/user-detail-management/interceptor"
+	"github.com/ssshekhu53/user-detail-management/models"
+	serviceUser "github.com/ssshekhu53/user-detail-management/service/user"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+	"github.com/ssshekhu53/user-detail-management/utils"
+)
+
+// testServer records the calls it receives and fails the next unavailable of them with
+// Unavailable, after handling them when lose is set, as if the response had been lost.
+type testServer struct {
+	mu          sync.Mutex
+	unavailable int
+	lose        bool
+	calls       []metadata.MD
+	deadlines   []time.Time
+}
+
+func (s *testServer) intercept(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	deadline, _ := ctx.Deadline()
+
+	s.mu.Lock()
+	s.calls = append(s.calls, md)
+	s.deadlines = append(s.deadlines, deadline)
+
+	fail := s.unavailable > 0
+	if fail {
+		s.unavailable--
+	}
+
+	s.mu.Unlock()
+
+	if !fail {
+		return handler(ctx, req)
+	}
+
+	if s.lose {
+		_, _ = handler(ctx, req)
+	}
+
+	return nil, status.Error(codes.Unavailable, "connection reset")
+}
+
+// newTestClient returns a client of a UserService backed by an in-memory store, served over an
+// in-process listener. The client does not wait between retries.
+func newTestClient(t *testing.T, cfg Config) (*Client, *testServer) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := &testServer{}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		srv.intercept,
+		interceptor.NewTenantInterceptor().UnaryTenantInterceptor,
+		interceptor.NewIdempotencyInterceptor(time.Minute).UnaryIdempotencyInterceptor,
+	))
+	pb.RegisterUserServiceServer(s, handlerUser.New(serviceUser.New(storeUser.New())))
+
+	go func() { _ = s.Serve(lis) }()
+
+	t.Cleanup(s.Stop)
+
+	c, err := Dial("passthrough:///bufnet", cfg,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = c.Close() })
+
+	c.sleep = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+
+	return c, srv
+}
+
+var john = models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 180}
+
+func Test_CRUD(t *testing.T) {
+	c, _ := newTestClient(t, Config{})
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, john)
+	require.NoError(t, err)
+	assert.Equal(t, &models.User{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180}, created)
+
+	created.City, created.Married = "Boston", true
+
+	updated, err := c.Update(ctx, *created)
+	require.NoError(t, err)
+	assert.Equal(t, created, updated)
+
+	usr, err := c.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, updated, usr)
+
+	users, err := c.GetByIDs(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{*updated}, users)
+
+	users, err = c.Search(ctx, models.Filters{City: utils.StrPtr("boston")})
+	require.NoError(t, err)
+	assert.Equal(t, []models.User{*updated}, users)
+
+	users, err = c.Search(ctx, models.Filters{City: utils.StrPtr("Denver")})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	require.NoError(t, c.Delete(ctx, 1))
+
+	users, err = c.Get(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func Test_TypedErrors(t *testing.T) {
+	c, _ := newTestClient(t, Config{})
+	ctx := context.Background()
+
+	_, err := c.Create(ctx, john)
+	require.NoError(t, err)
+
+	_, err = c.Create(ctx, john)
+	assert.Equal(t, errors.UserAlreadyExists{}, err)
+
+	_, err = c.Create(ctx, models.User{Fname: "Jane", City: "Denver", Phone: "123", Height: 165})
+	assert.Equal(t, errors.InvalidParams{Params: []string{"phone"}}, err)
+
+	_, err = c.GetByID(ctx, 7)
+	assert.Equal(t, errors.UserNotFound{ID: 7}, err)
+
+	assert.Equal(t, errors.InvalidParams{Params: []string{"id"}}, c.Delete(ctx, 0))
+}
+
+func Test_Retry(t *testing.T) {
+	t.Run("Succeeds after retries", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{MaxRetries: 2})
+		srv.unavailable = 2
+
+		users, err := c.Get(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, users)
+		assert.Len(t, srv.calls, 3)
+	})
+
+	t.Run("Gives up", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{MaxRetries: 2})
+		srv.unavailable = 5
+
+		_, err := c.Get(context.Background())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Len(t, srv.calls, 3)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{MaxRetries: -1})
+		srv.unavailable = 1
+
+		_, err := c.Get(context.Background())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Len(t, srv.calls, 1)
+	})
+
+	t.Run("Other codes are not retried", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{})
+
+		_, err := c.GetByID(context.Background(), 1)
+		assert.Equal(t, errors.UserNotFound{ID: 1}, err)
+		assert.Len(t, srv.calls, 1)
+	})
+
+	t.Run("Retried creates are idempotent", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{})
+		srv.unavailable, srv.lose = 1, true
+
+		created, err := c.Create(context.Background(), john)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created.ID)
+
+		require.Len(t, srv.calls, 2)
+
+		key := srv.calls[0].Get(idempotencyKeyMetadataKey)
+		require.Len(t, key, 1)
+		assert.NotEmpty(t, key[0])
+		assert.Equal(t, key, srv.calls[1].Get(idempotencyKeyMetadataKey))
+
+		users, err := c.Get(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		_, err = c.Create(context.Background(), models.User{Fname: "Jane", City: "Denver", Phone: "0987654321", Height: 165})
+		require.NoError(t, err)
+		assert.NotEqual(t, key, srv.calls[len(srv.calls)-1].Get(idempotencyKeyMetadataKey))
+	})
+
+	t.Run("Stops when the context is done", func(t *testing.T) {
+		c, srv := newTestClient(t, Config{MaxRetries: 10})
+		srv.unavailable = 10
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c.sleep = func(context.Context, time.Duration) error {
+			cancel()
+
+			return context.Canceled
+		}
+
+		_, err := c.Get(ctx)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Len(t, srv.calls, 1)
+	})
+}
+
+func Test_Deadline(t *testing.T) {
+	c, srv := newTestClient(t, Config{Timeout: time.Minute})
+
+	start := time.Now()
+
+	_, err := c.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	_, err = c.Get(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, srv.deadlines, 2)
+	assert.WithinDuration(t, start.Add(time.Minute), srv.deadlines[0], 10*time.Second)
+	assert.WithinDuration(t, start.Add(time.Hour), srv.deadlines[1], 10*time.Second)
+}
+
+func Test_Metadata(t *testing.T) {
+	c, srv := newTestClient(t, Config{Token: "token", Tenant: "acme"})
+
+	_, err := c.Create(context.Background(), john)
+	require.NoError(t, err)
+
+	require.Len(t, srv.calls, 1)
+	assert.Equal(t, []string{"Bearer token"}, srv.calls[0].Get("authorization"))
+	assert.Equal(t, []string{"acme"}, srv.calls[0].Get("x-tenant-id"))
+	assert.Empty(t, srv.calls[0].Get("x-api-key"))
+
+	other := New(c.conn, Config{APIKey: "key"})
+
+	users, err := other.Get(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, users, "users are scoped to their tenant")
+	assert.Equal(t, []string{"key"}, srv.calls[1].Get("x-api-key"))
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+// fromStatus converts the status returned by the server back into the errors package type it was
+// built from. Errors of any other kind, including statuses carrying an unknown message, are
+// returned unchanged.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	msg := st.Message()
+
+	switch st.Code() {
+	case codes.NotFound:
+		var id int
+
+		if msg == (errors.UserNotFound{}).Error() {
+			return errors.UserNotFound{}
+		}
+
+		if _, scanErr := fmt.Sscanf(msg, "user with ID %d not found", &id); scanErr == nil {
+			return errors.UserNotFound{ID: id}
+		}
+	case codes.InvalidArgument:
+		if msg == (errors.IdempotencyKeyReused{}).Error() {
+			return errors.IdempotencyKeyReused{}
+		}
+
+		if params, ok := params(msg, "missing"); ok {
+			return errors.MissingParams{Params: params}
+		}
+
+		if params, ok := params(msg, "invalid"); ok {
+			return errors.InvalidParams{Params: params}
+		}
+
+		if reason, ok := strings.CutPrefix(msg, "invalid backup: "); ok {
+			return errors.InvalidBackup{Reason: reason}
+		}
+	case codes.AlreadyExists:
+		if msg == (errors.UserAlreadyExists{}).Error() {
+			return errors.UserAlreadyExists{}
+		}
+	case codes.Unauthenticated:
+		if msg == (errors.Unauthenticated{}).Error() {
+			return errors.Unauthenticated{}
+		}
+
+		if reason, ok := strings.CutPrefix(msg, "unauthenticated: "); ok {
+			return errors.Unauthenticated{Reason: reason}
+		}
+	case codes.PermissionDenied:
+		if msg == (errors.PermissionDenied{}).Error() {
+			return errors.PermissionDenied{}
+		}
+
+		if method, ok := strings.CutPrefix(msg, "permission denied for "); ok {
+			return errors.PermissionDenied{Method: method}
+		}
+
+		if msg == (errors.TenantMismatch{}).Error() {
+			return errors.TenantMismatch{}
+		}
+
+		if tenant, ok := strings.CutPrefix(msg, "tenant "); ok {
+			if tenant, ok = strings.CutSuffix(tenant, " not permitted for caller"); ok {
+				return errors.TenantMismatch{Tenant: tenant}
+			}
+		}
+	case codes.ResourceExhausted:
+		rateLimited := errors.RateLimited{}
+
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				rateLimited.RetryAfter = info.GetRetryDelay().AsDuration()
+			}
+		}
+
+		return rateLimited
+	case codes.FailedPrecondition:
+		var users int
+
+		if msg == (errors.StoreNotEmpty{}).Error() {
+			return errors.StoreNotEmpty{}
+		}
+
+		if _, scanErr := fmt.Sscanf(msg, "store is not empty: it holds %d users", &users); scanErr == nil {
+			return errors.StoreNotEmpty{Users: users}
+		}
+	}
+
+	return err
+}
+
+// params parses the message of errors.MissingParams or errors.InvalidParams, whose messages start
+// with kind.
+func params(msg, kind string) ([]string, bool) {
+	if msg == kind+" params" {
+		return nil, true
+	}
+
+	for _, prefix := range []string{kind + " param: ", kind + " params: "} {
+		if list, ok := strings.CutPrefix(msg, prefix); ok {
+			return strings.Split(list, ", "), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+func Test_fromStatus(t *testing.T) {
+	rateLimited, err := status.New(codes.ResourceExhausted, "rate limit exceeded, retry after 2s").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)})
+	require.NoError(t, err)
+
+	unknown := status.Error(codes.NotFound, "no such route")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Nil", nil, nil},
+		{"Not a status", context.Canceled, context.Canceled},
+		{"User not found", status.Error(codes.NotFound, "user with ID 3 not found"), errors.UserNotFound{ID: 3}},
+		{"User not found without ID", status.Error(codes.NotFound, "user not found"), errors.UserNotFound{}},
+		{"Unknown message", unknown, unknown},
+		{"Missing param", status.Error(codes.InvalidArgument, "missing param: fname"), errors.MissingParams{Params: []string{"fname"}}},
+		{"Missing params", status.Error(codes.InvalidArgument, "missing params: city, phone"), errors.MissingParams{Params: []string{"city", "phone"}}},
+		{"Invalid params", status.Error(codes.InvalidArgument, "invalid params: phone, height"), errors.InvalidParams{Params: []string{"phone", "height"}}},
+		{"Invalid params without list", status.Error(codes.InvalidArgument, "invalid params"), errors.InvalidParams{}},
+		{"Invalid backup", status.Error(codes.InvalidArgument, "invalid backup: empty file"), errors.InvalidBackup{Reason: "empty file"}},
+		{"Idempotency key reused", status.Error(codes.InvalidArgument, "idempotency key reused with a different request"), errors.IdempotencyKeyReused{}},
+		{"User already exists", status.Error(codes.AlreadyExists, "user already exists with given combination"), errors.UserAlreadyExists{}},
+		{"Unauthenticated", status.Error(codes.Unauthenticated, "unauthenticated"), errors.Unauthenticated{}},
+		{"Unauthenticated with reason", status.Error(codes.Unauthenticated, "unauthenticated: invalid token"), errors.Unauthenticated{Reason: "invalid token"}},
+		{"Permission denied", status.Error(codes.PermissionDenied, "permission denied for /user.UserService/Delete"), errors.PermissionDenied{Method: "/user.UserService/Delete"}},
+		{"Tenant mismatch", status.Error(codes.PermissionDenied, "tenant acme not permitted for caller"), errors.TenantMismatch{Tenant: "acme"}},
+		{"Rate limited", rateLimited.Err(), errors.RateLimited{RetryAfter: 2 * time.Second}},
+		{"Rate limited without details", status.Error(codes.ResourceExhausted, "rate limit exceeded"), errors.RateLimited{}},
+		{"Store not empty", status.Error(codes.FailedPrecondition, "store is not empty: it holds 4 users"), errors.StoreNotEmpty{Users: 4}},
+		{"Unavailable", status.Error(codes.Unavailable, "connection reset"), status.Error(codes.Unavailable, "connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fromStatus(tt.err))
+		})
+	}
+}
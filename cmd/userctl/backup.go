@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+func newBackupCmd(global *globalOptions) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write a backup file of the users of the tenant",
+		Long: `Write a backup file of the users of the tenant. The snapshot is consistent and taken without
+stopping the server; the file is versioned and checksummed, and is loaded back with restore.`,
+		Example: `  userctl backup -o users.backup
+  userctl backup --tenant acme > acme.backup`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackup(cmd, global, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "output file, - for standard output")
+
+	return cmd
+}
+
+func runBackup(cmd *cobra.Command, global *globalOptions, output string) error {
+	client, closeConn, err := global.dial()
+	if err != nil {
+		return err
+	}
+
+	defer closeConn()
+
+	stream, err := client.Backup(global.outgoing(cmd.Context()), &pb.BackupRequest{})
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		return receiveChunks(stream.Recv, cmd.OutOrStdout())
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+
+	err = receiveChunks(stream.Recv, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		// A partial backup cannot be restored, so it is not left behind.
+		_ = os.Remove(output)
+	}
+
+	return err
+}
+
+func newRestoreCmd(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Load a backup file into the tenant",
+		Long: `Load a backup file into the tenant, which must hold no users. The file is checked against its
+checksum first, and nothing is loaded unless it is valid. Use - to read standard input.`,
+		Example: `  userctl restore users.backup
+  userctl restore --tenant acme - < acme.backup`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestore(cmd, global, args[0])
+		},
+	}
+}
+
+func runRestore(cmd *cobra.Command, global *globalOptions, file string) error {
+	in := cmd.InOrStdin()
+
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		in = f
+	}
+
+	client, closeConn, err := global.dial()
+	if err != nil {
+		return err
+	}
+
+	defer closeConn()
+
+	stream, err := client.Restore(global.outgoing(cmd.Context()))
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+
+	for {
+		n, readErr := in.Read(buf)
+
+		if n > 0 {
+			// A send error means the server ended the call; its status comes with CloseAndRecv.
+			if err := stream.Send(&pb.RestoreRequest{Data: buf[:n]}); err != nil {
+				break
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "restored %d users of tenant %q backed up at %s, last ID %d\n",
+		summary.GetUsers(), summary.GetTenant(), summary.GetCreatedAt(), summary.GetLastInsertedId())
+
+	return nil
+}
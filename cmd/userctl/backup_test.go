@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+func Test_BackupRestore(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 3)
+
+	require.NoError(t, svc.Delete(context.Background(), 3))
+
+	file := filepath.Join(t.TempDir(), "users.backup")
+
+	out, err := run(t, opts, "", "backup", "-o", file)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	out, err = run(t, opts, "", "restore", "--tenant", "acme", file)
+	require.NoError(t, err)
+	assert.Regexp(t, `^restored 2 users of tenant "default" backed up at \S+, last ID 3\n$`, out)
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	assert.Equal(t, svc.Get(context.Background()), svc.Get(acme))
+
+	_, err = run(t, opts, "", "restore", file)
+	assert.ErrorContains(t, err, "store is not empty: it holds 2 users")
+}
+
+func Test_RestoreCorruptFile(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 1)
+
+	backup, err := run(t, opts, "", "backup")
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "users.backup")
+	require.NoError(t, os.WriteFile(file, []byte(backup[:len(backup)-10]), 0o600))
+
+	_, err = run(t, opts, "", "restore", "--tenant", "acme", file)
+	assert.ErrorContains(t, err, "invalid backup: malformed file")
+
+	_, err = run(t, opts, backup, "restore", "--tenant", "acme", "-")
+	assert.NoError(t, err)
+}
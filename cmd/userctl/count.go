@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newCountCmd(global *globalOptions) *cobra.Command {
+	var filters userFlags
+
+	cmd := &cobra.Command{
+		Use:   "count",
+		Short: "Print the number of users matching every given criterion",
+		Long: `Print the number of users matching every given criterion, or of every user when none is given,
+without listing them.`,
+		Example: `  userctl count
+  userctl count --city Boston`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.Count(global.outgoing(cmd.Context()), filters.filters(global.unit))
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), res.GetCount())
+
+			return err
+		},
+	}
+
+	filters.addFilters(cmd)
+
+	return cmd
+}
+
+func newExistsCmd(global *globalOptions) *cobra.Command {
+	var filters userFlags
+
+	cmd := &cobra.Command{
+		Use:   "exists",
+		Short: "Print whether a user matches every given criterion",
+		Long:  `Print true when a user matches every given criterion, and false otherwise.`,
+		Example: `  userctl exists --phone 1234567890
+  userctl exists --fname John --city Boston`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.Exists(global.outgoing(cmd.Context()), filters.filters(global.unit))
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), res.GetExists())
+
+			return err
+		},
+	}
+
+	filters.addFilters(cmd)
+
+	return cmd
+}
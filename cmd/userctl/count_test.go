@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Count(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 3)
+
+	out, err := run(t, opts, "", "count")
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", out)
+
+	out, err = run(t, opts, "", "count", "--city", "denver")
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", out)
+}
+
+func Test_Exists(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 2)
+
+	out, err := run(t, opts, "", "exists", "--phone", "0000000001")
+	require.NoError(t, err)
+	assert.Equal(t, "true\n", out)
+
+	out, err = run(t, opts, "", "exists", "--phone", "1234567890")
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", out)
+
+	out, err = run(t, opts, "", "exists", "--fname", "john", "--city", "denver")
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", out)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+type duplicatesOptions struct {
+	rules         []string
+	minSimilarity float64
+	format        string
+}
+
+// duplicateCluster is a cluster as printed in JSON and YAML.
+type duplicateCluster struct {
+	Rules []string      `json:"rules" yaml:"rules"`
+	Users []models.User `json:"users" yaml:"users"`
+}
+
+var duplicateRules = map[string]pb.FindDuplicatesRequest_Rule{
+	"phone":     pb.FindDuplicatesRequest_RULE_PHONE,
+	"name-city": pb.FindDuplicatesRequest_RULE_NAME_CITY,
+}
+
+// mergeRecord is a merge as printed in JSON and YAML.
+type mergeRecord struct {
+	Revision int64       `json:"revision" yaml:"revision"`
+	MergedAt string      `json:"merged_at" yaml:"merged_at"`
+	Survivor models.User `json:"survivor" yaml:"survivor"`
+	Merged   models.User `json:"merged_user" yaml:"merged_user"`
+	Result   models.User `json:"result" yaml:"result"`
+}
+
+// mergeFields lists the fields the --take flag of merge accepts.
+var mergeFields = []string{"fname", "city", "phone", "height", "married"}
+
+func newDuplicatesCmd(global *globalOptions) *cobra.Command {
+	opts := &duplicatesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "duplicates",
+		Short: "List the clusters of users likely to be duplicates",
+		Long: `List the clusters of users likely to be duplicates of one another. The phone rule links users
+whose phone numbers end with the same ten digits, and the name-city rule links users of the same
+city with similar first names. Users linked through another user share its cluster. Every rule
+applies unless --rule is given.`,
+		Example: `  userctl duplicates
+  userctl duplicates --rule name-city --min-similarity 0.8 --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(opts.format); err != nil {
+				return err
+			}
+
+			req := &pb.FindDuplicatesRequest{MinSimilarity: opts.minSimilarity}
+
+			for _, name := range opts.rules {
+				rule, ok := duplicateRules[name]
+				if !ok {
+					return fmt.Errorf("unknown rule %q, use phone or name-city", name)
+				}
+
+				req.Rules = append(req.Rules, rule)
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.FindDuplicates(global.outgoing(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+
+			return printDuplicates(cmd.OutOrStdout(), opts.format, res.GetClusters())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.rules, "rule", nil, "rule linking users, phone or name-city, repeatable (default: both)")
+	flags.Float64Var(&opts.minSimilarity, "min-similarity", 0, "lowest similarity, from 0 to 1, of the first names linked by name-city (default 0.6)")
+	addFormatFlag(cmd, &opts.format)
+
+	_ = cmd.RegisterFlagCompletionFunc("rule", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"phone", "name-city"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func printDuplicates(w io.Writer, format string, clusters []*pb.DuplicateCluster) error {
+	list := make([]duplicateCluster, 0, len(clusters))
+
+	for _, c := range clusters {
+		cluster := duplicateCluster{Rules: make([]string, 0, len(c.GetRules())), Users: make([]models.User, 0, len(c.GetUsers()))}
+
+		for _, rule := range c.GetRules() {
+			for name, r := range duplicateRules {
+				if r == rule {
+					cluster.Rules = append(cluster.Rules, name)
+				}
+			}
+		}
+
+		for _, usr := range c.GetUsers() {
+			cluster.Users = append(cluster.Users, toUser(usr))
+		}
+
+		list = append(list, cluster)
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "CLUSTER\tRULES\tID\tFNAME\tCITY\tPHONE\tHEIGHT\tMARRIED")
+
+		for i, cluster := range list {
+			for _, usr := range cluster.Users {
+				fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%s\t%s\t%t\n", i+1, strings.Join(cluster.Rules, ","), usr.ID, usr.Fname,
+					usr.City, usr.Phone, strconv.FormatFloat(usr.Height, 'f', -1, 64), usr.Married)
+			}
+		}
+
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(list)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(list); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	return unknownFormat(format)
+}
+
+func newMergeCmd(global *globalOptions) *cobra.Command {
+	var (
+		take   []string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "merge SURVIVOR_ID MERGED_ID",
+		Short: "Merge a user into another one",
+		Long: `Merge the user MERGED_ID into the user SURVIVOR_ID, which keeps its ID, then delete MERGED_ID.
+Fields keep the value of SURVIVOR_ID, except for the fields given to --take, which take the value
+of MERGED_ID. The merge is recorded as a single change, which watchers and webhooks receive along
+with the deleted user.`,
+		Example: `  userctl merge 1 2
+  userctl merge 1 2 --take city,married`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeIDs(global),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+
+			req := &pb.MergeRequest{SurvivorId: ids[0], MergedId: ids[1]}
+
+			sources := map[string]*pb.MergeRequest_Source{
+				"fname":   &req.Fname,
+				"city":    &req.City,
+				"phone":   &req.Phone,
+				"height":  &req.Height,
+				"married": &req.Married,
+			}
+
+			for _, field := range take {
+				source, ok := sources[field]
+				if !ok {
+					return fmt.Errorf("unknown field %q, use %s", field, strings.Join(mergeFields, ", "))
+				}
+
+				*source = pb.MergeRequest_SOURCE_MERGED
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			usr, err := client.Merge(global.outgoing(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, true, usr)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&take, "take", nil, "fields taken from MERGED_ID, among "+strings.Join(mergeFields, ", "))
+	addFormatFlag(cmd, &format)
+
+	_ = cmd.RegisterFlagCompletionFunc("take", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return mergeFields, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func newMergesCmd(global *globalOptions) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "merges ID",
+		Short: "List the merges of a user",
+		Long: `List the merges user ID took part in, as the survivor or as the user merged, oldest first. The
+table shows the user merged and the survivor as it was after the merge; JSON and YAML also hold
+the survivor as it was before.`,
+		Example: `  userctl merges 1
+  userctl merges 4 --format json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeIDs(global),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.ListMerges(global.outgoing(cmd.Context()), &pb.ListMergesRequest{UserId: ids[0]})
+			if err != nil {
+				return err
+			}
+
+			return printMerges(cmd.OutOrStdout(), format, res.GetMerges())
+		},
+	}
+
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
+
+func printMerges(w io.Writer, format string, records []*pb.MergeRecord) error {
+	list := make([]mergeRecord, 0, len(records))
+
+	for _, record := range records {
+		list = append(list, mergeRecord{
+			Revision: record.GetRevision(),
+			MergedAt: record.GetMergedAt(),
+			Survivor: toUser(record.GetSurvivor()),
+			Merged:   toUser(record.GetMergedUser()),
+			Result:   toUser(record.GetResult()),
+		})
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "REVISION\tMERGED_AT\tMERGED_ID\tID\tFNAME\tCITY\tPHONE\tHEIGHT\tMARRIED")
+
+		for _, record := range list {
+			usr := record.Result
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%t\n", record.Revision, record.MergedAt, record.Merged.ID,
+				usr.ID, usr.Fname, usr.City, usr.Phone, strconv.FormatFloat(usr.Height, 'f', -1, 64), usr.Married)
+		}
+
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(list)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(list); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	return unknownFormat(format)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/utils"
+)
+
+func createDuplicates(t *testing.T, svc service.User) {
+	t.Helper()
+
+	for _, usr := range []struct{ fname, city, phone string }{
+		{"John", "Boston", "5550100000"},
+		{"Jon", "Boston", "5550100001"},
+		{"Jane", "Denver", "+1 555 010 0000"},
+		{"Alice", "Paris", "5550100003"},
+	} {
+		_, err := svc.Create(context.Background(), &models.UserRequest{
+			Fname: &usr.fname, City: &usr.city, Phone: &usr.phone, Height: utils.Float64Ptr(5.9), Married: utils.BoolPtr(false),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func Test_Duplicates(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createDuplicates(t, svc)
+
+	out, err := run(t, opts, "", "duplicates")
+	require.NoError(t, err)
+	assert.Equal(t, `CLUSTER  RULES            ID  FNAME  CITY    PHONE            HEIGHT  MARRIED
+1        phone,name-city  1   John   Boston  5550100000       5.9     false
+1        phone,name-city  2   Jon    Boston  5550100001       5.9     false
+1        phone,name-city  3   Jane   Denver  +1 555 010 0000  5.9     false
+`, out)
+
+	out, err = run(t, opts, "", "duplicates", "--rule", "name-city", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"rules":["name-city"],"users":[
+		{"id":1,"fname":"John","city":"Boston","phone":"5550100000","height":5.9,"married":false},
+		{"id":2,"fname":"Jon","city":"Boston","phone":"5550100001","height":5.9,"married":false}
+	]}]`, out)
+
+	out, err = run(t, opts, "", "duplicates", "--rule", "name-city", "--min-similarity", "0.9", "--format", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", out)
+
+	_, err = run(t, opts, "", "duplicates", "--rule", "height")
+	assert.EqualError(t, err, `unknown rule "height", use phone or name-city`)
+
+	_, err = run(t, opts, "", "duplicates", "--min-similarity", "2")
+	assert.ErrorContains(t, err, "invalid param: min_similarity")
+}
+
+func Test_Merge(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createDuplicates(t, svc)
+
+	out, err := run(t, opts, "", "merge", "1", "3", "--take", "city,phone", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"fname":"John","city":"Denver","phone":"+1 555 010 0000","height":5.9,"married":false}`, out)
+
+	_, err = svc.GetByID(context.Background(), 3)
+	assert.Error(t, err, "the merged user is deleted")
+
+	_, err = run(t, opts, "", "merge", "1", "3")
+	assert.ErrorContains(t, err, "user with ID 3 not found")
+
+	_, err = run(t, opts, "", "merge", "1", "1")
+	assert.ErrorContains(t, err, "invalid param: merged_id")
+
+	_, err = run(t, opts, "", "merge", "1", "2", "--take", "id")
+	assert.EqualError(t, err, `unknown field "id", use fname, city, phone, height, married`)
+}
+
+func Test_Merges(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createDuplicates(t, svc)
+
+	_, err := run(t, opts, "", "merge", "1", "2", "--take", "fname")
+	require.NoError(t, err)
+
+	out, err := run(t, opts, "", "merges", "2", "--format", "json")
+	require.NoError(t, err)
+
+	var records []mergeRecord
+
+	require.NoError(t, json.Unmarshal([]byte(out), &records))
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "John", records[0].Survivor.Fname)
+		assert.Equal(t, models.User{ID: 2, Fname: "Jon", City: "Boston", Phone: "5550100001", Height: 5.9}, records[0].Merged)
+		assert.Equal(t, models.User{ID: 1, Fname: "Jon", City: "Boston", Phone: "5550100000", Height: 5.9}, records[0].Result)
+	}
+
+	out, err = run(t, opts, "", "merges", "3")
+	require.NoError(t, err)
+	assert.Equal(t, "REVISION  MERGED_AT  MERGED_ID  ID  FNAME  CITY  PHONE  HEIGHT  MARRIED\n", out)
+
+	_, err = run(t, opts, "", "merges", "0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+type exportOptions struct {
+	format  string
+	columns []string
+	output  string
+	fname   string
+	city    string
+	phone   string
+	height  float64
+}
+
+func newExportCmd(global *globalOptions) *cobra.Command {
+	opts := &exportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write users to a CSV, NDJSON or columnar JSON file",
+		Long: `Write every user, or the users matching the filter flags, to a CSV, NDJSON or columnar JSON
+file. Columnar JSON holds one object per page of users, with an array of values per column.`,
+		Example: `  userctl export -o users.csv
+  userctl export --columns id,fname,phone --city Boston --format ndjson
+  userctl export --format columnar-json -o users.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(cmd, global, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "file format, csv, ndjson or columnar-json (default: guessed from the output file extension, else csv)")
+	flags.StringSliceVar(&opts.columns, "columns", nil, "exported columns, in order, among id, fname, city, phone, height and married (default: all)")
+	flags.StringVarP(&opts.output, "output", "o", "-", "output file, - for standard output")
+	flags.StringVar(&opts.fname, "fname", "", "only export users with this first name")
+	flags.StringVar(&opts.city, "city", "", "only export users living in this city")
+	flags.StringVar(&opts.phone, "phone", "", "only export users with this phone number")
+	flags.Float64Var(&opts.height, "height", 0, "only export users of this height")
+
+	return cmd
+}
+
+func exportFormat(format, file string) (pb.Format, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".ndjson", ".jsonl":
+			format = "ndjson"
+		case ".json":
+			format = "columnar-json"
+		default:
+			format = "csv"
+		}
+	}
+
+	switch format {
+	case "csv":
+		return pb.Format_FORMAT_CSV, nil
+	case "ndjson", "jsonl":
+		return pb.Format_FORMAT_NDJSON, nil
+	case "columnar-json":
+		return pb.Format_FORMAT_COLUMNAR_JSON, nil
+	}
+
+	return pb.Format_FORMAT_UNSPECIFIED, fmt.Errorf("unknown format %q, use csv, ndjson or columnar-json", format)
+}
+
+func runExport(cmd *cobra.Command, global *globalOptions, opts *exportOptions) error {
+	format, err := exportFormat(opts.format, opts.output)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ExportRequest{Format: format, Columns: opts.columns}
+
+	flags := cmd.Flags()
+	if flags.Changed("fname") || flags.Changed("city") || flags.Changed("phone") || flags.Changed("height") {
+		req.Filters = &pb.Filters{Fname: opts.fname, City: opts.city, Phone: opts.phone, Height: opts.height, HeightUnit: global.unit}
+	}
+
+	client, closeConn, err := global.dial()
+	if err != nil {
+		return err
+	}
+
+	defer closeConn()
+
+	stream, err := client.Export(global.outgoing(cmd.Context()), req)
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "-" {
+		return receiveChunks(stream.Recv, cmd.OutOrStdout())
+	}
+
+	f, err := os.Create(opts.output)
+	if err != nil {
+		return err
+	}
+
+	err = receiveChunks(stream.Recv, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// receiveChunks writes the data of the messages returned by recv to out, until the end of the
+// stream.
+func receiveChunks[C interface{ GetData() []byte }](recv func() (C, error), out io.Writer) error {
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := out.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+)
+
+func createUsers(t *testing.T, svc service.User, count int) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		fname, city, phone, height, married := "John", "Boston", fmt.Sprintf("%010d", i), 170+float64(i)/1000, false
+		if i%2 == 1 {
+			fname, city = "Jane", "Denver"
+		}
+
+		_, err := svc.Create(context.Background(), &models.UserRequest{Fname: &fname, City: &city, Phone: &phone, Height: &height, Married: &married})
+		require.NoError(t, err)
+	}
+}
+
+func Test_Export(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 3)
+
+	out, err := run(t, opts, "", "export", "--columns", "id,fname,married")
+	require.NoError(t, err)
+	assert.Equal(t, "id,fname,married\n1,John,false\n2,Jane,false\n3,John,false\n", out)
+
+	out, err = run(t, opts, "", "export", "--format", "ndjson", "--columns", "id", "--city", "denver")
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":2}\n", out)
+}
+
+func Test_ExportToFile(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 1200)
+
+	file := filepath.Join(t.TempDir(), "users.ndjson")
+
+	out, err := run(t, opts, "", "export", "-o", file)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1200)
+	assert.True(t, strings.HasPrefix(lines[1199], `{"id":1200,"fname":"Jane","city":"Denver","phone":"0000001199","height":171.199`), lines[1199])
+}
+
+func Test_ExportInvalidColumn(t *testing.T) {
+	opts, _ := newTestServer(t)
+
+	_, err := run(t, opts, "", "export", "--columns", "id,email")
+	assert.ErrorContains(t, err, "invalid param: email")
+}
+
+func Test_exportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		want    pb.Format
+		wantErr bool
+	}{
+		{"Standard output", "", "-", pb.Format_FORMAT_CSV, false},
+		{"NDJSON extension", "", "users.NDJSON", pb.Format_FORMAT_NDJSON, false},
+		{"JSON extension", "", "users.json", pb.Format_FORMAT_COLUMNAR_JSON, false},
+		{"Flag wins", "csv", "users.json", pb.Format_FORMAT_CSV, false},
+		{"Unknown format", "xml", "users.xml", pb.Format_FORMAT_UNSPECIFIED, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exportFormat(tt.format, tt.file)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
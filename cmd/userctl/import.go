@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+// chunkSize is the amount of file data sent per stream message.
+const chunkSize = 32 * 1024
+
+type importOptions struct {
+	format    string
+	columns   map[string]string
+	dryRun    bool
+	batchSize int32
+}
+
+func newImportCmd(global *globalOptions) *cobra.Command {
+	opts := &importOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create or update users from a CSV or NDJSON file",
+		Long: `Create or update users from a CSV or NDJSON file. Users are matched by phone number:
+rows with a known phone number update that user, the others create a new one. Use - to read
+standard input.`,
+		Example: `  userctl import users.csv
+  userctl import --column "First Name=fname" --column Mobile=phone users.csv
+  userctl import --dry-run --format ndjson - < users.ndjson`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd, global, opts, args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "file format, csv or ndjson (default: guessed from the file extension)")
+	flags.StringToStringVar(&opts.columns, "column", nil, "maps a CSV header to a user field, e.g. Mobile=phone (repeatable)")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "validate the file and report the changes without applying them")
+	flags.Int32Var(&opts.batchSize, "batch-size", 0, "number of valid rows applied together, in a single store operation (default: server default)")
+
+	return cmd
+}
+
+func importFormat(format, file string) (pb.Format, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+
+	switch format {
+	case "csv":
+		return pb.Format_FORMAT_CSV, nil
+	case "ndjson", "jsonl":
+		return pb.Format_FORMAT_NDJSON, nil
+	}
+
+	return pb.Format_FORMAT_UNSPECIFIED, fmt.Errorf("cannot tell the format of %s, use --format csv or --format ndjson", file)
+}
+
+func runImport(cmd *cobra.Command, global *globalOptions, opts *importOptions, file string) error {
+	format, err := importFormat(opts.format, file)
+	if err != nil {
+		return err
+	}
+
+	in := cmd.InOrStdin()
+
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		in = f
+	}
+
+	client, closeConn, err := global.dial()
+	if err != nil {
+		return err
+	}
+
+	defer closeConn()
+
+	stream, err := client.Import(global.outgoing(cmd.Context()))
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ImportRequest{Options: &pb.ImportOptions{
+		Format:     format,
+		Columns:    opts.columns,
+		DryRun:     opts.dryRun,
+		BatchSize:  opts.batchSize,
+		HeightUnit: global.unit,
+	}}
+
+	buf := make([]byte, chunkSize)
+
+	for {
+		n, readErr := in.Read(buf)
+
+		if n > 0 || req.Options != nil {
+			req.Data = buf[:n]
+
+			// A send error means the server ended the call; its status comes with CloseAndRecv.
+			if err := stream.Send(req); err != nil {
+				break
+			}
+
+			req = &pb.ImportRequest{}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	printImportSummary(cmd.OutOrStdout(), summary)
+
+	if summary.GetFailed() > 0 {
+		return fmt.Errorf("%d of %d rows failed", summary.GetFailed(), summary.GetRows())
+	}
+
+	return nil
+}
+
+func printImportSummary(w io.Writer, summary *pb.ImportSummary) {
+	for _, importErr := range summary.GetErrors() {
+		fmt.Fprintf(w, "line %d: %s\n", importErr.GetLine(), importErr.GetMessage())
+	}
+
+	prefix := ""
+	if summary.GetDryRun() {
+		prefix = "dry run: "
+	}
+
+	fmt.Fprintf(w, "%s%d rows, %d created, %d updated, %d failed\n", prefix,
+		summary.GetRows(), summary.GetCreated(), summary.GetUpdated(), summary.GetFailed())
+}
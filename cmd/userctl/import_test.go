@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+func Test_Import(t *testing.T) {
+	opts, svc := newTestServer(t)
+
+	file := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(file, []byte("First Name,city,phone,height,married\nJohn,Boston,1234567890,5.9,false\n"), 0o600))
+
+	out, err := run(t, opts, "", "import", "--column", "First Name=fname", "--tenant", "acme", "--height-unit", "ft", file)
+	require.NoError(t, err)
+	assert.Equal(t, "1 rows, 1 created, 0 updated, 0 failed\n", out)
+
+	users := svc.Get(tenant.NewContext(context.Background(), "acme"))
+	require.Len(t, users, 1)
+	assert.Equal(t, "John", users[0].Fname)
+	assert.Equal(t, 179.832, users[0].Height)
+}
+
+func Test_ImportFromStdin(t *testing.T) {
+	opts, svc := newTestServer(t)
+
+	var ndjson strings.Builder
+	for i := 0; i < 400; i++ {
+		ndjson.WriteString(`{"fname":"John","city":"Boston","phone":"1234567890","height":180,"married":false}` + "\n")
+	}
+
+	ndjson.WriteString(`{"fname":"Jane","city":"Boston","phone":"12","height":165,"married":false}` + "\n")
+
+	require.Greater(t, ndjson.Len(), chunkSize, "the file must be streamed in several messages")
+
+	out, err := run(t, opts, ndjson.String(), "import", "--format", "ndjson", "-")
+	assert.EqualError(t, err, "1 of 401 rows failed")
+	assert.Contains(t, out, "line 401: invalid param: phone\n")
+	assert.Contains(t, out, "401 rows, 1 created, 399 updated, 1 failed\n")
+
+	assert.Len(t, svc.Get(context.Background()), 1)
+}
+
+func Test_ImportDryRun(t *testing.T) {
+	opts, svc := newTestServer(t)
+
+	out, err := run(t, opts, "fname,city,phone,height,married\nJohn,Boston,1234567890,180,false\n", "import", "--dry-run", "--format", "csv", "-")
+	require.NoError(t, err)
+	assert.Equal(t, "dry run: 1 rows, 1 created, 0 updated, 0 failed\n", out)
+
+	assert.Empty(t, svc.Get(context.Background()))
+}
+
+func Test_ImportInvalidHeader(t *testing.T) {
+	opts, _ := newTestServer(t)
+
+	_, err := run(t, opts, "fname,email\n", "import", "--format", "csv", "-")
+	assert.ErrorContains(t, err, `column "email" is not a user field`)
+}
+
+func Test_importFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		want    pb.Format
+		wantErr bool
+	}{
+		{"CSV extension", "", "users.CSV", pb.Format_FORMAT_CSV, false},
+		{"JSONL extension", "", "users.jsonl", pb.Format_FORMAT_NDJSON, false},
+		{"Flag wins", "ndjson", "users.csv", pb.Format_FORMAT_NDJSON, false},
+		{"Stdin without flag", "", "-", pb.Format_FORMAT_UNSPECIFIED, true},
+		{"Unknown format", "xml", "users.xml", pb.Format_FORMAT_UNSPECIFIED, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importFormat(tt.format, tt.file)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
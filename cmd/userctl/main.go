@@ -0,0 +1,236 @@
+// Command userctl is a command-line client for the UserService.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// globalOptions holds the flags shared by every command.
+type globalOptions struct {
+	addr   string
+	token  string
+	apiKey string
+	tenant string
+	tls    tlsOptions
+
+	// heightUnit and heightPrecision are the unit heights are given and shown in and the number
+	// of decimals they are shown with. unit holds heightUnit once parsed.
+	heightUnit      string
+	heightPrecision string
+	unit            pb.HeightUnit
+
+	// dialer replaces the network dialer in tests.
+	dialer func(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// tlsOptions configures the TLS connection to the server. TLS is used when enabled is set or any
+// file is given.
+type tlsOptions struct {
+	enabled    bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+}
+
+func (t tlsOptions) use() bool {
+	return t.enabled || t.caFile != "" || t.certFile != "" || t.keyFile != ""
+}
+
+func main() {
+	if err := newRootCmd(&globalOptions{}).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd(opts *globalOptions) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "userctl",
+		Short:        "Command-line client for the UserService",
+		SilenceUsage: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			unit, err := parseHeightUnit(opts.heightUnit)
+			opts.unit = unit
+
+			return err
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.addr, "addr", envOr("USERCTL_ADDR", "localhost:9000"), "server address (env USERCTL_ADDR)")
+	flags.StringVar(&opts.token, "token", os.Getenv("USERCTL_TOKEN"), "bearer token sent in the authorization metadata (env USERCTL_TOKEN)")
+	flags.StringVar(&opts.apiKey, "api-key", os.Getenv("USERCTL_API_KEY"), "API key sent in the x-api-key metadata (env USERCTL_API_KEY)")
+	flags.StringVar(&opts.tenant, "tenant", os.Getenv("USERCTL_TENANT"), "tenant sent in the x-tenant-id metadata (env USERCTL_TENANT)")
+	flags.StringVar(&opts.heightUnit, "height-unit", os.Getenv("USERCTL_HEIGHT_UNIT"), "unit heights are given and shown in, cm, m, ft or in (default: cm) (env USERCTL_HEIGHT_UNIT)")
+	flags.StringVar(&opts.heightPrecision, "height-precision", os.Getenv("USERCTL_HEIGHT_PRECISION"), "number of decimals heights are shown with (default: unrounded) (env USERCTL_HEIGHT_PRECISION)")
+
+	useTLS, _ := strconv.ParseBool(os.Getenv("USERCTL_TLS"))
+	flags.BoolVar(&opts.tls.enabled, "tls", useTLS, "connect over TLS, verifying the server against the system roots (env USERCTL_TLS)")
+	flags.StringVar(&opts.tls.caFile, "tls-ca-file", os.Getenv("USERCTL_TLS_CA_FILE"), "CA bundle verifying the server certificate, implies --tls (env USERCTL_TLS_CA_FILE)")
+	flags.StringVar(&opts.tls.certFile, "tls-cert-file", os.Getenv("USERCTL_TLS_CERT_FILE"), "client certificate for mutual TLS, implies --tls (env USERCTL_TLS_CERT_FILE)")
+	flags.StringVar(&opts.tls.keyFile, "tls-key-file", os.Getenv("USERCTL_TLS_KEY_FILE"), "client key for mutual TLS, implies --tls (env USERCTL_TLS_KEY_FILE)")
+	flags.StringVar(&opts.tls.serverName, "tls-server-name", os.Getenv("USERCTL_TLS_SERVER_NAME"), "name checked against the server certificate (default: host of --addr) (env USERCTL_TLS_SERVER_NAME)")
+
+	_ = root.MarkPersistentFlagFilename("tls-ca-file")
+	_ = root.MarkPersistentFlagFilename("tls-cert-file")
+	_ = root.MarkPersistentFlagFilename("tls-key-file")
+
+	_ = root.RegisterFlagCompletionFunc("height-unit", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return heightUnits, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	root.AddCommand(newCreateCmd(opts))
+	root.AddCommand(newGetCmd(opts))
+	root.AddCommand(newListCmd(opts))
+	root.AddCommand(newUpdateCmd(opts))
+	root.AddCommand(newDeleteCmd(opts))
+	root.AddCommand(newSearchCmd(opts))
+	root.AddCommand(newCountCmd(opts))
+	root.AddCommand(newExistsCmd(opts))
+	root.AddCommand(newSuggestCmd(opts))
+	root.AddCommand(newStatsCmd(opts))
+	root.AddCommand(newDuplicatesCmd(opts))
+	root.AddCommand(newMergeCmd(opts))
+	root.AddCommand(newMergesCmd(opts))
+
+	root.AddCommand(newImportCmd(opts))
+	root.AddCommand(newExportCmd(opts))
+	root.AddCommand(newBackupCmd(opts))
+	root.AddCommand(newRestoreCmd(opts))
+	root.AddCommand(newWatchCmd(opts))
+
+	return root
+}
+
+// heightUnits lists the values of the --height-unit flag.
+var heightUnits = []string{"cm", "m", "ft", "in"}
+
+func parseHeightUnit(name string) (pb.HeightUnit, error) {
+	if name == "" {
+		return pb.HeightUnit_HEIGHT_UNIT_UNSPECIFIED, nil
+	}
+
+	unit, ok := models.ParseHeightUnit(name)
+	if !ok {
+		return pb.HeightUnit_HEIGHT_UNIT_UNSPECIFIED, fmt.Errorf("unknown height unit %q, use cm, m, ft or in", name)
+	}
+
+	return pb.HeightUnit(unit), nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// dial connects to the server. The returned func closes the connection.
+func (o *globalOptions) dial() (pb.UserServiceClient, func() error, error) {
+	creds, err := o.tls.credentials()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	if o.dialer != nil {
+		dialOpts = append(dialOpts, grpc.WithContextDialer(o.dialer))
+	}
+
+	conn, err := grpc.NewClient(o.addr, dialOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pb.NewUserServiceClient(conn), conn.Close, nil
+}
+
+func (t tlsOptions) credentials() (credentials.TransportCredentials, error) {
+	if !t.use() {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: t.serverName}
+
+	if t.caFile != "" {
+		data, err := os.ReadFile(t.caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", t.caFile)
+		}
+	}
+
+	if t.certFile != "" || t.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.certFile, t.keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// outgoing attaches the credentials, tenant and height format to the metadata of calls made with
+// ctx.
+func (o *globalOptions) outgoing(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, o.metadata(true)...)
+}
+
+// outgoingCentimeters is outgoing without the height format, so heights are returned as they are
+// stored, in centimeters.
+func (o *globalOptions) outgoingCentimeters(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, o.metadata(false)...)
+}
+
+func (o *globalOptions) metadata(heightFormat bool) []string {
+	var pairs []string
+
+	if o.token != "" {
+		pairs = append(pairs, "authorization", "Bearer "+o.token)
+	}
+
+	if o.apiKey != "" {
+		pairs = append(pairs, "x-api-key", o.apiKey)
+	}
+
+	if o.tenant != "" {
+		pairs = append(pairs, "x-tenant-id", o.tenant)
+	}
+
+	if !heightFormat {
+		return pairs
+	}
+
+	if o.heightUnit != "" {
+		pairs = append(pairs, "x-height-unit", o.heightUnit)
+	}
+
+	if o.heightPrecision != "" {
+		pairs = append(pairs, "x-height-precision", o.heightPrecision)
+	}
+
+	return pairs
+}
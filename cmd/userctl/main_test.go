@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	handlerUser "github.com/ssshekhu53/user-detail-management/handler/user"
+	"github.com/ssshekhu53/user-detail-management/interceptor"
+	"github.com/ssshekhu53/user-detail-management/service"
+	serviceUser "github.com/ssshekhu53/user-detail-management/service/user"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+)
+
+// newTestServer serves a UserService backed by an in-memory store over an in-process listener.
+func newTestServer(t *testing.T, serverOpts ...grpc.ServerOption) (*globalOptions, service.User) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	svc := serviceUser.New(storeUser.New())
+	tenantInterceptor := interceptor.NewTenantInterceptor()
+	unitsInterceptor := interceptor.NewUnitsInterceptor()
+
+	s := grpc.NewServer(append(serverOpts,
+		grpc.ChainUnaryInterceptor(tenantInterceptor.UnaryTenantInterceptor, unitsInterceptor.UnaryUnitsInterceptor),
+		grpc.ChainStreamInterceptor(tenantInterceptor.StreamTenantInterceptor, unitsInterceptor.StreamUnitsInterceptor),
+	)...)
+	pb.RegisterUserServiceServer(s, handlerUser.New(svc))
+
+	go func() { _ = s.Serve(lis) }()
+
+	t.Cleanup(s.Stop)
+
+	opts := &globalOptions{
+		addr: "passthrough:///bufnet",
+		dialer: func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		},
+	}
+
+	return opts, svc
+}
+
+// run executes userctl with args against the server opts points at.
+func run(t *testing.T, opts *globalOptions, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	root := newRootCmd(opts)
+	root.SetArgs(append(args, "--addr", opts.addr))
+	root.SetIn(bytes.NewBufferString(stdin))
+	root.SetOut(&out)
+	root.SetErr(&out)
+
+	err := root.Execute()
+
+	return out.String(), err
+}
+
+func Test_envOr(t *testing.T) {
+	t.Setenv("USERCTL_TEST_ENV", "")
+	require.Equal(t, "fallback", envOr("USERCTL_TEST_ENV", "fallback"))
+
+	t.Setenv("USERCTL_TEST_ENV", "value")
+	require.Equal(t, "value", envOr("USERCTL_TEST_ENV", "fallback"))
+}
+
+// writeTestCA writes a self-signed certificate for localhost to dir and returns the server TLS
+// config using it, along with the path of the certificate.
+func writeTestCA(t *testing.T, dir string) (*tls.Config, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, certFile
+}
+
+func Test_TLS(t *testing.T) {
+	serverTLS, caFile := writeTestCA(t, t.TempDir())
+
+	opts, svc := newTestServer(t, grpc.Creds(credentials.NewTLS(serverTLS)))
+	createUsers(t, svc, 1)
+
+	out, err := run(t, opts, "", "list", "--tls-ca-file", caFile, "--tls-server-name", "localhost")
+	require.NoError(t, err)
+	assert.Contains(t, out, "John")
+
+	_, err = run(t, opts, "", "list", "--tls-ca-file", caFile, "--tls-server-name", "example.com")
+	assert.ErrorContains(t, err, "certificate")
+
+	_, err = run(t, opts, "", "list")
+	assert.Error(t, err)
+
+	_, err = run(t, opts, "", "list", "--tls-ca-file", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, err, "no such file")
+}
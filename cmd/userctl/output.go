@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// outputFormats lists the values of the --format flag of the commands printing users.
+var outputFormats = []string{"table", "json", "yaml"}
+
+func addFormatFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(format, "format", "table", "output format, table, json or yaml")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return outputFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func toUser(usr *pb.User) models.User {
+	return models.User{
+		ID:      int(usr.GetId()),
+		Fname:   usr.GetFname(),
+		City:    usr.GetCity(),
+		Phone:   usr.GetPhone(),
+		Height:  usr.GetHeight(),
+		Married: usr.GetMarried(),
+	}
+}
+
+// printUsers writes users to w in format. A single user is written as an object rather than a
+// list in JSON and YAML.
+func printUsers(w io.Writer, format string, single bool, users ...*pb.User) error {
+	list := make([]models.User, 0, len(users))
+
+	for _, usr := range users {
+		list = append(list, toUser(usr))
+	}
+
+	var v any = list
+	if single && len(list) == 1 {
+		v = list[0]
+	}
+
+	switch format {
+	case "table":
+		return printTable(w, list)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	return unknownFormat(format)
+}
+
+// checkFormat rejects unknown output formats before any call is made.
+func checkFormat(format string) error {
+	if !slices.Contains(outputFormats, format) {
+		return unknownFormat(format)
+	}
+
+	return nil
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown format %q, use table, json or yaml", format)
+}
+
+func printTable(w io.Writer, users []models.User) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tFNAME\tCITY\tPHONE\tHEIGHT\tMARRIED")
+
+	for _, usr := range users {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%t\n", usr.ID, usr.Fname, usr.City, usr.Phone,
+			strconv.FormatFloat(usr.Height, 'f', -1, 64), usr.Married)
+	}
+
+	return tw.Flush()
+}
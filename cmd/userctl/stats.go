@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+type statsOptions struct {
+	filters userFlags
+	groupBy string
+	buckets int32
+	format  string
+}
+
+// statsGroup, heightStats and histogramBucket are statistics as printed in JSON and YAML.
+type statsGroup struct {
+	Key    string       `json:"key,omitempty" yaml:"key,omitempty"`
+	Count  int32        `json:"count" yaml:"count"`
+	Height *heightStats `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+type heightStats struct {
+	Min       float64           `json:"min" yaml:"min"`
+	Max       float64           `json:"max" yaml:"max"`
+	Avg       float64           `json:"avg" yaml:"avg"`
+	P50       float64           `json:"p50" yaml:"p50"`
+	P90       float64           `json:"p90" yaml:"p90"`
+	P95       float64           `json:"p95" yaml:"p95"`
+	P99       float64           `json:"p99" yaml:"p99"`
+	Histogram []histogramBucket `json:"histogram" yaml:"histogram"`
+}
+
+type histogramBucket struct {
+	LowerBound float64 `json:"lower_bound" yaml:"lower_bound"`
+	UpperBound float64 `json:"upper_bound" yaml:"upper_bound"`
+	Count      int32   `json:"count" yaml:"count"`
+}
+
+type stats struct {
+	Total  statsGroup   `json:"total" yaml:"total"`
+	Groups []statsGroup `json:"groups" yaml:"groups"`
+}
+
+var groupBys = map[string]pb.StatsRequest_GroupBy{
+	"":        pb.StatsRequest_GROUP_BY_NONE,
+	"city":    pb.StatsRequest_GROUP_BY_CITY,
+	"married": pb.StatsRequest_GROUP_BY_MARRIED,
+}
+
+func newStatsCmd(global *globalOptions) *cobra.Command {
+	opts := &statsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize the counts and heights of the users matching every given criterion",
+		Long: `Summarize the number of users matching every given criterion and the distribution of their
+heights, in total and, with --group-by, for each city or marital status. Cities differing only in
+case or accents form a single group. The table lists the summaries; JSON and YAML also hold the
+height histograms.`,
+		Example: `  userctl stats
+  userctl stats --group-by city --format json
+  userctl stats --city Boston --group-by married --buckets 5`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(opts.format); err != nil {
+				return err
+			}
+
+			groupBy, ok := groupBys[opts.groupBy]
+			if !ok {
+				return fmt.Errorf("unknown group %q, use city or married", opts.groupBy)
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.Stats(global.outgoing(cmd.Context()), &pb.StatsRequest{
+				Filters:          opts.filters.filters(global.unit),
+				GroupBy:          groupBy,
+				HistogramBuckets: opts.buckets,
+			})
+			if err != nil {
+				return err
+			}
+
+			return printStats(cmd.OutOrStdout(), opts.format, res)
+		},
+	}
+
+	opts.filters.addFilters(cmd)
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.groupBy, "group-by", "", "field to group the users by, city or married")
+	flags.Int32Var(&opts.buckets, "buckets", 0, "number of buckets of the height histograms, up to 100 (default 10)")
+	addFormatFlag(cmd, &opts.format)
+
+	_ = cmd.RegisterFlagCompletionFunc("group-by", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"city", "married"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func printStats(w io.Writer, format string, res *pb.StatsResponse) error {
+	s := stats{Total: toStatsGroup(res.GetTotal()), Groups: make([]statsGroup, 0, len(res.GetGroups()))}
+
+	for _, group := range res.GetGroups() {
+		s.Groups = append(s.Groups, toStatsGroup(group))
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "GROUP\tCOUNT\tMIN\tMAX\tAVG\tP50\tP90\tP95\tP99")
+
+		printStatsRow(tw, "(total)", s.Total)
+
+		for _, group := range s.Groups {
+			printStatsRow(tw, group.Key, group)
+		}
+
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(s)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	return unknownFormat(format)
+}
+
+func printStatsRow(w io.Writer, name string, group statsGroup) {
+	if group.Height == nil {
+		fmt.Fprintf(w, "%s\t%d\t-\t-\t-\t-\t-\t-\t-\n", name, group.Count)
+
+		return
+	}
+
+	h := group.Height
+	fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\n", name, group.Count, h.Min, h.Max, h.Avg, h.P50, h.P90,
+		h.P95, h.P99)
+}
+
+func toStatsGroup(group *pb.StatsGroup) statsGroup {
+	s := statsGroup{Key: group.GetKey(), Count: group.GetCount()}
+
+	height := group.GetHeight()
+	if height == nil {
+		return s
+	}
+
+	s.Height = &heightStats{
+		Min:       height.GetMin(),
+		Max:       height.GetMax(),
+		Avg:       height.GetAvg(),
+		P50:       height.GetP50(),
+		P90:       height.GetP90(),
+		P95:       height.GetP95(),
+		P99:       height.GetP99(),
+		Histogram: make([]histogramBucket, 0, len(height.GetHistogram())),
+	}
+
+	for _, bucket := range height.GetHistogram() {
+		s.Height.Histogram = append(s.Height.Histogram, histogramBucket{
+			LowerBound: bucket.GetLowerBound(),
+			UpperBound: bucket.GetUpperBound(),
+			Count:      bucket.GetCount(),
+		})
+	}
+
+	return s
+}
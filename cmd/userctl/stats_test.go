@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Stats(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 2)
+
+	out, err := run(t, opts, "", "stats", "--group-by", "city")
+	require.NoError(t, err)
+	assert.Equal(t, `GROUP    COUNT  MIN     MAX     AVG     P50     P90     P95     P99
+(total)  2      170.00  170.00  170.00  170.00  170.00  170.00  170.00
+Boston   1      170.00  170.00  170.00  170.00  170.00  170.00  170.00
+Denver   1      170.00  170.00  170.00  170.00  170.00  170.00  170.00
+`, out)
+
+	out, err = run(t, opts, "", "stats", "--city", "denver", "--buckets", "1", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":{"count":1,"height":{"min":170.001,"max":170.001,"avg":170.001,"p50":170.001,"p90":170.001,"p95":170.001,"p99":170.001,
+		"histogram":[{"lower_bound":170.001,"upper_bound":170.001,"count":1}]}},"groups":[]}`, out)
+
+	out, err = run(t, opts, "", "stats", "--fname", "nobody", "--format", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `total:
+  count: 0
+groups: []
+`, out)
+
+	out, err = run(t, opts, "", "stats", "--fname", "nobody")
+	require.NoError(t, err)
+	assert.Equal(t, `GROUP    COUNT  MIN  MAX  AVG  P50  P90  P95  P99
+(total)  0      -    -    -    -    -    -    -
+`, out)
+
+	_, err = run(t, opts, "", "stats", "--group-by", "phone")
+	assert.EqualError(t, err, `unknown group "phone", use city or married`)
+
+	_, err = run(t, opts, "", "stats", "--buckets", "500")
+	assert.ErrorContains(t, err, "invalid param: histogram_buckets")
+}
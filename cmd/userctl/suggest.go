@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+type suggestOptions struct {
+	fields []string
+	prefix bool
+	limit  int32
+	format string
+}
+
+// suggestion is a suggestion as printed in JSON and YAML.
+type suggestion struct {
+	Score float64     `json:"score" yaml:"score"`
+	Field string      `json:"field" yaml:"field"`
+	User  models.User `json:"user" yaml:"user"`
+}
+
+var suggestFields = map[string]pb.SuggestRequest_Field{
+	"fname": pb.SuggestRequest_FIELD_FNAME,
+	"city":  pb.SuggestRequest_FIELD_CITY,
+}
+
+func newSuggestCmd(global *globalOptions) *cobra.Command {
+	opts := &suggestOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "suggest TEXT",
+		Short: "List the users whose first name or city resembles a text",
+		Long: `List the users whose first name or city resembles TEXT, best matches first, so that misspelled
+names still find their users. With --prefix, only names and cities starting with TEXT match, as
+when completing what is being typed.`,
+		Example: `  userctl suggest jon
+  userctl suggest bos --prefix --field city -n 5`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(opts.format); err != nil {
+				return err
+			}
+
+			req := &pb.SuggestRequest{Text: args[0], Prefix: opts.prefix, Limit: opts.limit}
+
+			for _, name := range opts.fields {
+				field, ok := suggestFields[name]
+				if !ok {
+					return fmt.Errorf("unknown field %q, use fname or city", name)
+				}
+
+				req.Fields = append(req.Fields, field)
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			res, err := client.Suggest(global.outgoing(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+
+			return printSuggestions(cmd.OutOrStdout(), opts.format, res.GetSuggestions())
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.fields, "field", nil, "field to match, fname or city, repeatable (default: both)")
+	flags.BoolVar(&opts.prefix, "prefix", false, "only match names and cities starting with TEXT")
+	flags.Int32VarP(&opts.limit, "limit", "n", 0, "maximum number of suggestions, up to 100 (default 10)")
+	addFormatFlag(cmd, &opts.format)
+
+	_ = cmd.RegisterFlagCompletionFunc("field", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"fname", "city"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func printSuggestions(w io.Writer, format string, suggestions []*pb.Suggestion) error {
+	list := make([]suggestion, 0, len(suggestions))
+
+	for _, s := range suggestions {
+		var field string
+
+		for name, f := range suggestFields {
+			if f == s.GetField() {
+				field = name
+			}
+		}
+
+		list = append(list, suggestion{
+			Score: s.GetScore(),
+			Field: field,
+			User:  toUser(s.GetUser()),
+		})
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "SCORE\tFIELD\tID\tFNAME\tCITY\tPHONE\tHEIGHT\tMARRIED")
+
+		for _, s := range list {
+			fmt.Fprintf(tw, "%.2f\t%s\t%d\t%s\t%s\t%s\t%s\t%t\n", s.Score, s.Field, s.User.ID, s.User.Fname,
+				s.User.City, s.User.Phone, strconv.FormatFloat(s.User.Height, 'f', -1, 64), s.User.Married)
+		}
+
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(list)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(list); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	}
+
+	return unknownFormat(format)
+}
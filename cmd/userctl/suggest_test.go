@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Suggest(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 2)
+
+	out, err := run(t, opts, "", "suggest", "jhon")
+	require.NoError(t, err)
+	assert.Equal(t, `SCORE  FIELD  ID  FNAME  CITY    PHONE       HEIGHT  MARRIED
+0.40   fname  1   John   Boston  0000000000  170     false
+`, out)
+
+	out, err = run(t, opts, "", "suggest", "den", "--prefix", "--field", "city", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"score":0.5,"field":"city","user":{"id":2,"fname":"Jane","city":"Denver","phone":"0000000001","height":170.001,"married":false}}]`, out)
+
+	out, err = run(t, opts, "", "suggest", "j", "--prefix", "-n", "1", "--format", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, `- score: 0.25
+  field: fname
+  user:
+    id: 1
+    fname: John
+    city: Boston
+    phone: "0000000000"
+    height: 170
+    married: false
+`, out)
+
+	_, err = run(t, opts, "", "suggest", "jo", "--field", "phone")
+	assert.EqualError(t, err, `unknown field "phone", use fname or city`)
+
+	_, err = run(t, opts, "", "suggest", "jo", "-n", "500")
+	assert.ErrorContains(t, err, "invalid param: limit")
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+// userFlags holds the user fields set on the command line.
+type userFlags struct {
+	fname   string
+	city    string
+	phone   string
+	height  float64
+	married bool
+}
+
+func (u *userFlags) add(cmd *cobra.Command, verb string) {
+	flags := cmd.Flags()
+	flags.StringVar(&u.fname, "fname", "", verb+" first name")
+	flags.StringVar(&u.city, "city", "", verb+" city")
+	flags.StringVar(&u.phone, "phone", "", verb+" phone number")
+	flags.Float64Var(&u.height, "height", 0, verb+" height")
+	flags.BoolVar(&u.married, "married", false, verb+" marital status")
+}
+
+// addFilters adds the flags of the criteria users are filtered by.
+func (u *userFlags) addFilters(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&u.fname, "fname", "", "first name")
+	flags.StringVar(&u.city, "city", "", "city")
+	flags.StringVar(&u.phone, "phone", "", "phone number")
+	flags.Float64Var(&u.height, "height", 0, "height")
+}
+
+// filters returns the criteria set on the command line, the height being given in unit.
+func (u *userFlags) filters(unit pb.HeightUnit) *pb.Filters {
+	return &pb.Filters{Fname: u.fname, City: u.city, Phone: u.phone, Height: u.height, HeightUnit: unit}
+}
+
+func parseIDs(args []string) ([]int32, error) {
+	ids := make([]int32, 0, len(args))
+
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid user ID %q", arg)
+		}
+
+		ids = append(ids, int32(id))
+	}
+
+	return ids, nil
+}
+
+// completeIDs completes user IDs with the users of the tenant, described by their name and city.
+func completeIDs(global *globalOptions) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		client, closeConn, err := global.dial()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		defer closeConn()
+
+		users, err := client.Get(global.outgoing(cmd.Context()), &emptypb.Empty{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		completions := make([]string, 0, len(users.GetUsers()))
+
+		for _, usr := range users.GetUsers() {
+			id := strconv.Itoa(int(usr.GetId()))
+			if slices.Contains(args, id) {
+				continue
+			}
+
+			completions = append(completions, fmt.Sprintf("%s\t%s, %s", id, usr.GetFname(), usr.GetCity()))
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func newCreateCmd(global *globalOptions) *cobra.Command {
+	var (
+		usr    userFlags
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Example: `  userctl create --fname John --city "New York" --phone 1234567890 --height 180 --married
+  userctl create --fname Jane --city Denver --phone 0987654321 --height 5.5 --height-unit ft`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			created, err := client.Create(global.outgoing(cmd.Context()), &pb.UserRequest{
+				Fname:      usr.fname,
+				City:       usr.city,
+				Phone:      usr.phone,
+				Height:     usr.height,
+				Married:    usr.married,
+				HeightUnit: global.unit,
+			})
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, true, created)
+		},
+	}
+
+	usr.add(cmd, "user")
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
+
+func newGetCmd(global *globalOptions) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "get ID...",
+		Short: "Show users by ID",
+		Example: `  userctl get 1
+  userctl get 1 2 3 --format json`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeIDs(global),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			ctx := global.outgoing(cmd.Context())
+
+			if len(ids) == 1 {
+				usr, err := client.GetByID(ctx, &pb.UserID{Id: ids[0]})
+				if err != nil {
+					return err
+				}
+
+				return printUsers(cmd.OutOrStdout(), format, true, usr)
+			}
+
+			users, err := client.GetByIDs(ctx, &pb.UserIDs{Ids: ids})
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, false, users.GetUsers()...)
+		},
+	}
+
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
+
+func newListCmd(global *globalOptions) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List every user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			users, err := client.Get(global.outgoing(cmd.Context()), &emptypb.Empty{})
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, false, users.GetUsers()...)
+		},
+	}
+
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
+
+func newUpdateCmd(global *globalOptions) *cobra.Command {
+	var (
+		usr    userFlags
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the fields of a user",
+		Long: `Change the fields of a user. Fields without a flag keep their current value, which is read
+from the server first.`,
+		Example: `  userctl update 1 --city Boston
+  userctl update 1 --married=false`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeIDs(global),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			// The current height is read and sent back in centimeters, as it is stored, so that
+			// converting and rounding it to --height-unit and --height-precision cannot change it.
+			current, err := client.GetByID(global.outgoingCentimeters(cmd.Context()), &pb.UserID{Id: ids[0]})
+			if err != nil {
+				return err
+			}
+
+			req := &pb.UserUpdateRequest{
+				Id:         current.GetId(),
+				Fname:      current.GetFname(),
+				City:       current.GetCity(),
+				Phone:      current.GetPhone(),
+				Height:     current.GetHeight(),
+				Married:    current.GetMarried(),
+				HeightUnit: pb.HeightUnit_HEIGHT_UNIT_CENTIMETERS,
+			}
+
+			flags := cmd.Flags()
+
+			if flags.Changed("fname") {
+				req.Fname = usr.fname
+			}
+
+			if flags.Changed("city") {
+				req.City = usr.city
+			}
+
+			if flags.Changed("phone") {
+				req.Phone = usr.phone
+			}
+
+			if flags.Changed("height") {
+				req.Height, req.HeightUnit = usr.height, global.unit
+			}
+
+			if flags.Changed("married") {
+				req.Married = usr.married
+			}
+
+			updated, err := client.Update(global.outgoing(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, true, updated)
+		},
+	}
+
+	usr.add(cmd, "new")
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
+
+func newDeleteCmd(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete ID...",
+		Short:             "Delete users by ID",
+		Example:           `  userctl delete 1 2`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeIDs(global),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			ctx := global.outgoing(cmd.Context())
+
+			for _, id := range ids {
+				if _, err := client.Delete(ctx, &pb.UserID{Id: id}); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "deleted user %d\n", id)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newSearchCmd(global *globalOptions) *cobra.Command {
+	var (
+		filters userFlags
+		format  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "List the users matching every given criterion",
+		Long: `List the users matching every given criterion. First names and cities are compared without
+regard to case or accents.`,
+		Example: `  userctl search --city "New York"
+  userctl search --fname john --format yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			users, err := client.Search(global.outgoing(cmd.Context()), filters.filters(global.unit))
+			if err != nil {
+				return err
+			}
+
+			return printUsers(cmd.OutOrStdout(), format, false, users.GetUsers()...)
+		},
+	}
+
+	filters.addFilters(cmd)
+	addFormatFlag(cmd, &format)
+
+	return cmd
+}
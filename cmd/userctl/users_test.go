@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateGetUpdateDelete(t *testing.T) {
+	opts, svc := newTestServer(t)
+
+	out, err := run(t, opts, "", "create", "--fname", "John", "--city", "New York", "--phone", "1234567890", "--height", "5.9",
+		"--height-unit", "ft", "--height-precision", "2", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"fname":"John","city":"New York","phone":"1234567890","height":5.9,"married":false}`, out)
+
+	out, err = run(t, opts, "", "update", "1", "--city", "Boston", "--married", "--height-unit", "ft", "--height-precision", "2", "--format", "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "id: 1\nfname: John\ncity: Boston\nphone: \"1234567890\"\nheight: 5.9\nmarried: true\n", out)
+
+	out, err = run(t, opts, "", "get", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "ID  FNAME  CITY    PHONE       HEIGHT   MARRIED\n1   John   Boston  1234567890  179.832  true\n", out)
+
+	// Heights shown rounded are not sent back rounded when --height is not given.
+	_, err = run(t, opts, "", "update", "1", "--city", "Denver", "--height-unit", "m", "--height-precision", "0")
+	require.NoError(t, err)
+
+	usr, err := svc.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 179.832, usr.Height)
+
+	out, err = run(t, opts, "", "search", "--height", "1.79832", "--height-unit", "m", "--format", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"id": 1`)
+
+	out, err = run(t, opts, "", "delete", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "deleted user 1\n", out)
+
+	assert.Empty(t, svc.Get(context.Background()))
+
+	_, err = run(t, opts, "", "get", "1")
+	assert.ErrorContains(t, err, "NotFound")
+}
+
+func Test_ListSearch(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 3)
+
+	out, err := run(t, opts, "", "list", "--format", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"id":1,"fname":"John","city":"Boston","phone":"0000000000","height":170,"married":false},
+		{"id":2,"fname":"Jane","city":"Denver","phone":"0000000001","height":170.001,"married":false},
+		{"id":3,"fname":"John","city":"Boston","phone":"0000000002","height":170.002,"married":false}
+	]`, out)
+
+	out, err = run(t, opts, "", "search", "--city", "denver")
+	require.NoError(t, err)
+	assert.Equal(t, "ID  FNAME  CITY    PHONE       HEIGHT   MARRIED\n2   Jane   Denver  0000000001  170.001  false\n", out)
+
+	out, err = run(t, opts, "", "get", "3", "1", "--format", "yaml")
+	require.NoError(t, err)
+	assert.Contains(t, out, "- id: 1\n")
+	assert.Contains(t, out, "- id: 3\n")
+}
+
+func Test_UserCommandErrors(t *testing.T) {
+	opts, _ := newTestServer(t)
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"Invalid ID", []string{"get", "one"}, `invalid user ID "one"`},
+		{"Zero ID", []string{"delete", "0"}, `invalid user ID "0"`},
+		{"Unknown format", []string{"list", "--format", "xml"}, `unknown format "xml", use table, json or yaml`},
+		{"Missing fields", []string{"create", "--fname", "John"}, "missing params: city, phone, height"},
+		{"Implausible height", []string{"create", "--fname", "John", "--city", "Boston", "--phone", "1234567890", "--height", "5.9"}, "invalid param: height"},
+		{"Unknown height unit", []string{"list", "--height-unit", "cubits"}, `unknown height unit "cubits", use cm, m, ft or in`},
+		{"Invalid height precision", []string{"list", "--height-precision", "9"}, "invalid param: height_precision"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, opts, "", tt.args...)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+// complete returns the shell completions of the last of args.
+func complete(t *testing.T, opts *globalOptions, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	root := newRootCmd(opts)
+	root.SetArgs(append([]string{"__complete", "--addr", opts.addr}, args...))
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+
+	require.NoError(t, root.Execute())
+
+	return out.String()
+}
+
+func Test_CompleteIDs(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 2)
+
+	assert.Equal(t, "2\tJane, Denver\n:4\n", complete(t, opts, "delete", "1", ""))
+	assert.Equal(t, "table\njson\nyaml\n:4\n", complete(t, opts, "list", "--format", ""))
+}
+
+func Test_Completion(t *testing.T) {
+	opts, _ := newTestServer(t)
+
+	out, err := run(t, opts, "", "completion", "bash")
+	require.NoError(t, err)
+	assert.Contains(t, out, "__start_userctl")
+}
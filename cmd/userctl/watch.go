@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+type watchOptions struct {
+	afterRevision int64
+	maxEvents     int
+	fname         string
+	city          string
+	phone         string
+	height        float64
+}
+
+// watchEvent is the line printed for every change.
+type watchEvent struct {
+	Revision     int64        `json:"revision"`
+	Type         string       `json:"type"`
+	User         models.User  `json:"user"`
+	MergedUser   *models.User `json:"merged_user,omitempty"`
+	PreviousUser *models.User `json:"previous_user,omitempty"`
+	// RestoredUsers is set for restores, which carry no user: the users are to be listed again.
+	RestoredUsers int `json:"restored_users,omitempty"`
+}
+
+var watchEventTypes = map[pb.WatchEvent_Type]string{
+	pb.WatchEvent_TYPE_CREATED:  "created",
+	pb.WatchEvent_TYPE_UPDATED:  "updated",
+	pb.WatchEvent_TYPE_DELETED:  "deleted",
+	pb.WatchEvent_TYPE_MERGED:   "merged",
+	pb.WatchEvent_TYPE_RESTORED: "restored",
+}
+
+func newWatchCmd(global *globalOptions) *cobra.Command {
+	opts := &watchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print the changes made to users as they happen",
+		Long: `Print the changes made to every user, or to the users matching the filter flags, as they happen,
+one JSON object per line. Pass the revision of the last change printed to --after-revision to
+resume where a previous watch stopped.`,
+		Example: `  userctl watch
+  userctl watch --city Boston --after-revision 42`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &pb.WatchRequest{AfterRevision: opts.afterRevision}
+
+			flags := cmd.Flags()
+			if flags.Changed("fname") || flags.Changed("city") || flags.Changed("phone") || flags.Changed("height") {
+				req.Filters = &pb.Filters{Fname: opts.fname, City: opts.city, Phone: opts.phone, Height: opts.height, HeightUnit: global.unit}
+			}
+
+			client, closeConn, err := global.dial()
+			if err != nil {
+				return err
+			}
+
+			defer closeConn()
+
+			stream, err := client.Watch(global.outgoing(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+
+			for received := 0; opts.maxEvents <= 0 || received < opts.maxEvents; received++ {
+				event, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				line := watchEvent{
+					Revision:      event.GetRevision(),
+					Type:          watchEventTypes[event.GetType()],
+					User:          toUser(event.GetUser()),
+					RestoredUsers: int(event.GetRestoredUsers()),
+				}
+
+				if event.GetMergedUser() != nil {
+					merged := toUser(event.GetMergedUser())
+					line.MergedUser = &merged
+				}
+
+				if event.GetPreviousUser() != nil {
+					previous := toUser(event.GetPreviousUser())
+					line.PreviousUser = &previous
+				}
+
+				if err := enc.Encode(line); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Int64Var(&opts.afterRevision, "after-revision", 0, "print the changes following this revision (default: changes from now on)")
+	flags.IntVarP(&opts.maxEvents, "max-events", "n", 0, "exit after printing this many changes (default: never)")
+	flags.StringVar(&opts.fname, "fname", "", "only watch users with this first name")
+	flags.StringVar(&opts.city, "city", "", "only watch users living in this city")
+	flags.StringVar(&opts.phone, "phone", "", "only watch users with this phone number")
+	flags.Float64Var(&opts.height, "height", 0, "only watch users of this height")
+
+	return cmd
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Watch(t *testing.T) {
+	opts, svc := newTestServer(t)
+	createUsers(t, svc, 3)
+
+	require.NoError(t, svc.Delete(context.Background(), 2))
+
+	out, err := run(t, opts, "", "watch", "--after-revision", "1", "--city", "denver", "-n", "2")
+	require.NoError(t, err)
+	assert.Equal(t, `{"revision":2,"type":"created","user":{"id":2,"fname":"Jane","city":"Denver","phone":"0000000001","height":170.001,"married":false}}
+{"revision":4,"type":"deleted","user":{"id":2,"fname":"Jane","city":"Denver","phone":"0000000001","height":170.001,"married":false}}
+`, out)
+
+	_, err = run(t, opts, "", "watch", "--after-revision", "9")
+	assert.ErrorContains(t, err, "invalid param: after_revision")
+}
@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+// AmbiguousPhone is returned when an upsert by phone number matches more than one user.
+type AmbiguousPhone struct {
+	Matches int
+}
+
+func (a AmbiguousPhone) Error() string {
+	if a.Matches == 0 {
+		return "phone matches several users"
+	}
+
+	return fmt.Sprintf("phone matches %d users", a.Matches)
+}
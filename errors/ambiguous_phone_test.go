@@ -0,0 +1,30 @@
+package errors
+
+import "testing"
+
+func TestAmbiguousPhoneError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      AmbiguousPhone
+		expected string
+	}{
+		{
+			name:     "Matches unknown",
+			err:      AmbiguousPhone{},
+			expected: "phone matches several users",
+		},
+		{
+			name:     "Matches known",
+			err:      AmbiguousPhone{Matches: 3},
+			expected: "phone matches 3 users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
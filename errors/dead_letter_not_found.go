@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+// DeadLetterNotFound is returned when replaying a failed webhook delivery that is not held.
+type DeadLetterNotFound struct {
+	ID int64
+}
+
+func (d DeadLetterNotFound) Error() string {
+	if d.ID == 0 {
+		return "dead letter not found"
+	}
+
+	return fmt.Sprintf("dead letter with ID %d not found", d.ID)
+}
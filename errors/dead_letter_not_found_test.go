@@ -0,0 +1,30 @@
+package errors
+
+import "testing"
+
+func TestDeadLetterNotFoundError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      DeadLetterNotFound
+		expected string
+	}{
+		{
+			name:     "ID unknown",
+			err:      DeadLetterNotFound{},
+			expected: "dead letter not found",
+		},
+		{
+			name:     "ID known",
+			err:      DeadLetterNotFound{ID: 3},
+			expected: "dead letter with ID 3 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package errors
+
+// IdempotencyKeyReused is returned when an idempotency key comes with a request other than the
+// one it was first used for.
+type IdempotencyKeyReused struct{}
+
+func (i IdempotencyKeyReused) Error() string {
+	return "idempotency key reused with a different request"
+}
@@ -0,0 +1,11 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IdempotencyKeyReusedError(t *testing.T) {
+	assert.EqualError(t, IdempotencyKeyReused{}, "idempotency key reused with a different request")
+}
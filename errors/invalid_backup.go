@@ -0,0 +1,14 @@
+package errors
+
+// InvalidBackup is returned when a backup file cannot be restored.
+type InvalidBackup struct {
+	Reason string
+}
+
+func (i InvalidBackup) Error() string {
+	if i.Reason == "" {
+		return "invalid backup"
+	}
+
+	return "invalid backup: " + i.Reason
+}
@@ -0,0 +1,30 @@
+package errors
+
+import "testing"
+
+func TestInvalidBackupError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      InvalidBackup
+		expected string
+	}{
+		{
+			name:     "Without reason",
+			err:      InvalidBackup{},
+			expected: "invalid backup",
+		},
+		{
+			name:     "With reason",
+			err:      InvalidBackup{Reason: "checksum mismatch"},
+			expected: "invalid backup: checksum mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package errors
+
+import "fmt"
+
+// InvalidLine reports a line of an imported file that could not be applied.
+type InvalidLine struct {
+	Line   int
+	Reason string
+}
+
+func (i InvalidLine) Error() string {
+	if i.Line == 0 {
+		return i.Reason
+	}
+
+	return fmt.Sprintf("line %d: %s", i.Line, i.Reason)
+}
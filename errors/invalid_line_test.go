@@ -0,0 +1,30 @@
+package errors
+
+import "testing"
+
+func TestInvalidLineError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      InvalidLine
+		expected string
+	}{
+		{
+			name:     "Line unknown",
+			err:      InvalidLine{Reason: "unexpected EOF"},
+			expected: "unexpected EOF",
+		},
+		{
+			name:     "Line known",
+			err:      InvalidLine{Line: 7, Reason: "missing param: phone"},
+			expected: "line 7: missing param: phone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
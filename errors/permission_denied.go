@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type PermissionDenied struct {
+	Method string
+}
+
+func (p PermissionDenied) Error() string {
+	if p.Method == "" {
+		return "permission denied"
+	}
+
+	return fmt.Sprintf("permission denied for %s", p.Method)
+}
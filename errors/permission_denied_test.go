@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionDeniedError(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		wantErr string
+	}{
+		{
+			name:    "Without method",
+			method:  "",
+			wantErr: "permission denied",
+		},
+		{
+			name:    "With method",
+			method:  "/user.UserService/Delete",
+			wantErr: "permission denied for /user.UserService/Delete",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PermissionDenied{Method: tt.method}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
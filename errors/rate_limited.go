@@ -0,0 +1,18 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+type RateLimited struct {
+	RetryAfter time.Duration
+}
+
+func (r RateLimited) Error() string {
+	if r.RetryAfter <= 0 {
+		return "rate limit exceeded"
+	}
+
+	return fmt.Sprintf("rate limit exceeded, retry after %s", r.RetryAfter)
+}
@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RateLimitedError(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		wantErr    string
+	}{
+		{"Without retry after", 0, "rate limit exceeded"},
+		{"With retry after", 1500 * time.Millisecond, "rate limit exceeded, retry after 1.5s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, RateLimited{RetryAfter: tt.retryAfter}, tt.wantErr)
+		})
+	}
+}
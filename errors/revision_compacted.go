@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+// RevisionCompacted is returned when changes are asked after a revision older than the oldest
+// change still kept.
+type RevisionCompacted struct {
+	Revision int64
+	// Oldest is the oldest revision changes can still be asked after.
+	Oldest int64
+}
+
+func (r RevisionCompacted) Error() string {
+	return fmt.Sprintf("revision %d is compacted, the oldest revision to resume from is %d", r.Revision, r.Oldest)
+}
@@ -0,0 +1,25 @@
+package errors
+
+import "testing"
+
+func TestRevisionCompactedError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      RevisionCompacted
+		expected string
+	}{
+		{
+			name:     "Compacted",
+			err:      RevisionCompacted{Revision: 3, Oldest: 120},
+			expected: "revision 3 is compacted, the oldest revision to resume from is 120",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
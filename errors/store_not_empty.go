@@ -0,0 +1,16 @@
+package errors
+
+import "fmt"
+
+// StoreNotEmpty is returned when a backup is restored into a tenant that already holds users.
+type StoreNotEmpty struct {
+	Users int
+}
+
+func (s StoreNotEmpty) Error() string {
+	if s.Users == 0 {
+		return "store is not empty"
+	}
+
+	return fmt.Sprintf("store is not empty: it holds %d users", s.Users)
+}
@@ -0,0 +1,30 @@
+package errors
+
+import "testing"
+
+func TestStoreNotEmptyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      StoreNotEmpty
+		expected string
+	}{
+		{
+			name:     "Users unknown",
+			err:      StoreNotEmpty{},
+			expected: "store is not empty",
+		},
+		{
+			name:     "Users known",
+			err:      StoreNotEmpty{Users: 2},
+			expected: "store is not empty: it holds 2 users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
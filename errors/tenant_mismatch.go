@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type TenantMismatch struct {
+	Tenant string
+}
+
+func (t TenantMismatch) Error() string {
+	if t.Tenant == "" {
+		return "tenant not permitted for caller"
+	}
+
+	return fmt.Sprintf("tenant %s not permitted for caller", t.Tenant)
+}
@@ -0,0 +1,12 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TenantMismatchError(t *testing.T) {
+	assert.EqualError(t, TenantMismatch{}, "tenant not permitted for caller")
+	assert.EqualError(t, TenantMismatch{Tenant: "acme"}, "tenant acme not permitted for caller")
+}
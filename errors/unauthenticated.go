@@ -0,0 +1,15 @@
+package errors
+
+import "fmt"
+
+type Unauthenticated struct {
+	Reason string
+}
+
+func (u Unauthenticated) Error() string {
+	if u.Reason == "" {
+		return "unauthenticated"
+	}
+
+	return fmt.Sprintf("unauthenticated: %s", u.Reason)
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnauthenticatedError(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		wantErr string
+	}{
+		{
+			name:    "Without reason",
+			reason:  "",
+			wantErr: "unauthenticated",
+		},
+		{
+			name:    "With reason",
+			reason:  "invalid API key",
+			wantErr: "unauthenticated: invalid API key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unauthenticated{Reason: tt.reason}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"strconv"
+)
+
+const (
+	// ContentType is the content type of the messages produced by Broker.
+	ContentType = "application/x-protobuf; messageType=user.UserEvent"
+
+	ContentTypeHeader   = "content-type"
+	SchemaVersionHeader = "schema-version"
+	EventTypeHeader     = "event-type"
+	TenantHeader        = "tenant"
+)
+
+// Message is a message to produce on a message bus.
+type Message struct {
+	Topic string
+	// Key is the tenant and ID of the user, so that buses partitioning by key, such as Kafka,
+	// deliver the events of a user in order.
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Producer sends messages to a message bus. Adapting a client library takes a few lines: a Kafka
+// producer maps the message to a record of the same topic, key, value and headers; a NATS
+// connection publishes the value with the headers on a subject named after the topic. Produce is
+// given the messages in revision order and sends them in that order.
+type Producer interface {
+	Produce(ctx context.Context, msg Message) error
+}
+
+// Broker is a Publisher producing every event to a topic of a message bus, encoded as a UserEvent
+// protobuf message.
+type Broker struct {
+	producer Producer
+	topic    string
+}
+
+func NewBroker(producer Producer, topic string) *Broker {
+	return &Broker{producer: producer, topic: topic}
+}
+
+func (b *Broker) Publish(ctx context.Context, event Event) error {
+	value, err := Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.producer.Produce(ctx, Message{
+		Topic: b.topic,
+		Key:   []byte(event.Tenant + "/" + strconv.Itoa(event.User.ID)),
+		Value: value,
+		Headers: map[string]string{
+			ContentTypeHeader:   ContentType,
+			SchemaVersionHeader: strconv.Itoa(event.SchemaVersion),
+			EventTypeHeader:     event.Type,
+			TenantHeader:        event.Tenant,
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type producerFunc func(ctx context.Context, msg Message) error
+
+func (f producerFunc) Produce(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+func Test_Broker(t *testing.T) {
+	var produced []Message
+
+	b := NewBroker(producerFunc(func(_ context.Context, msg Message) error {
+		produced = append(produced, msg)
+
+		return nil
+	}), "users")
+
+	event := New(TypeUpdated, "acme", john)
+
+	require.NoError(t, b.Publish(context.Background(), event))
+	require.Len(t, produced, 1)
+
+	msg := produced[0]
+	assert.Equal(t, "users", msg.Topic)
+	assert.Equal(t, []byte("acme/1"), msg.Key)
+	assert.Equal(t, map[string]string{
+		"content-type":   "application/x-protobuf; messageType=user.UserEvent",
+		"schema-version": "2",
+		"event-type":     "user.updated",
+		"tenant":         "acme",
+	}, msg.Headers)
+
+	decoded, err := Unmarshal(msg.Value)
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, john, decoded.User)
+
+	failing := NewBroker(producerFunc(func(context.Context, Message) error {
+		return stdErrors.New("broker unreachable")
+	}), "users")
+
+	assert.EqualError(t, failing.Publish(context.Background(), event), "broker unreachable")
+}
@@ -0,0 +1,98 @@
+// Package events publishes the lifecycle events of users to message buses and sinks.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// SchemaVersion is the version of the event schema, the UserEvent protobuf message, produced by
+// this package. Version 2 holds heights in centimeters, whatever the unit they were given in.
+const SchemaVersion = 2
+
+const (
+	TypeCreated = "user.created"
+	TypeUpdated = "user.updated"
+	TypeDeleted = "user.deleted"
+	// TypeMerged is a user merged with another user, which is deleted.
+	TypeMerged = "user.merged"
+	// TypeRestored is the users of a tenant loaded from a backup at once. It carries no user, so
+	// consumers mirroring the users list them again.
+	TypeRestored = "users.restored"
+)
+
+// types maps the changes of the store to the types of the events announcing them.
+var types = map[store.EventType]string{
+	store.EventCreated:  TypeCreated,
+	store.EventUpdated:  TypeUpdated,
+	store.EventDeleted:  TypeDeleted,
+	store.EventMerged:   TypeMerged,
+	store.EventRestored: TypeRestored,
+}
+
+// Event is a change made to a user.
+type Event struct {
+	SchemaVersion int `json:"schema_version"`
+	// ID identifies the event, for consumers to drop duplicates.
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Tenant string `json:"tenant"`
+	// OccurredAt is the time of the change.
+	OccurredAt time.Time `json:"occurred_at"`
+	// Revision is the revision of the change in the store, increasing with every change of the
+	// tenant.
+	Revision int64 `json:"revision"`
+	// User is the user after the change, or before it for deletions.
+	User models.User `json:"user"`
+	// MergedUser is the user merged into User, as it was before being deleted, for merges.
+	MergedUser *models.User `json:"merged_user,omitempty"`
+	// RestoredUsers is the number of users loaded, for restores.
+	RestoredUsers int `json:"restored_users,omitempty"`
+}
+
+// New returns an event of the given type, made to usr in tenant now.
+func New(eventType, tenant string, usr models.User) Event {
+	id := make([]byte, 16)
+
+	_, _ = rand.Read(id)
+
+	return Event{
+		SchemaVersion: SchemaVersion,
+		ID:            hex.EncodeToString(id),
+		Type:          eventType,
+		Tenant:        tenant,
+		OccurredAt:    time.Now().UTC(),
+		User:          usr,
+	}
+}
+
+// FromChange returns the event announcing change, made to a user of tenant now.
+func FromChange(tenant string, change store.Event) Event {
+	event := New(types[change.Type], tenant, change.User)
+	event.Revision = change.Revision
+	event.MergedUser = change.Merged
+	event.RestoredUsers = change.Restored
+
+	return event
+}
+
+// Publisher publishes events once the changes they describe are made. Implementations keep the
+// order they are given the events in. NDJSON and Broker publish on the path of the caller; wrap
+// them in a Queue where Publish must not wait for the sink, as under the lock of the store.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Discard is a Publisher dropping every event.
+var Discard Publisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(context.Context, Event) error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+var john = models.User{ID: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, Married: true}
+
+func Test_New(t *testing.T) {
+	event := New(TypeCreated, "acme", john)
+
+	assert.Equal(t, SchemaVersion, event.SchemaVersion)
+	assert.Len(t, event.ID, 32)
+	assert.Equal(t, TypeCreated, event.Type)
+	assert.Equal(t, "acme", event.Tenant)
+	assert.Equal(t, john, event.User)
+	assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Minute)
+
+	assert.NotEqual(t, event.ID, New(TypeCreated, "acme", john).ID, "every event gets its own ID")
+}
+
+func Test_FromChange(t *testing.T) {
+	jon := models.User{ID: 2, Fname: "Jon", City: "Boston", Phone: "1234567891", Height: 5.8}
+
+	event := FromChange("acme", store.Event{Revision: 7, Type: store.EventMerged, User: john, Previous: &john, Merged: &jon})
+
+	assert.Equal(t, TypeMerged, event.Type)
+	assert.Equal(t, "acme", event.Tenant)
+	assert.Equal(t, int64(7), event.Revision)
+	assert.Equal(t, john, event.User)
+	assert.Equal(t, &jon, event.MergedUser)
+
+	event = FromChange("acme", store.Event{Revision: 8, Type: store.EventRestored, Restored: 3})
+
+	assert.Equal(t, TypeRestored, event.Type)
+	assert.Equal(t, models.User{}, event.User)
+	assert.Equal(t, 3, event.RestoredUsers)
+}
+
+func Test_Memory(t *testing.T) {
+	m := NewMemory()
+	created, deleted := New(TypeCreated, "acme", john), New(TypeDeleted, "acme", john)
+
+	assert.Empty(t, m.Events())
+
+	assert.NoError(t, m.Publish(context.Background(), created))
+	assert.NoError(t, m.Publish(context.Background(), deleted))
+
+	assert.Equal(t, []Event{created, deleted}, m.Events())
+}
+
+func Test_Discard(t *testing.T) {
+	assert.NoError(t, Discard.Publish(context.Background(), New(TypeCreated, "acme", john)))
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is a Publisher keeping the events in memory, for tests.
+type Memory struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(_ context.Context, event Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (m *Memory) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Event{}, m.events...)
+}
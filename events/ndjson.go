@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// NDJSON is a Publisher writing every event as a line of JSON.
+type NDJSON struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewNDJSON returns a Publisher writing the events to w.
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{w: w}
+}
+
+// OpenNDJSON returns a Publisher appending the events to the named file, which is created if
+// needed.
+func OpenNDJSON(name string) (*NDJSON, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("events: opening event file: %w", err)
+	}
+
+	return &NDJSON{w: f, closer: f}, nil
+}
+
+func (n *NDJSON) Publish(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Writing the line at once keeps lines whole when several processes append to the same file.
+	_, err = n.w.Write(append(line, '\n'))
+
+	return err
+}
+
+// Close closes the file opened by OpenNDJSON. It does nothing for publishers made by NewNDJSON.
+func (n *NDJSON) Close() error {
+	if n.closer == nil {
+		return nil
+	}
+
+	return n.closer.Close()
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	n := NewNDJSON(&buf)
+
+	event := Event{
+		SchemaVersion: SchemaVersion,
+		ID:            "0123456789abcdef",
+		Type:          TypeCreated,
+		Tenant:        "acme",
+		OccurredAt:    time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+		Revision:      1,
+		User:          john,
+	}
+
+	require.NoError(t, n.Publish(context.Background(), event))
+	require.NoError(t, n.Publish(context.Background(), event))
+	assert.NoError(t, n.Close())
+
+	line := `{"schema_version":2,"id":"0123456789abcdef","type":"user.created","tenant":"acme",` +
+		`"occurred_at":"2024-07-01T12:00:00Z","revision":1,` +
+		`"user":{"id":1,"fname":"John","city":"Boston","phone":"1234567890","height":5.9,"married":true}}` + "\n"
+
+	assert.Equal(t, line+line, buf.String())
+}
+
+func Test_OpenNDJSON(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "events.ndjson")
+
+	for _, eventType := range []string{TypeCreated, TypeDeleted} {
+		n, err := OpenNDJSON(name)
+		require.NoError(t, err)
+
+		require.NoError(t, n.Publish(context.Background(), New(eventType, "acme", john)))
+		require.NoError(t, n.Close())
+	}
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var types []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+		types = append(types, event.Type)
+	}
+
+	assert.Equal(t, []string{TypeCreated, TypeDeleted}, types, "events are appended")
+
+	_, err = OpenNDJSON(filepath.Join(t.TempDir(), "missing", "events.ndjson"))
+	assert.ErrorContains(t, err, "events: opening event file")
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+var protoTypes = map[string]grpc.UserEvent_Type{
+	TypeCreated:  grpc.UserEvent_TYPE_CREATED,
+	TypeUpdated:  grpc.UserEvent_TYPE_UPDATED,
+	TypeDeleted:  grpc.UserEvent_TYPE_DELETED,
+	TypeMerged:   grpc.UserEvent_TYPE_MERGED,
+	TypeRestored: grpc.UserEvent_TYPE_RESTORED,
+}
+
+// Marshal encodes event as a UserEvent protobuf message.
+func Marshal(event Event) ([]byte, error) {
+	msg := &grpc.UserEvent{
+		SchemaVersion: uint32(event.SchemaVersion),
+		Id:            event.ID,
+		Type:          protoTypes[event.Type],
+		Tenant:        event.Tenant,
+		OccurredAt:    event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		User:          userToProto(event.User),
+		Revision:      event.Revision,
+		RestoredUsers: int32(event.RestoredUsers),
+	}
+
+	if event.MergedUser != nil {
+		msg.MergedUser = userToProto(*event.MergedUser)
+	}
+
+	return proto.Marshal(msg)
+}
+
+// Unmarshal decodes a UserEvent protobuf message. It fails on schema versions newer than
+// SchemaVersion.
+func Unmarshal(b []byte) (Event, error) {
+	var msg grpc.UserEvent
+
+	if err := proto.Unmarshal(b, &msg); err != nil {
+		return Event{}, fmt.Errorf("events: decoding event: %w", err)
+	}
+
+	if msg.GetSchemaVersion() > SchemaVersion {
+		return Event{}, fmt.Errorf("events: unsupported schema version %d", msg.GetSchemaVersion())
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339Nano, msg.GetOccurredAt())
+	if err != nil {
+		return Event{}, fmt.Errorf("events: invalid occurred_at: %w", err)
+	}
+
+	event := Event{
+		SchemaVersion: int(msg.GetSchemaVersion()),
+		ID:            msg.GetId(),
+		Tenant:        msg.GetTenant(),
+		OccurredAt:    occurredAt,
+		User:          userFromProto(msg.GetUser()),
+		Revision:      msg.GetRevision(),
+		RestoredUsers: int(msg.GetRestoredUsers()),
+	}
+
+	if msg.GetMergedUser() != nil {
+		merged := userFromProto(msg.GetMergedUser())
+		event.MergedUser = &merged
+	}
+
+	for eventType, protoType := range protoTypes {
+		if protoType == msg.GetType() {
+			event.Type = eventType
+		}
+	}
+
+	return event, nil
+}
+
+func userToProto(usr models.User) *grpc.User {
+	return &grpc.User{
+		Id:      int32(usr.ID),
+		Fname:   usr.Fname,
+		City:    usr.City,
+		Phone:   usr.Phone,
+		Height:  usr.Height,
+		Married: usr.Married,
+	}
+}
+
+func userFromProto(usr *grpc.User) models.User {
+	return models.User{
+		ID:      int(usr.GetId()),
+		Fname:   usr.GetFname(),
+		City:    usr.GetCity(),
+		Phone:   usr.GetPhone(),
+		Height:  usr.GetHeight(),
+		Married: usr.GetMarried(),
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+func Test_MarshalUnmarshal(t *testing.T) {
+	for _, eventType := range []string{TypeCreated, TypeUpdated, TypeDeleted, TypeMerged, TypeRestored} {
+		t.Run(eventType, func(t *testing.T) {
+			event := New(eventType, "acme", john)
+			event.Revision = 7
+
+			if eventType == TypeMerged {
+				event.MergedUser = &models.User{ID: 2, Fname: "Jon", City: "Boston", Phone: "1234567891", Height: 5.8}
+			}
+
+			if eventType == TypeRestored {
+				event.User = models.User{}
+				event.RestoredUsers = 3
+			}
+
+			b, err := Marshal(event)
+			require.NoError(t, err)
+
+			decoded, err := Unmarshal(b)
+			require.NoError(t, err)
+			assert.True(t, event.OccurredAt.Equal(decoded.OccurredAt))
+
+			decoded.OccurredAt = event.OccurredAt
+			assert.Equal(t, event, decoded)
+		})
+	}
+}
+
+func Test_MarshalSchema(t *testing.T) {
+	event := Event{
+		SchemaVersion: SchemaVersion,
+		ID:            "0123456789abcdef",
+		Type:          TypeUpdated,
+		Tenant:        "acme",
+		OccurredAt:    time.Date(2024, 7, 1, 12, 0, 0, 500, time.UTC),
+		Revision:      42,
+		User:          john,
+	}
+
+	b, err := Marshal(event)
+	require.NoError(t, err)
+
+	var msg grpc.UserEvent
+
+	require.NoError(t, proto.Unmarshal(b, &msg))
+	assert.True(t, proto.Equal(&grpc.UserEvent{
+		SchemaVersion: 2,
+		Id:            "0123456789abcdef",
+		Type:          grpc.UserEvent_TYPE_UPDATED,
+		Tenant:        "acme",
+		OccurredAt:    "2024-07-01T12:00:00.0000005Z",
+		User:          &grpc.User{Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, Married: true},
+		Revision:      42,
+	}, &msg), "got %v", &msg)
+}
+
+func Test_UnmarshalErrors(t *testing.T) {
+	newer, err := proto.Marshal(&grpc.UserEvent{SchemaVersion: SchemaVersion + 1, OccurredAt: "2024-07-01T12:00:00Z"})
+	require.NoError(t, err)
+
+	badTime, err := proto.Marshal(&grpc.UserEvent{SchemaVersion: SchemaVersion, OccurredAt: "yesterday"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		b       []byte
+		wantErr string
+	}{
+		{"Not protobuf", []byte{0xff}, "events: decoding event"},
+		{"Newer schema", newer, "events: unsupported schema version 3"},
+		{"Invalid time", badTime, "events: invalid occurred_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal(tt.b)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/redact"
+)
+
+// DefaultQueueSize is the number of events a Queue holds while its publisher catches up.
+const DefaultQueueSize = 10000
+
+var (
+	// ErrQueueFull is returned by Queue.Publish when the queue holds as many events as it may.
+	ErrQueueFull = errors.New("events: queue full")
+	// ErrQueueClosed is returned by Queue.Publish once the queue is closed.
+	ErrQueueClosed = errors.New("events: queue closed")
+)
+
+// Queue is a Publisher handing the events over to another publisher from a goroutine of its own,
+// so that Publish returns at once however slow that publisher is. Events are published in the
+// order they are queued. Failing to publish one is logged at warn level.
+type Queue struct {
+	publisher Publisher
+	size      int
+
+	mu      sync.Mutex
+	pending []queued
+	closed  bool
+	// ready is signalled when events are queued or the queue is closed.
+	ready chan struct{}
+	done  chan struct{}
+}
+
+type queued struct {
+	ctx   context.Context
+	event Event
+}
+
+// NewQueue returns a Queue publishing to publisher, holding at most size events, or
+// DefaultQueueSize when size is not positive. Close must be called to publish the events left.
+func NewQueue(publisher Publisher, size int) *Queue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+
+	q := &Queue{
+		publisher: publisher,
+		size:      size,
+		ready:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	go q.run()
+
+	return q
+}
+
+// Publish queues event, failing with ErrQueueFull rather than waiting when the queue is full. The
+// event is published with the values of ctx, but is not cancelled with it.
+func (q *Queue) Publish(ctx context.Context, event Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	if len(q.pending) >= q.size {
+		return ErrQueueFull
+	}
+
+	q.pending = append(q.pending, queued{ctx: context.WithoutCancel(ctx), event: event})
+	q.signal()
+
+	return nil
+}
+
+// Close stops queueing events and returns once the events queued are published.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	q.closed = true
+	q.signal()
+	q.mu.Unlock()
+
+	<-q.done
+
+	return nil
+}
+
+// signal wakes up run. It must be called with q.mu held.
+func (q *Queue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+
+	for range q.ready {
+		for {
+			q.mu.Lock()
+			pending, closed := q.pending, q.closed
+			q.pending = nil
+			q.mu.Unlock()
+
+			if len(pending) == 0 {
+				if closed {
+					return
+				}
+
+				break
+			}
+
+			for _, item := range pending {
+				if err := q.publisher.Publish(item.ctx, item.event); err != nil {
+					logging.FromContext(item.ctx).Warn("event not published", "event_type", item.event.Type,
+						"user_id", item.event.User.ID, "error", redact.Text(err.Error()))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingPublisher publishes to a Memory once it is released, telling when it is first called.
+type blockingPublisher struct {
+	*Memory
+
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (b blockingPublisher) Publish(ctx context.Context, event Event) error {
+	select {
+	case b.called <- struct{}{}:
+	default:
+	}
+
+	<-b.release
+
+	return b.Memory.Publish(ctx, event)
+}
+
+func Test_Queue(t *testing.T) {
+	publisher := blockingPublisher{Memory: NewMemory(), called: make(chan struct{}, 1), release: make(chan struct{})}
+	q := NewQueue(publisher, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var want []Event
+
+	for i := 0; i < 4; i++ {
+		event := New(TypeCreated, "acme", john)
+		event.Revision = int64(i + 1)
+
+		require.NoError(t, q.Publish(ctx, event))
+
+		want = append(want, event)
+
+		if i == 0 {
+			// The publisher holds on to the first event, and the queue fills up behind it.
+			<-publisher.called
+		}
+	}
+
+	assert.ErrorIs(t, q.Publish(ctx, New(TypeDeleted, "acme", john)), ErrQueueFull)
+
+	cancel()
+	close(publisher.release)
+	require.NoError(t, q.Close())
+
+	assert.Equal(t, want, publisher.Events(), "events are published in order, though their context is cancelled")
+	assert.ErrorIs(t, q.Publish(context.Background(), New(TypeCreated, "acme", john)), ErrQueueClosed)
+}
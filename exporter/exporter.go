@@ -0,0 +1,285 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	// FormatColumnarJSON writes one JSON object per page of users, holding an array per column.
+	FormatColumnarJSON Format = "columnar-json"
+)
+
+// DefaultPageSize is the number of users read from the store at a time.
+const DefaultPageSize = 500
+
+// Columns lists the user fields that can be exported, in their default order.
+var Columns = []string{"id", "fname", "city", "phone", "height", "married"}
+
+type Options struct {
+	Format Format
+	// Columns are the exported fields, in order. Every field in Columns is exported when empty.
+	Columns []string
+	// Filters restricts the export to the matching users; every user is exported when nil.
+	Filters *models.Filters
+	// PageSize is the number of users read from the store at a time. It defaults to
+	// DefaultPageSize.
+	PageSize int
+	// HeightFormat is the unit and precision heights are written in; they are written as stored
+	// when it is the zero value.
+	HeightFormat models.HeightFormat
+}
+
+// pageWriter writes pages of users in a file format.
+type pageWriter func(w io.Writer, users []models.User) error
+
+// Export writes the users to w page by page, so that the whole directory is never held in memory.
+// Users created while the export runs may or may not be part of it. It returns the number of
+// exported users.
+func Export(ctx context.Context, w io.Writer, userService service.User, opts Options) (int, error) {
+	columns, err := validateColumns(opts.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+
+	var write pageWriter
+
+	switch opts.Format {
+	case FormatCSV:
+		write, err = csvWriter(w, columns)
+	case FormatNDJSON:
+		write = ndjsonWriter(columns)
+	case FormatColumnarJSON:
+		write = columnarWriter(columns)
+	default:
+		err = fmt.Errorf("unknown export format %q", opts.Format)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	exported, afterID := 0, 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		users := userService.Scan(ctx, opts.Filters, afterID, opts.PageSize)
+		if len(users) == 0 {
+			break
+		}
+
+		for i := range users {
+			users[i].Height = opts.HeightFormat.Convert(users[i].Height)
+		}
+
+		if err := write(w, users); err != nil {
+			return exported, err
+		}
+
+		exported += len(users)
+		afterID = users[len(users)-1].ID
+
+		if len(users) < opts.PageSize {
+			break
+		}
+	}
+
+	logging.FromContext(ctx).Info("export completed", "format", opts.Format, "users", exported)
+
+	return exported, nil
+}
+
+// validateColumns returns the exported columns, rejecting unknown and repeated ones.
+func validateColumns(columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return Columns, nil
+	}
+
+	seen := make(map[string]bool, len(columns))
+
+	var invalid []string
+
+	for _, column := range columns {
+		if seen[column] || !knownColumn(column) {
+			invalid = append(invalid, column)
+		}
+
+		seen[column] = true
+	}
+
+	if len(invalid) > 0 {
+		return nil, errors.InvalidParams{Params: invalid}
+	}
+
+	return columns, nil
+}
+
+func knownColumn(column string) bool {
+	for _, c := range Columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// value returns the field of usr held in column.
+func value(usr *models.User, column string) any {
+	switch column {
+	case "id":
+		return usr.ID
+	case "fname":
+		return usr.Fname
+	case "city":
+		return usr.City
+	case "phone":
+		return usr.Phone
+	case "height":
+		return usr.Height
+	case "married":
+		return usr.Married
+	}
+
+	return nil
+}
+
+// csvWriter writes the header row right away, then one record per user.
+func csvWriter(w io.Writer, columns []string) (pageWriter, error) {
+	if err := writeCSV(w, [][]string{columns}); err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer, users []models.User) error {
+		records := make([][]string, len(users))
+
+		for i := range users {
+			records[i] = make([]string, len(columns))
+
+			for j, column := range columns {
+				switch v := value(&users[i], column).(type) {
+				case int:
+					records[i][j] = strconv.Itoa(v)
+				case float64:
+					records[i][j] = strconv.FormatFloat(v, 'f', -1, 64)
+				case bool:
+					records[i][j] = strconv.FormatBool(v)
+				case string:
+					records[i][j] = v
+				}
+			}
+		}
+
+		return writeCSV(w, records)
+	}, nil
+}
+
+// writeCSV writes the records to w at once. csv.Writer is not used on w directly since it would
+// flush a buffered w on every call.
+func writeCSV(w io.Writer, records [][]string) error {
+	var buf bytes.Buffer
+
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// ndjsonWriter writes one object per user, with its keys in the order of columns.
+func ndjsonWriter(columns []string) pageWriter {
+	return func(w io.Writer, users []models.User) error {
+		var buf bytes.Buffer
+
+		for i := range users {
+			buf.WriteByte('{')
+
+			for j, column := range columns {
+				if j > 0 {
+					buf.WriteByte(',')
+				}
+
+				if err := writeMember(&buf, column, value(&users[i], column)); err != nil {
+					return err
+				}
+			}
+
+			buf.WriteString("}\n")
+		}
+
+		_, err := w.Write(buf.Bytes())
+
+		return err
+	}
+}
+
+// columnarWriter writes one object per page, holding the values of every column as an array.
+func columnarWriter(columns []string) pageWriter {
+	return func(w io.Writer, users []models.User) error {
+		var buf bytes.Buffer
+
+		buf.WriteByte('{')
+
+		for j, column := range columns {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+
+			values := make([]any, len(users))
+			for i := range users {
+				values[i] = value(&users[i], column)
+			}
+
+			if err := writeMember(&buf, column, values); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("}\n")
+
+		_, err := w.Write(buf.Bytes())
+
+		return err
+	}
+}
+
+func writeMember(buf *bytes.Buffer, key string, v any) error {
+	name, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(name)
+	buf.WriteByte(':')
+	buf.Write(data)
+
+	return nil
+}
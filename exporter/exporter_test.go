@@ -0,0 +1,157 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	serviceUser "github.com/ssshekhu53/user-detail-management/service/user"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+)
+
+func newService(t *testing.T) service.User {
+	t.Helper()
+
+	svc := serviceUser.New(storeUser.New())
+
+	users := []struct {
+		fname, city, phone string
+		height             float64
+		married            bool
+	}{
+		{"John", "New York", "1234567890", 180, false},
+		{"Jane", "Boston", "0987654321", 165, true},
+		{"Jim, Jr.", "New York", "5555555555", 185, false},
+	}
+
+	for _, u := range users {
+		fname, city, phone, height, married := u.fname, u.city, u.phone, u.height, u.married
+
+		_, err := svc.Create(context.Background(), &models.UserRequest{Fname: &fname, City: &city, Phone: &phone, Height: &height, Married: &married})
+		require.NoError(t, err)
+	}
+
+	return svc
+}
+
+func Test_Export(t *testing.T) {
+	newYork, one := "new york", 1
+
+	tests := []struct {
+		name      string
+		opts      Options
+		want      string
+		wantCount int
+	}{
+		{
+			name: "CSV with every column",
+			opts: Options{Format: FormatCSV},
+			want: `id,fname,city,phone,height,married
+1,John,New York,1234567890,180,false
+2,Jane,Boston,0987654321,165,true
+3,"Jim, Jr.",New York,5555555555,185,false
+`,
+			wantCount: 3,
+		},
+		{
+			name: "CSV with selected columns and filters",
+			opts: Options{Format: FormatCSV, Columns: []string{"phone", "id"}, Filters: &models.Filters{City: &newYork}},
+			want: `phone,id
+1234567890,1
+5555555555,3
+`,
+			wantCount: 2,
+		},
+		{
+			name: "NDJSON keeps the column order",
+			opts: Options{Format: FormatNDJSON, Columns: []string{"married", "fname"}},
+			want: `{"married":false,"fname":"John"}
+{"married":true,"fname":"Jane"}
+{"married":false,"fname":"Jim, Jr."}
+`,
+			wantCount: 3,
+		},
+		{
+			name: "Columnar JSON writes a line per page",
+			opts: Options{Format: FormatColumnarJSON, Columns: []string{"id", "height"}, PageSize: 2},
+			want: `{"id":[1,2],"height":[180,165]}
+{"id":[3],"height":[185]}
+`,
+			wantCount: 3,
+		},
+		{
+			name: "Heights in the requested format",
+			opts: Options{Format: FormatCSV, Columns: []string{"id", "height"}, HeightFormat: models.HeightFormat{Unit: models.HeightUnitFeet, Precision: &one}},
+			want: `id,height
+1,5.9
+2,5.4
+3,6.1
+`,
+			wantCount: 3,
+		},
+		{
+			name:      "No match writes the CSV header only",
+			opts:      Options{Format: FormatCSV, Columns: []string{"id"}, Filters: &models.Filters{Fname: &newYork}},
+			want:      "id\n",
+			wantCount: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			count, err := Export(context.Background(), &buf, newService(t), tc.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, buf.String())
+			assert.Equal(t, tc.wantCount, count)
+		})
+	}
+}
+
+func Test_ExportPages(t *testing.T) {
+	svc := newService(t)
+
+	// The page boundary falls on the last user, so the export needs an extra, empty page.
+	var buf bytes.Buffer
+
+	count, err := Export(context.Background(), &buf, svc, Options{Format: FormatNDJSON, Columns: []string{"id"}, PageSize: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}, strings.Fields(buf.String()))
+}
+
+func Test_ExportErrors(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts Options
+		err  error
+	}{
+		{"Unknown column", context.Background(), Options{Format: FormatCSV, Columns: []string{"id", "email"}}, errors.InvalidParams{Params: []string{"email"}}},
+		{"Repeated column", context.Background(), Options{Format: FormatCSV, Columns: []string{"id", "id"}}, errors.InvalidParams{Params: []string{"id"}}},
+		{"Canceled", canceled, Options{Format: FormatCSV}, context.Canceled},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Export(tc.ctx, &bytes.Buffer{}, newService(t), tc.opts)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+
+	_, err := Export(context.Background(), &bytes.Buffer{}, newService(t), Options{Format: "xml"})
+	assert.EqualError(t, err, `unknown export format "xml"`)
+}
@@ -0,0 +1,94 @@
+package user
+
+import (
+	"bufio"
+	"bytes"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/backup"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+// Backup streams a backup file of the users of the tenant. The snapshot is taken at once, so
+// the file is consistent even when users change while it is sent.
+func (u *user) Backup(_ *grpc.BackupRequest, stream grpc.UserService_BackupServer) error {
+	ctx := stream.Context()
+
+	snapshot := u.userService.Backup(ctx)
+
+	w := bufio.NewWriterSize(chunkWriter(func(data []byte) error {
+		return stream.Send(&grpc.BackupChunk{Data: data})
+	}), chunkSize)
+
+	err := backup.Write(w, backup.File{CreatedAt: time.Now(), Tenant: tenant.FromContext(ctx), Snapshot: snapshot})
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// Restore reads the backup file sent in the data of the stream messages and loads it into the
+// tenant, which must hold no users. Nothing is loaded unless the whole file is valid. The file is
+// held in memory, so files larger than the maximum backup size are rejected as soon as they
+// outgrow it.
+func (u *user) Restore(stream grpc.UserService_RestoreServer) error {
+	var data bytes.Buffer
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if data.Len()+len(req.GetData()) > u.maxBackupSize {
+			err := errors.InvalidBackup{Reason: fmt.Sprintf("file larger than %d bytes", u.maxBackupSize)}
+
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+
+		data.Write(req.GetData())
+	}
+
+	file, err := backup.Read(&data)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = u.userService.Restore(stream.Context(), file.Snapshot)
+	if err != nil {
+		var notEmptyErr errors.StoreNotEmpty
+		if stdErrors.As(err, &notEmptyErr) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return stream.SendAndClose(&grpc.RestoreSummary{
+		Users:          int32(len(file.Snapshot.Users)),
+		LastInsertedId: int32(file.Snapshot.LastInsertedID),
+		Tenant:         file.Tenant,
+		CreatedAt:      file.CreatedAt.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,175 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/backup"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+type mockBackupServer struct {
+	gogrpc.ServerStream
+
+	data bytes.Buffer
+}
+
+func (m *mockBackupServer) Context() context.Context {
+	return tenant.NewContext(context.Background(), "acme")
+}
+
+func (m *mockBackupServer) Send(chunk *grpc.BackupChunk) error {
+	m.data.Write(chunk.GetData())
+
+	return nil
+}
+
+type mockRestoreServer struct {
+	gogrpc.ServerStream
+
+	requests []*grpc.RestoreRequest
+	summary  *grpc.RestoreSummary
+}
+
+func (m *mockRestoreServer) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockRestoreServer) Recv() (*grpc.RestoreRequest, error) {
+	if len(m.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	req := m.requests[0]
+	m.requests = m.requests[1:]
+
+	return req, nil
+}
+
+func (m *mockRestoreServer) SendAndClose(summary *grpc.RestoreSummary) error {
+	m.summary = summary
+
+	return nil
+}
+
+func testSnapshot() store.Snapshot {
+	return store.Snapshot{
+		LastInsertedID: 3,
+		Users: []models.User{
+			{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180},
+			{ID: 3, Fname: "Jane", City: "Boston", Phone: "0987654321", Height: 167.64, Married: true},
+		},
+	}
+}
+
+func Test_Backup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	mockService.EXPECT().Backup(gomock.Any()).Return(testSnapshot())
+
+	stream := &mockBackupServer{}
+
+	require.NoError(t, handler.Backup(&grpc.BackupRequest{}, stream))
+
+	file, err := backup.Read(&stream.data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", file.Tenant)
+	assert.Equal(t, testSnapshot(), file.Snapshot)
+	assert.WithinDuration(t, time.Now(), file.CreatedAt, time.Minute)
+}
+
+func Test_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	var file bytes.Buffer
+
+	createdAt := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, backup.Write(&file, backup.File{CreatedAt: createdAt, Tenant: "acme", Snapshot: testSnapshot()}))
+
+	data := file.Bytes()
+	half := len(data) / 2
+
+	// The file is split across messages like a client streaming it would.
+	chunks := func() []*grpc.RestoreRequest {
+		return []*grpc.RestoreRequest{{Data: data[:half]}, {Data: data[half:]}}
+	}
+
+	tests := []struct {
+		name        string
+		requests    []*grpc.RestoreRequest
+		mockCalls   []*gomock.Call
+		wantSummary *grpc.RestoreSummary
+		expectedErr error
+	}{
+		{
+			name:     "Restored",
+			requests: chunks(),
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Restore(gomock.Any(), testSnapshot()).Return(nil),
+			},
+			wantSummary: &grpc.RestoreSummary{Users: 2, LastInsertedId: 3, Tenant: "acme", CreatedAt: "2024-07-01T12:00:00Z"},
+		},
+		{
+			name:     "Store not empty",
+			requests: chunks(),
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Restore(gomock.Any(), testSnapshot()).Return(errors.StoreNotEmpty{Users: 4}),
+			},
+			expectedErr: status.Error(codes.FailedPrecondition, "store is not empty: it holds 4 users"),
+		},
+		{
+			name:        "Truncated file",
+			requests:    chunks()[:1],
+			expectedErr: status.Error(codes.InvalidArgument, "invalid backup: malformed file: unexpected EOF"),
+		},
+		{
+			name:        "Empty file",
+			expectedErr: status.Error(codes.InvalidArgument, "invalid backup: empty file"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &mockRestoreServer{requests: tc.requests}
+
+			err := handler.Restore(stream)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.wantSummary, stream.summary)
+		})
+	}
+
+	t.Run("File too large", func(t *testing.T) {
+		stream := &mockRestoreServer{requests: chunks()}
+
+		err := New(mockService, WithMaxBackupSize(len(data)-1)).Restore(stream)
+
+		assert.Equal(t, status.Error(codes.ResourceExhausted, fmt.Sprintf("invalid backup: file larger than %d bytes", len(data)-1)), err)
+		assert.Nil(t, stream.summary)
+	})
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+var duplicateRules = map[grpc.FindDuplicatesRequest_Rule]store.DuplicateRule{
+	grpc.FindDuplicatesRequest_RULE_PHONE:     store.DuplicatePhone,
+	grpc.FindDuplicatesRequest_RULE_NAME_CITY: store.DuplicateNameCity,
+}
+
+var mergeSources = map[grpc.MergeRequest_Source]models.MergeSource{
+	grpc.MergeRequest_SOURCE_SURVIVOR: models.MergeFromSurvivor,
+	grpc.MergeRequest_SOURCE_MERGED:   models.MergeFromMerged,
+}
+
+// FindDuplicates returns the clusters of users likely to be duplicates of one another.
+func (u *user) FindDuplicates(ctx context.Context, req *grpc.FindDuplicatesRequest) (*grpc.DuplicateClusters, error) {
+	query := store.DuplicatesQuery{MinSimilarity: req.GetMinSimilarity()}
+
+	for _, rule := range req.GetRules() {
+		storeRule, ok := duplicateRules[rule]
+		if !ok {
+			err := errors.InvalidParams{Params: []string{"rules"}}
+
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		query.Rules = append(query.Rules, storeRule)
+	}
+
+	clusters, err := u.userService.FindDuplicates(ctx, query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	grpcClusters := &grpc.DuplicateClusters{Clusters: make([]*grpc.DuplicateCluster, 0, len(clusters))}
+
+	for _, cluster := range clusters {
+		grpcCluster := &grpc.DuplicateCluster{Users: u.userToGRPCUsers(ctx, cluster.Users).GetUsers()}
+
+		for _, rule := range cluster.Rules {
+			for grpcRule, storeRule := range duplicateRules {
+				if storeRule == rule {
+					grpcCluster.Rules = append(grpcCluster.Rules, grpcRule)
+				}
+			}
+		}
+
+		grpcClusters.Clusters = append(grpcClusters.Clusters, grpcCluster)
+	}
+
+	return grpcClusters, nil
+}
+
+// Merge merges a user into another one, which keeps its ID, taking every field from the user
+// chosen for it, and deletes it.
+func (u *user) Merge(ctx context.Context, req *grpc.MergeRequest) (*grpc.User, error) {
+	mergeReq := &models.MergeRequest{SurvivorID: int(req.GetSurvivorId()), MergedID: int(req.GetMergedId())}
+
+	var invalid []string
+
+	for _, field := range []struct {
+		name   string
+		source grpc.MergeRequest_Source
+		dest   *models.MergeSource
+	}{
+		{"fname", req.GetFname(), &mergeReq.Fname},
+		{"city", req.GetCity(), &mergeReq.City},
+		{"phone", req.GetPhone(), &mergeReq.Phone},
+		{"height", req.GetHeight(), &mergeReq.Height},
+		{"married", req.GetMarried(), &mergeReq.Married},
+	} {
+		source, ok := mergeSources[field.source]
+		if !ok {
+			invalid = append(invalid, field.name)
+		}
+
+		*field.dest = source
+	}
+
+	if len(invalid) > 0 {
+		err := errors.InvalidParams{Params: invalid}
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	usr, err := u.userService.Merge(ctx, mergeReq)
+	if err != nil {
+		var notFoundErr errors.UserNotFound
+
+		if stdErrors.As(err, &notFoundErr) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return u.userToGRPCUser(ctx, usr), nil
+}
+
+// ListMerges returns the merges the user took part in, as the survivor or as the user merged,
+// oldest first.
+func (u *user) ListMerges(ctx context.Context, req *grpc.ListMergesRequest) (*grpc.MergeRecords, error) {
+	records, err := u.userService.Merges(ctx, int(req.GetUserId()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	grpcRecords := &grpc.MergeRecords{Merges: make([]*grpc.MergeRecord, 0, len(records))}
+
+	for _, record := range records {
+		grpcRecords.Merges = append(grpcRecords.Merges, &grpc.MergeRecord{
+			Revision:   record.Revision,
+			MergedAt:   record.MergedAt.UTC().Format(time.RFC3339),
+			Survivor:   u.userToGRPCUser(ctx, &record.Survivor),
+			MergedUser: u.userToGRPCUser(ctx, &record.Merged),
+			Result:     u.userToGRPCUser(ctx, &record.Result),
+		})
+	}
+
+	return grpcRecords, nil
+}
@@ -0,0 +1,207 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+func Test_FindDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	john := models.User{ID: 1, Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9}
+	jon := models.User{ID: 2, Fname: "Jon", City: "Boston", Phone: "+1 555 010 0000", Height: 5.9}
+
+	tests := []struct {
+		name        string
+		req         *grpc.FindDuplicatesRequest
+		mockCalls   []*gomock.Call
+		expected    *grpc.DuplicateClusters
+		expectedErr error
+	}{
+		{
+			name: "Clusters",
+			req:  &grpc.FindDuplicatesRequest{MinSimilarity: 0.5},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().FindDuplicates(gomock.Any(), store.DuplicatesQuery{MinSimilarity: 0.5}).Return([]store.DuplicateCluster{
+					{Users: []models.User{john, jon}, Rules: []store.DuplicateRule{store.DuplicatePhone, store.DuplicateNameCity}},
+				}, nil),
+			},
+			expected: &grpc.DuplicateClusters{Clusters: []*grpc.DuplicateCluster{{
+				Users: []*grpc.User{
+					{Id: 1, Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9},
+					{Id: 2, Fname: "Jon", City: "Boston", Phone: "+1 555 010 0000", Height: 5.9},
+				},
+				Rules: []grpc.FindDuplicatesRequest_Rule{grpc.FindDuplicatesRequest_RULE_PHONE, grpc.FindDuplicatesRequest_RULE_NAME_CITY},
+			}}},
+		},
+		{
+			name: "Rules",
+			req:  &grpc.FindDuplicatesRequest{Rules: []grpc.FindDuplicatesRequest_Rule{grpc.FindDuplicatesRequest_RULE_PHONE}},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().FindDuplicates(gomock.Any(), store.DuplicatesQuery{
+					Rules: []store.DuplicateRule{store.DuplicatePhone},
+				}).Return([]store.DuplicateCluster{}, nil),
+			},
+			expected: &grpc.DuplicateClusters{Clusters: []*grpc.DuplicateCluster{}},
+		},
+		{
+			name:        "Unspecified rule",
+			req:         &grpc.FindDuplicatesRequest{Rules: []grpc.FindDuplicatesRequest_Rule{grpc.FindDuplicatesRequest_RULE_UNSPECIFIED}},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: rules"),
+		},
+		{
+			name: "Invalid similarity",
+			req:  &grpc.FindDuplicatesRequest{MinSimilarity: 2},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().FindDuplicates(gomock.Any(), store.DuplicatesQuery{MinSimilarity: 2}).
+					Return(nil, errors.InvalidParams{Params: []string{"min_similarity"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: min_similarity"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handler.FindDuplicates(context.Background(), tc.req)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	tests := []struct {
+		name        string
+		req         *grpc.MergeRequest
+		mockCalls   []*gomock.Call
+		expected    *grpc.User
+		expectedErr error
+	}{
+		{
+			name: "Merged",
+			req:  &grpc.MergeRequest{SurvivorId: 1, MergedId: 2, City: grpc.MergeRequest_SOURCE_MERGED},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merge(gomock.Any(), &models.MergeRequest{SurvivorID: 1, MergedID: 2, City: models.MergeFromMerged}).
+					Return(&models.User{ID: 1, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9}, nil),
+			},
+			expected: &grpc.User{Id: 1, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9},
+		},
+		{
+			name:        "Unknown source",
+			req:         &grpc.MergeRequest{SurvivorId: 1, MergedId: 2, Phone: 5, Married: -1},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid params: phone, married"),
+		},
+		{
+			name: "Same user",
+			req:  &grpc.MergeRequest{SurvivorId: 1, MergedId: 1},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merge(gomock.Any(), &models.MergeRequest{SurvivorID: 1, MergedID: 1}).
+					Return(nil, errors.InvalidParams{Params: []string{"merged_id"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: merged_id"),
+		},
+		{
+			name: "Missing user",
+			req:  &grpc.MergeRequest{SurvivorId: 1, MergedId: 3},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merge(gomock.Any(), &models.MergeRequest{SurvivorID: 1, MergedID: 3}).
+					Return(nil, errors.UserNotFound{ID: 3}),
+			},
+			expectedErr: status.Error(codes.NotFound, "user with ID 3 not found"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handler.Merge(context.Background(), tc.req)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_ListMerges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	tests := []struct {
+		name        string
+		req         *grpc.ListMergesRequest
+		mockCalls   []*gomock.Call
+		expected    *grpc.MergeRecords
+		expectedErr error
+	}{
+		{
+			name: "Listed",
+			req:  &grpc.ListMergesRequest{UserId: 2},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merges(gomock.Any(), 2).Return([]store.MergeRecord{{
+					Revision: 4,
+					MergedAt: time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+					Survivor: models.User{ID: 1, Fname: "John", City: "Boston", Height: 180},
+					Merged:   models.User{ID: 2, Fname: "Jon", City: "Denver", Height: 180},
+					Result:   models.User{ID: 1, Fname: "John", City: "Denver", Height: 180},
+				}}, nil),
+			},
+			expected: &grpc.MergeRecords{Merges: []*grpc.MergeRecord{{
+				Revision:   4,
+				MergedAt:   "2024-07-01T12:00:00Z",
+				Survivor:   &grpc.User{Id: 1, Fname: "John", City: "Boston", Height: 180},
+				MergedUser: &grpc.User{Id: 2, Fname: "Jon", City: "Denver", Height: 180},
+				Result:     &grpc.User{Id: 1, Fname: "John", City: "Denver", Height: 180},
+			}}},
+		},
+		{
+			name: "No merge",
+			req:  &grpc.ListMergesRequest{UserId: 3},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merges(gomock.Any(), 3).Return([]store.MergeRecord{}, nil),
+			},
+			expected: &grpc.MergeRecords{Merges: []*grpc.MergeRecord{}},
+		},
+		{
+			name: "Invalid user ID",
+			req:  &grpc.ListMergesRequest{},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Merges(gomock.Any(), 0).Return(nil, errors.InvalidParams{Params: []string{"user_id"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: user_id"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handler.ListMerges(context.Background(), tc.req)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
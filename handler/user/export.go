@@ -0,0 +1,101 @@
+package user
+
+import (
+	"bufio"
+	stdErrors "errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/exporter"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// chunkSize is the amount of file data sent per stream message.
+const chunkSize = 32 * 1024
+
+// Export streams the users matching the request filters, or every user, as a file.
+func (u *user) Export(req *grpc.ExportRequest, stream grpc.UserService_ExportServer) error {
+	opts, err := u.grpcExportRequestToOptions(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	opts.HeightFormat = models.HeightFormatFromContext(stream.Context())
+
+	w := bufio.NewWriterSize(chunkWriter(func(data []byte) error {
+		return stream.Send(&grpc.ExportChunk{Data: data})
+	}), chunkSize)
+
+	_, err = exporter.Export(stream.Context(), w, u.userService, opts)
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if err != nil {
+		var invalidErr errors.InvalidParams
+		if stdErrors.As(err, &invalidErr) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+
+		return status.FromContextError(err).Err()
+	}
+
+	return nil
+}
+
+func (u *user) grpcExportRequestToOptions(req *grpc.ExportRequest) (exporter.Options, error) {
+	var format exporter.Format
+
+	switch req.GetFormat() {
+	case grpc.Format_FORMAT_CSV:
+		format = exporter.FormatCSV
+	case grpc.Format_FORMAT_NDJSON:
+		format = exporter.FormatNDJSON
+	case grpc.Format_FORMAT_COLUMNAR_JSON:
+		format = exporter.FormatColumnarJSON
+	case grpc.Format_FORMAT_UNSPECIFIED:
+		return exporter.Options{}, errors.MissingParams{Params: []string{"format"}}
+	default:
+		return exporter.Options{}, errors.InvalidParams{Params: []string{"format"}}
+	}
+
+	opts := exporter.Options{Format: format, Columns: req.GetColumns()}
+
+	var err error
+
+	if req.GetFilters() != nil {
+		opts.Filters, err = u.grpcFiltersToFilters(req.GetFilters())
+		if err != nil {
+			return exporter.Options{}, err
+		}
+	}
+
+	return opts, nil
+}
+
+// chunkWriter sends the data written to it in stream messages of at most chunkSize bytes.
+type chunkWriter func(data []byte) error
+
+func (c chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		n := min(len(p), chunkSize)
+
+		if err := c(p[:n]); err != nil {
+			return written, err
+		}
+
+		written += n
+		p = p[n:]
+	}
+
+	return written, nil
+}
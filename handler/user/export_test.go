@@ -0,0 +1,153 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+)
+
+type mockExportServer struct {
+	gogrpc.ServerStream
+
+	chunks  []*grpc.ExportChunk
+	sendErr error
+}
+
+func (m *mockExportServer) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockExportServer) Send(chunk *grpc.ExportChunk) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+
+	m.chunks = append(m.chunks, &grpc.ExportChunk{Data: bytes.Clone(chunk.GetData())})
+
+	return nil
+}
+
+func (m *mockExportServer) data() string {
+	var buf bytes.Buffer
+
+	for _, chunk := range m.chunks {
+		buf.Write(chunk.GetData())
+	}
+
+	return buf.String()
+}
+
+func Test_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	users := []models.User{
+		{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false},
+		{ID: 4, Fname: "Jane", City: "Boston", Phone: "0987654321", Height: 5.5, Married: true},
+	}
+	city := "Boston"
+
+	tests := []struct {
+		name        string
+		req         *grpc.ExportRequest
+		mockCalls   []*gomock.Call
+		wantData    string
+		expectedErr error
+	}{
+		{
+			name: "CSV export of every user",
+			req:  &grpc.ExportRequest{Format: grpc.Format_FORMAT_CSV, Columns: []string{"id", "fname"}},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Scan(gomock.Any(), nil, 0, 500).Return(users),
+			},
+			wantData: "id,fname\n1,John\n4,Jane\n",
+		},
+		{
+			name: "NDJSON export of a search",
+			req:  &grpc.ExportRequest{Format: grpc.Format_FORMAT_NDJSON, Filters: &grpc.Filters{City: "Boston"}, Columns: []string{"id"}},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Scan(gomock.Any(), &models.Filters{City: &city}, 0, 500).Return(users[1:]),
+			},
+			wantData: "{\"id\":4}\n",
+		},
+		{
+			name:        "Missing format",
+			req:         &grpc.ExportRequest{},
+			expectedErr: status.Error(codes.InvalidArgument, "missing param: format"),
+		},
+		{
+			name:        "Unknown column",
+			req:         &grpc.ExportRequest{Format: grpc.Format_FORMAT_CSV, Columns: []string{"email"}},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: email"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &mockExportServer{}
+
+			err := handler.Export(tc.req, stream)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.wantData, stream.data())
+		})
+	}
+}
+
+func Test_ExportChunks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	page := make([]models.User, 500)
+	for i := range page {
+		page[i] = models.User{ID: i + 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9}
+	}
+
+	mockService.EXPECT().Scan(gomock.Any(), nil, 0, 500).Return(page)
+	mockService.EXPECT().Scan(gomock.Any(), nil, 500, 500).Return([]models.User{})
+
+	stream := &mockExportServer{}
+
+	err := handler.Export(&grpc.ExportRequest{Format: grpc.Format_FORMAT_NDJSON}, stream)
+	assert.NoError(t, err)
+
+	assert.Greater(t, len(stream.chunks), 1)
+
+	for _, chunk := range stream.chunks {
+		assert.LessOrEqual(t, len(chunk.GetData()), chunkSize)
+	}
+
+	assert.Equal(t, 500, bytes.Count([]byte(stream.data()), []byte("\n")))
+}
+
+func Test_ExportSendError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	mockService.EXPECT().Scan(gomock.Any(), nil, 0, 500).Return([]models.User{{ID: 1, Fname: "John"}})
+
+	sendErr := status.Error(codes.Unavailable, "transport is closing")
+	stream := &mockExportServer{sendErr: sendErr}
+
+	err := handler.Export(&grpc.ExportRequest{Format: grpc.Format_FORMAT_CSV}, stream)
+	assert.Equal(t, sendErr, err)
+}
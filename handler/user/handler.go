@@ -14,17 +14,38 @@ import (
 	"github.com/ssshekhu53/user-detail-management/utils"
 )
 
+// DefaultMaxBackupSize is the size of the largest backup file Restore accepts, in bytes, unless
+// set with WithMaxBackupSize.
+const DefaultMaxBackupSize = 64 << 20
+
 type user struct {
 	grpc.UnimplementedUserServiceServer
 
-	userService service.User
+	userService   service.User
+	maxBackupSize int
 }
 
-func New(userService service.User) *user {
-	return &user{userService: userService}
+// Option configures the handler returned by New.
+type Option func(*user)
+
+// WithMaxBackupSize makes Restore reject backup files larger than size bytes.
+func WithMaxBackupSize(size int) Option {
+	return func(u *user) {
+		u.maxBackupSize = size
+	}
 }
 
-func (u *user) Create(_ context.Context, req *grpc.UserRequest) (*grpc.User, error) {
+func New(userService service.User, opts ...Option) *user {
+	u := &user{userService: userService, maxBackupSize: DefaultMaxBackupSize}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+func (u *user) Create(ctx context.Context, req *grpc.UserRequest) (*grpc.User, error) {
 	userReq := u.grpcUserRequestToUserRequest(req)
 
 	err := userReq.ValidateMissingParam()
@@ -37,25 +58,25 @@ func (u *user) Create(_ context.Context, req *grpc.UserRequest) (*grpc.User, err
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	usr, err := u.userService.Create(userReq)
+	usr, err := u.userService.Create(ctx, userReq)
 	if err != nil {
 		return nil, status.Error(codes.AlreadyExists, err.Error())
 	}
 
-	grpcUser := u.userToGRPCUser(usr)
+	grpcUser := u.userToGRPCUser(ctx, usr)
 
 	return grpcUser, nil
 }
 
-func (u *user) Get(context.Context, *emptypb.Empty) (*grpc.Users, error) {
-	users := u.userService.Get()
+func (u *user) Get(ctx context.Context, _ *emptypb.Empty) (*grpc.Users, error) {
+	users := u.userService.Get(ctx)
 
-	grpcUsers := u.userToGRPCUsers(users)
+	grpcUsers := u.userToGRPCUsers(ctx, users)
 
 	return grpcUsers, nil
 }
 
-func (u *user) GetByID(_ context.Context, userID *grpc.UserID) (*grpc.User, error) {
+func (u *user) GetByID(ctx context.Context, userID *grpc.UserID) (*grpc.User, error) {
 	id := int(userID.GetId())
 
 	if id <= 0 {
@@ -64,22 +85,15 @@ func (u *user) GetByID(_ context.Context, userID *grpc.UserID) (*grpc.User, erro
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	usr, err := u.userService.GetByID(id)
+	usr, err := u.userService.GetByID(ctx, id)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	return &grpc.User{
-		Id:      int32(usr.ID),
-		Fname:   usr.Fname,
-		City:    usr.City,
-		Phone:   usr.Phone,
-		Height:  usr.Height,
-		Married: usr.Married,
-	}, nil
+	return u.userToGRPCUser(ctx, usr), nil
 }
 
-func (u *user) GetByIDs(_ context.Context, userIDs *grpc.UserIDs) (*grpc.Users, error) {
+func (u *user) GetByIDs(ctx context.Context, userIDs *grpc.UserIDs) (*grpc.Users, error) {
 	ids := userIDs.GetIds()
 
 	idsInt, err := u.validateIDs(ids)
@@ -87,14 +101,14 @@ func (u *user) GetByIDs(_ context.Context, userIDs *grpc.UserIDs) (*grpc.Users,
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	users := u.userService.GetByIDs(idsInt)
+	users := u.userService.GetByIDs(ctx, idsInt)
 
-	grpcUsers := u.userToGRPCUsers(users)
+	grpcUsers := u.userToGRPCUsers(ctx, users)
 
 	return grpcUsers, nil
 }
 
-func (u *user) Update(_ context.Context, req *grpc.UserUpdateRequest) (*grpc.User, error) {
+func (u *user) Update(ctx context.Context, req *grpc.UserUpdateRequest) (*grpc.User, error) {
 	userReq := u.grpcUserUpdateRequestToUserUpdateRequest(req)
 
 	err := userReq.ValidateMissingParam()
@@ -107,17 +121,17 @@ func (u *user) Update(_ context.Context, req *grpc.UserUpdateRequest) (*grpc.Use
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	usr, err := u.userService.Update(userReq)
+	usr, err := u.userService.Update(ctx, userReq)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	grpcUser := u.userToGRPCUser(usr)
+	grpcUser := u.userToGRPCUser(ctx, usr)
 
 	return grpcUser, nil
 }
 
-func (u *user) Delete(_ context.Context, userID *grpc.UserID) (*emptypb.Empty, error) {
+func (u *user) Delete(ctx context.Context, userID *grpc.UserID) (*emptypb.Empty, error) {
 	id := int(userID.GetId())
 
 	if id <= 0 {
@@ -126,39 +140,70 @@ func (u *user) Delete(_ context.Context, userID *grpc.UserID) (*emptypb.Empty, e
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := u.userService.Delete(id)
+	err := u.userService.Delete(ctx, id)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	return nil, nil
+	return &emptypb.Empty{}, nil
 }
 
-func (u *user) Search(_ context.Context, filters *grpc.Filters) (*grpc.Users, error) {
-	users := u.userService.Search(u.grpcFiltersToFilters(filters))
-	grpcUsers := u.userToGRPCUsers(users)
+func (u *user) Search(ctx context.Context, filters *grpc.Filters) (*grpc.Users, error) {
+	modelFilters, err := u.grpcFiltersToFilters(filters)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	users := u.userService.Search(ctx, modelFilters)
+	grpcUsers := u.userToGRPCUsers(ctx, users)
 
 	return grpcUsers, nil
 }
 
-func (u *user) userToGRPCUsers(users []models.User) *grpc.Users {
+func (u *user) Count(ctx context.Context, filters *grpc.Filters) (*grpc.CountResponse, error) {
+	modelFilters, err := u.grpcFiltersToFilters(filters)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	count := u.userService.Count(ctx, modelFilters)
+
+	return &grpc.CountResponse{Count: int32(count)}, nil
+}
+
+func (u *user) Exists(ctx context.Context, filters *grpc.Filters) (*grpc.ExistsResponse, error) {
+	modelFilters, err := u.grpcFiltersToFilters(filters)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	exists := u.userService.Exists(ctx, modelFilters)
+
+	return &grpc.ExistsResponse{Exists: exists}, nil
+}
+
+func (u *user) userToGRPCUsers(ctx context.Context, users []models.User) *grpc.Users {
 	grpcUsers := make([]*grpc.User, 0)
 
 	for i := range users {
-		grpcUsers = append(grpcUsers, u.userToGRPCUser(&users[i]))
+		grpcUsers = append(grpcUsers, u.userToGRPCUser(ctx, &users[i]))
 	}
 
 	return &grpc.Users{Users: grpcUsers}
 }
 
-func (u *user) userToGRPCUser(usr *models.User) *grpc.User {
+// userToGRPCUser converts usr, showing its height in the format requested by the caller.
+func (u *user) userToGRPCUser(ctx context.Context, usr *models.User) *grpc.User {
+	format := models.HeightFormatFromContext(ctx)
+
 	return &grpc.User{
-		Id:      int32(usr.ID),
-		Fname:   usr.Fname,
-		City:    usr.City,
-		Phone:   usr.Phone,
-		Height:  usr.Height,
-		Married: usr.Married,
+		Id:         int32(usr.ID),
+		Fname:      usr.Fname,
+		City:       usr.City,
+		Phone:      usr.Phone,
+		Height:     format.Convert(usr.Height),
+		Married:    usr.Married,
+		HeightUnit: grpc.HeightUnit(format.Unit),
 	}
 }
 
@@ -176,32 +221,42 @@ func (u *user) validateIDs(ids []int32) ([]int, error) {
 	return idsInt, nil
 }
 
-func (u *user) grpcFiltersToFilters(filters *grpc.Filters) *models.Filters {
+// grpcFiltersToFilters converts filters, turning the height filter to centimeters.
+func (u *user) grpcFiltersToFilters(filters *grpc.Filters) (*models.Filters, error) {
+	unit := models.HeightUnit(filters.HeightUnit)
+	if !unit.Valid() {
+		return nil, errors.InvalidParams{Params: []string{"height_unit"}}
+	}
+
 	return &models.Filters{
 		Fname:  utils.StrPtr(filters.Fname),
 		City:   utils.StrPtr(filters.City),
 		Phone:  utils.StrPtr(filters.Phone),
-		Height: utils.Float64Ptr(filters.Height),
-	}
+		Height: utils.Float64Ptr(models.Height{Value: filters.Height, Unit: unit}.Centimeters()),
+	}, nil
 }
 
+// grpcUserRequestToUserRequest converts userReq. The height units of models mirror those of the
+// API, so they are converted directly.
 func (u *user) grpcUserRequestToUserRequest(userReq *grpc.UserRequest) *models.UserRequest {
 	return &models.UserRequest{
-		Fname:   utils.StrPtr(userReq.Fname),
-		City:    utils.StrPtr(userReq.City),
-		Phone:   utils.StrPtr(userReq.Phone),
-		Height:  utils.Float64Ptr(userReq.Height),
-		Married: utils.BoolPtr(userReq.Married),
+		Fname:      utils.StrPtr(userReq.Fname),
+		City:       utils.StrPtr(userReq.City),
+		Phone:      utils.StrPtr(userReq.Phone),
+		Height:     utils.Float64Ptr(userReq.Height),
+		Married:    utils.BoolPtr(userReq.Married),
+		HeightUnit: models.HeightUnit(userReq.HeightUnit),
 	}
 }
 
 func (u *user) grpcUserUpdateRequestToUserUpdateRequest(userReq *grpc.UserUpdateRequest) *models.UserUpdateRequest {
 	return &models.UserUpdateRequest{
-		ID:      utils.IntPtr(int(userReq.Id)),
-		Fname:   utils.StrPtr(userReq.Fname),
-		City:    utils.StrPtr(userReq.City),
-		Phone:   utils.StrPtr(userReq.Phone),
-		Height:  utils.Float64Ptr(userReq.Height),
-		Married: utils.BoolPtr(userReq.Married),
+		ID:         utils.IntPtr(int(userReq.Id)),
+		Fname:      utils.StrPtr(userReq.Fname),
+		City:       utils.StrPtr(userReq.City),
+		Phone:      utils.StrPtr(userReq.Phone),
+		Height:     utils.Float64Ptr(userReq.Height),
+		Married:    utils.BoolPtr(userReq.Married),
+		HeightUnit: models.HeightUnit(userReq.HeightUnit),
 	}
 }
@@ -50,7 +50,7 @@ func Test_Create(t *testing.T) {
 		{
 			"Success", sampleReq,
 			func() {
-				mockService.EXPECT().Create(gomock.Any()).Return(sampleUser, nil)
+				mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(sampleUser, nil)
 			},
 			&grpc.User{
 				Id:      1,
@@ -86,10 +86,24 @@ func Test_Create(t *testing.T) {
 			},
 			nil, status.Error(codes.InvalidArgument, "invalid param: height"),
 		},
+		{
+			"Invalid height unit", &grpc.UserRequest{
+				Fname:      "John",
+				City:       "New York",
+				Phone:      "1234567890",
+				Height:     180,
+				Married:    false,
+				HeightUnit: grpc.HeightUnit(9),
+			},
+			func() {
+				// No mock expected as it should fail before calling the service
+			},
+			nil, status.Error(codes.InvalidArgument, "invalid param: height_unit"),
+		},
 		{
 			"User Already Exists", sampleReq,
 			func() {
-				mockService.EXPECT().Create(gomock.Any()).Return(nil, errors.UserAlreadyExists{})
+				mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.UserAlreadyExists{})
 			},
 			nil, status.Error(codes.AlreadyExists, "user already exists with given combination"),
 		},
@@ -134,7 +148,7 @@ func Test_Get(t *testing.T) {
 		{
 			"Success",
 			func() {
-				mockService.EXPECT().Get().Return(sampleUsers)
+				mockService.EXPECT().Get(gomock.Any()).Return(sampleUsers)
 			},
 			&grpc.Users{
 				Users: []*grpc.User{
@@ -180,7 +194,7 @@ func Test_GetByID(t *testing.T) {
 		{
 			"Success", 1,
 			func() {
-				mockService.EXPECT().GetByID(1).Return(&models.User{
+				mockService.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID:      1,
 					Fname:   "John",
 					City:    "New York",
@@ -208,7 +222,7 @@ func Test_GetByID(t *testing.T) {
 		{
 			"User Not Found", 2,
 			func() {
-				mockService.EXPECT().GetByID(2).Return(nil, errors.UserNotFound{ID: 2})
+				mockService.EXPECT().GetByID(gomock.Any(), 2).Return(nil, errors.UserNotFound{ID: 2})
 			},
 			nil, status.Error(codes.NotFound, "user with ID 2 not found"),
 		},
@@ -262,7 +276,7 @@ func Test_GetByIDs(t *testing.T) {
 		{
 			"Success", []int32{1, 2},
 			func() {
-				mockService.EXPECT().GetByIDs([]int{1, 2}).Return(sampleUsers)
+				mockService.EXPECT().GetByIDs(gomock.Any(), []int{1, 2}).Return(sampleUsers)
 			},
 			&grpc.Users{
 				Users: []*grpc.User{
@@ -341,7 +355,7 @@ func Test_Update(t *testing.T) {
 		{
 			"Success", sampleUpdateReq,
 			func() {
-				mockService.EXPECT().Update(gomock.Any()).Return(sampleUser, nil)
+				mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(sampleUser, nil)
 			},
 			&grpc.User{
 				Id:      1,
@@ -382,7 +396,7 @@ func Test_Update(t *testing.T) {
 		{
 			"User Not Found", sampleUpdateReq,
 			func() {
-				mockService.EXPECT().Update(gomock.Any()).Return(nil, errors.UserNotFound{ID: 1})
+				mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, errors.UserNotFound{ID: 1})
 			},
 			nil, status.Error(codes.NotFound, "user with ID 1 not found"),
 		},
@@ -416,7 +430,7 @@ func Test_Delete(t *testing.T) {
 		{
 			"Success", 1,
 			func() {
-				mockService.EXPECT().Delete(1).Return(nil)
+				mockService.EXPECT().Delete(gomock.Any(), 1).Return(nil)
 			}, nil,
 		},
 		{
@@ -428,7 +442,7 @@ func Test_Delete(t *testing.T) {
 		{
 			"not found", 1,
 			func() {
-				mockService.EXPECT().Delete(1).Return(errors.UserNotFound{ID: 1})
+				mockService.EXPECT().Delete(gomock.Any(), 1).Return(errors.UserNotFound{ID: 1})
 			}, status.Error(codes.NotFound, "user with ID 1 not found"),
 		},
 	}
@@ -481,7 +495,7 @@ func Test_Search(t *testing.T) {
 			"Success",
 			sampleFilters,
 			func() {
-				mockService.EXPECT().Search(gomock.Any()).Return(sampleUsers)
+				mockService.EXPECT().Search(gomock.Any(), gomock.Any()).Return(sampleUsers)
 			},
 			&grpc.Users{
 				Users: []*grpc.User{
@@ -509,3 +523,67 @@ func Test_Search(t *testing.T) {
 		})
 	}
 }
+
+func Test_CountExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	city := "Boston"
+	filters := &models.Filters{City: &city}
+
+	mockService.EXPECT().Count(gomock.Any(), filters).Return(2)
+	mockService.EXPECT().Exists(gomock.Any(), filters).Return(false)
+
+	count, err := handler.Count(context.Background(), &grpc.Filters{City: "Boston"})
+	assert.NoError(t, err)
+	assert.Equal(t, &grpc.CountResponse{Count: 2}, count)
+
+	exists, err := handler.Exists(context.Background(), &grpc.Filters{City: "Boston"})
+	assert.NoError(t, err)
+	assert.Equal(t, &grpc.ExistsResponse{Exists: false}, exists)
+
+	phone := "1234567890"
+	mockService.EXPECT().Exists(gomock.Any(), &models.Filters{Phone: &phone}).Return(true)
+
+	exists, err = handler.Exists(context.Background(), &grpc.Filters{Phone: "1234567890"})
+	assert.NoError(t, err)
+	assert.Equal(t, &grpc.ExistsResponse{Exists: true}, exists)
+
+	_, err = handler.Count(context.Background(), &grpc.Filters{Height: 6, HeightUnit: grpc.HeightUnit(9)})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid param: height_unit"), err)
+}
+
+func Test_HeightUnits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	precision := 2
+	ctx := models.NewHeightFormatContext(context.Background(), models.HeightFormat{Unit: models.HeightUnitFeet, Precision: &precision})
+	john := models.User{ID: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 179.832}
+	height := 180.0
+
+	mockService.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *models.UserRequest) (*models.User, error) {
+		assert.Equal(t, 179.832, req.HeightCentimeters())
+
+		return &john, nil
+	})
+	mockService.EXPECT().Search(gomock.Any(), &models.Filters{Height: &height}).Return([]models.User{john})
+
+	created, err := handler.Create(ctx, &grpc.UserRequest{
+		Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, HeightUnit: grpc.HeightUnit_HEIGHT_UNIT_FEET,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &grpc.User{
+		Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, HeightUnit: grpc.HeightUnit_HEIGHT_UNIT_FEET,
+	}, created)
+
+	users, err := handler.Search(ctx, &grpc.Filters{Height: 1.8, HeightUnit: grpc.HeightUnit_HEIGHT_UNIT_METERS})
+	assert.NoError(t, err)
+	assert.Equal(t, 5.9, users.GetUsers()[0].GetHeight())
+}
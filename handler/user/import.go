@@ -0,0 +1,128 @@
+package user
+
+import (
+	stdErrors "errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/importer"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// Import reads the file sent in the data of the stream messages. The options are taken from the
+// first message.
+func (u *user) Import(stream grpc.UserService_ImportServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		err = errors.MissingParams{Params: []string{"options"}}
+
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	opts, err := u.grpcImportOptionsToOptions(first.GetOptions())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The messages are received as the importer reads, so nothing is received once it stops.
+	summary, err := importer.Import(stream.Context(), &importReader{stream: stream, data: first.GetData()}, u.userService, opts)
+	if err != nil {
+		var lineErr errors.InvalidLine
+		if stdErrors.As(err, &lineErr) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+
+		return status.FromContextError(err).Err()
+	}
+
+	return stream.SendAndClose(u.summaryToGRPCImportSummary(summary))
+}
+
+// importReader reads the data of the messages of an Import stream, receiving the next message when
+// the data of the previous one is read. It stops with the error of the context of the stream once
+// it is done.
+type importReader struct {
+	stream grpc.UserService_ImportServer
+	data   []byte
+}
+
+func (r *importReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if err := r.stream.Context().Err(); err != nil {
+			return 0, err
+		}
+
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		r.data = req.GetData()
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+func (u *user) grpcImportOptionsToOptions(opts *grpc.ImportOptions) (importer.Options, error) {
+	var format importer.Format
+
+	switch opts.GetFormat() {
+	case grpc.Format_FORMAT_CSV:
+		format = importer.FormatCSV
+	case grpc.Format_FORMAT_NDJSON:
+		format = importer.FormatNDJSON
+	case grpc.Format_FORMAT_UNSPECIFIED:
+		return importer.Options{}, errors.MissingParams{Params: []string{"format"}}
+	default:
+		return importer.Options{}, errors.InvalidParams{Params: []string{"format"}}
+	}
+
+	if opts.GetBatchSize() < 0 {
+		return importer.Options{}, errors.InvalidParams{Params: []string{"batch_size"}}
+	}
+
+	heightUnit := models.HeightUnit(opts.GetHeightUnit())
+	if !heightUnit.Valid() {
+		return importer.Options{}, errors.InvalidParams{Params: []string{"height_unit"}}
+	}
+
+	return importer.Options{
+		Format:     format,
+		Columns:    opts.GetColumns(),
+		DryRun:     opts.GetDryRun(),
+		BatchSize:  int(opts.GetBatchSize()),
+		HeightUnit: heightUnit,
+	}, nil
+}
+
+func (u *user) summaryToGRPCImportSummary(summary importer.Summary) *grpc.ImportSummary {
+	importErrors := make([]*grpc.ImportError, 0, len(summary.Errors))
+
+	for _, lineErr := range summary.Errors {
+		importErrors = append(importErrors, &grpc.ImportError{Line: int32(lineErr.Line), Message: lineErr.Reason})
+	}
+
+	return &grpc.ImportSummary{
+		Rows:    int32(summary.Rows),
+		Created: int32(summary.Created),
+		Updated: int32(summary.Updated),
+		Failed:  int32(summary.Failed),
+		Errors:  importErrors,
+		DryRun:  summary.DryRun,
+	}
+}
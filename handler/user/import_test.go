@@ -0,0 +1,156 @@
+package user
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+type mockImportServer struct {
+	gogrpc.ServerStream
+
+	ctx      context.Context
+	requests []*grpc.ImportRequest
+	recvErr  error
+	summary  *grpc.ImportSummary
+}
+
+func (m *mockImportServer) Context() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+
+	return context.Background()
+}
+
+func (m *mockImportServer) Recv() (*grpc.ImportRequest, error) {
+	if len(m.requests) == 0 {
+		if m.recvErr != nil {
+			return nil, m.recvErr
+		}
+
+		return nil, io.EOF
+	}
+
+	req := m.requests[0]
+	m.requests = m.requests[1:]
+
+	return req, nil
+}
+
+func (m *mockImportServer) SendAndClose(summary *grpc.ImportSummary) error {
+	m.summary = summary
+
+	return nil
+}
+
+func Test_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	csvOptions := &grpc.ImportOptions{Format: grpc.Format_FORMAT_CSV, Columns: map[string]string{"Mobile": "phone"}}
+
+	tests := []struct {
+		name        string
+		stream      *mockImportServer
+		mockSetup   func()
+		wantSummary *grpc.ImportSummary
+		wantCode    codes.Code
+	}{
+		{
+			"File split across messages",
+			&mockImportServer{requests: []*grpc.ImportRequest{
+				{Options: csvOptions, Data: []byte("fname,city,Mobile,height,married\nJohn,Bos")},
+				{Data: []byte("ton,1234567890,180,false\nJane,Boston,12,165,false\n")},
+			}},
+			func() {
+				mockService.EXPECT().Upsert(gomock.Any(), gomock.Len(1)).Return([]store.UpsertResult{{User: models.User{ID: 1}, Created: true}})
+			},
+			&grpc.ImportSummary{Rows: 2, Created: 1, Failed: 1, Errors: []*grpc.ImportError{{Line: 3, Message: "invalid param: phone"}}},
+			codes.OK,
+		},
+		{
+			"Dry run",
+			&mockImportServer{requests: []*grpc.ImportRequest{
+				{Options: &grpc.ImportOptions{Format: grpc.Format_FORMAT_NDJSON, DryRun: true}, Data: []byte(`{"fname":"John","city":"Boston","phone":"1234567890","height":5.9,"height_unit":"ft","married":true}`)},
+			}},
+			func() {
+				mockService.EXPECT().GetByPhone(gomock.Any(), "1234567890").Return([]models.User{{ID: 1}})
+			},
+			&grpc.ImportSummary{Rows: 1, Updated: 1, Errors: []*grpc.ImportError{}, DryRun: true},
+			codes.OK,
+		},
+		{"Empty stream", &mockImportServer{}, func() {}, nil, codes.InvalidArgument},
+		{
+			"Missing format",
+			&mockImportServer{requests: []*grpc.ImportRequest{{Data: []byte("fname\n")}}},
+			func() {}, nil, codes.InvalidArgument,
+		},
+		{
+			"Negative batch size",
+			&mockImportServer{requests: []*grpc.ImportRequest{{Options: &grpc.ImportOptions{Format: grpc.Format_FORMAT_CSV, BatchSize: -1}}}},
+			func() {}, nil, codes.InvalidArgument,
+		},
+		{
+			"Invalid header",
+			&mockImportServer{requests: []*grpc.ImportRequest{{Options: csvOptions, Data: []byte("fname,email\n")}}},
+			func() {}, nil, codes.InvalidArgument,
+		},
+		{
+			"Client stream fails",
+			&mockImportServer{
+				requests: []*grpc.ImportRequest{{Options: csvOptions, Data: []byte("fname,city,phone,height,married\n")}},
+				recvErr:  status.Error(codes.Canceled, "context canceled"),
+			},
+			func() {}, nil, codes.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := handler.Import(tt.stream)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantSummary, tt.stream.summary)
+		})
+	}
+
+	t.Run("Stops receiving when the import stops", func(t *testing.T) {
+		stream := &mockImportServer{requests: []*grpc.ImportRequest{
+			{Options: csvOptions, Data: []byte("fname,email\n")},
+			{Data: []byte("John,john@example.com\n")},
+		}}
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(handler.Import(stream)))
+		assert.Len(t, stream.requests, 1, "the messages following the invalid header are not received")
+	})
+
+	t.Run("Stops receiving when the context ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stream := &mockImportServer{ctx: ctx, requests: []*grpc.ImportRequest{
+			{Options: csvOptions},
+			{Data: []byte("fname,city,phone,height,married\n")},
+		}}
+
+		assert.Equal(t, codes.Canceled, status.Code(handler.Import(stream)))
+		assert.Len(t, stream.requests, 1)
+	})
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+var groupBys = map[grpc.StatsRequest_GroupBy]store.GroupBy{
+	grpc.StatsRequest_GROUP_BY_NONE:    store.GroupByNone,
+	grpc.StatsRequest_GROUP_BY_CITY:    store.GroupByCity,
+	grpc.StatsRequest_GROUP_BY_MARRIED: store.GroupByMarried,
+}
+
+// Stats aggregates the counts and heights of the users matching the filters, in total and by group.
+func (u *user) Stats(ctx context.Context, req *grpc.StatsRequest) (*grpc.StatsResponse, error) {
+	groupBy, ok := groupBys[req.GetGroupBy()]
+	if !ok {
+		err := errors.InvalidParams{Params: []string{"group_by"}}
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query := store.StatsQuery{GroupBy: groupBy, HistogramBuckets: int(req.GetHistogramBuckets())}
+
+	if req.GetFilters() != nil {
+		var err error
+
+		query.Filters, err = u.grpcFiltersToFilters(req.GetFilters())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	stats, err := u.userService.Stats(ctx, query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &grpc.StatsResponse{
+		Total:  u.statsGroupToGRPCStatsGroup(ctx, stats.Total),
+		Groups: make([]*grpc.StatsGroup, 0, len(stats.Groups)),
+	}
+
+	for _, group := range stats.Groups {
+		res.Groups = append(res.Groups, u.statsGroupToGRPCStatsGroup(ctx, group))
+	}
+
+	return res, nil
+}
+
+// statsGroupToGRPCStatsGroup converts group, showing its heights in the format requested by the
+// caller.
+func (u *user) statsGroupToGRPCStatsGroup(ctx context.Context, group store.StatsGroup) *grpc.StatsGroup {
+	grpcGroup := &grpc.StatsGroup{Key: group.Key, Count: int32(group.Count)}
+
+	if group.Height == nil {
+		return grpcGroup
+	}
+
+	height := group.Height
+	format := models.HeightFormatFromContext(ctx)
+
+	grpcGroup.Height = &grpc.HeightStats{
+		Min:       format.Convert(height.Min),
+		Max:       format.Convert(height.Max),
+		Avg:       format.Convert(height.Avg),
+		P50:       format.Convert(height.P50),
+		P90:       format.Convert(height.P90),
+		P95:       format.Convert(height.P95),
+		P99:       format.Convert(height.P99),
+		Histogram: make([]*grpc.HistogramBucket, 0, len(height.Histogram)),
+		Unit:      grpc.HeightUnit(format.Unit),
+	}
+
+	for _, bucket := range height.Histogram {
+		grpcGroup.Height.Histogram = append(grpcGroup.Height.Histogram, &grpc.HistogramBucket{
+			LowerBound: format.Convert(bucket.LowerBound),
+			UpperBound: format.Convert(bucket.UpperBound),
+			Count:      int32(bucket.Count),
+		})
+	}
+
+	return grpcGroup
+}
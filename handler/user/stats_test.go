@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+func Test_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	city := "Boston"
+	height := store.HeightStats{
+		Min: 5, Max: 6, Avg: 5.5, P50: 5.5, P90: 5.9, P95: 5.95, P99: 5.99,
+		Histogram: []store.HistogramBucket{{LowerBound: 5, UpperBound: 5.5, Count: 1}, {LowerBound: 5.5, UpperBound: 6, Count: 1}},
+	}
+	grpcHeight := &grpc.HeightStats{
+		Min: 5, Max: 6, Avg: 5.5, P50: 5.5, P90: 5.9, P95: 5.95, P99: 5.99,
+		Histogram: []*grpc.HistogramBucket{{LowerBound: 5, UpperBound: 5.5, Count: 1}, {LowerBound: 5.5, UpperBound: 6, Count: 1}},
+	}
+
+	tests := []struct {
+		name        string
+		req         *grpc.StatsRequest
+		mockCalls   []*gomock.Call
+		expected    *grpc.StatsResponse
+		expectedErr error
+	}{
+		{
+			name: "Grouped by city",
+			req: &grpc.StatsRequest{
+				Filters: &grpc.Filters{City: "Boston"}, GroupBy: grpc.StatsRequest_GROUP_BY_CITY, HistogramBuckets: 2,
+			},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Stats(gomock.Any(), store.StatsQuery{
+					Filters: &models.Filters{City: &city}, GroupBy: store.GroupByCity, HistogramBuckets: 2,
+				}).Return(store.Stats{
+					Total:  store.StatsGroup{Count: 2, Height: &height},
+					Groups: []store.StatsGroup{{Key: "Boston", Count: 2, Height: &height}},
+				}, nil),
+			},
+			expected: &grpc.StatsResponse{
+				Total:  &grpc.StatsGroup{Count: 2, Height: grpcHeight},
+				Groups: []*grpc.StatsGroup{{Key: "Boston", Count: 2, Height: grpcHeight}},
+			},
+		},
+		{
+			name: "No users",
+			req:  &grpc.StatsRequest{},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Stats(gomock.Any(), store.StatsQuery{}).Return(store.Stats{}, nil),
+			},
+			expected: &grpc.StatsResponse{Total: &grpc.StatsGroup{}, Groups: []*grpc.StatsGroup{}},
+		},
+		{
+			name:        "Unknown group by",
+			req:         &grpc.StatsRequest{GroupBy: 7},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: group_by"),
+		},
+		{
+			name: "Invalid histogram buckets",
+			req:  &grpc.StatsRequest{HistogramBuckets: -1},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Stats(gomock.Any(), store.StatsQuery{HistogramBuckets: -1}).
+					Return(store.Stats{}, errors.InvalidParams{Params: []string{"histogram_buckets"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: histogram_buckets"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handler.Stats(context.Background(), tc.req)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_StatsHeightUnit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	height := store.HeightStats{
+		Min: 150, Max: 200, Avg: 175, P50: 175, P90: 195, P95: 197.5, P99: 199.5,
+		Histogram: []store.HistogramBucket{{LowerBound: 150, UpperBound: 200, Count: 2}},
+	}
+
+	mockService.EXPECT().Stats(gomock.Any(), store.StatsQuery{}).Return(store.Stats{Total: store.StatsGroup{Count: 2, Height: &height}}, nil)
+
+	ctx := models.NewHeightFormatContext(context.Background(), models.HeightFormat{Unit: models.HeightUnitMeters})
+
+	got, err := handler.Stats(ctx, &grpc.StatsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, &grpc.HeightStats{
+		Min: 1.5, Max: 2, Avg: 1.75, P50: 1.75, P90: 1.95, P95: 1.975, P99: 1.995,
+		Histogram: []*grpc.HistogramBucket{{LowerBound: 1.5, UpperBound: 2, Count: 2}},
+		Unit:      grpc.HeightUnit_HEIGHT_UNIT_METERS,
+	}, got.GetTotal().GetHeight())
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+var suggestFields = map[grpc.SuggestRequest_Field]store.SuggestField{
+	grpc.SuggestRequest_FIELD_FNAME: store.SuggestFname,
+	grpc.SuggestRequest_FIELD_CITY:  store.SuggestCity,
+}
+
+// Suggest returns the users whose first name or city resembles the text, best matches first.
+func (u *user) Suggest(ctx context.Context, req *grpc.SuggestRequest) (*grpc.Suggestions, error) {
+	query := store.SuggestQuery{Text: req.GetText(), Prefix: req.GetPrefix(), Limit: int(req.GetLimit())}
+
+	for _, field := range req.GetFields() {
+		storeField, ok := suggestFields[field]
+		if !ok {
+			err := errors.InvalidParams{Params: []string{"fields"}}
+
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		query.Fields = append(query.Fields, storeField)
+	}
+
+	suggestions, err := u.userService.Suggest(ctx, query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	grpcSuggestions := &grpc.Suggestions{Suggestions: make([]*grpc.Suggestion, 0, len(suggestions))}
+
+	for i := range suggestions {
+		var field grpc.SuggestRequest_Field
+
+		for grpcField, storeField := range suggestFields {
+			if storeField == suggestions[i].Field {
+				field = grpcField
+			}
+		}
+
+		grpcSuggestions.Suggestions = append(grpcSuggestions.Suggestions, &grpc.Suggestion{
+			User:  u.userToGRPCUser(ctx, &suggestions[i].User),
+			Score: suggestions[i].Score,
+			Field: field,
+		})
+	}
+
+	return grpcSuggestions, nil
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+func Test_Suggest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	john := models.User{ID: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9}
+	jane := models.User{ID: 2, Fname: "Jane", City: "Johnstown", Phone: "0987654321", Height: 5.5}
+
+	tests := []struct {
+		name        string
+		req         *grpc.SuggestRequest
+		mockCalls   []*gomock.Call
+		expected    *grpc.Suggestions
+		expectedErr error
+	}{
+		{
+			name: "Ranked suggestions",
+			req:  &grpc.SuggestRequest{Text: "jo", Prefix: true, Limit: 5},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Suggest(gomock.Any(), store.SuggestQuery{Text: "jo", Prefix: true, Limit: 5}).Return([]store.Suggestion{
+					{User: john, Score: 0.5, Field: store.SuggestFname},
+					{User: jane, Score: 2.0 / 9, Field: store.SuggestCity},
+				}, nil),
+			},
+			expected: &grpc.Suggestions{Suggestions: []*grpc.Suggestion{
+				{
+					User:  &grpc.User{Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9},
+					Score: 0.5,
+					Field: grpc.SuggestRequest_FIELD_FNAME,
+				},
+				{
+					User:  &grpc.User{Id: 2, Fname: "Jane", City: "Johnstown", Phone: "0987654321", Height: 5.5},
+					Score: 2.0 / 9,
+					Field: grpc.SuggestRequest_FIELD_CITY,
+				},
+			}},
+		},
+		{
+			name: "Fields",
+			req:  &grpc.SuggestRequest{Text: "bostn", Fields: []grpc.SuggestRequest_Field{grpc.SuggestRequest_FIELD_CITY}},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Suggest(gomock.Any(), store.SuggestQuery{
+					Text: "bostn", Fields: []store.SuggestField{store.SuggestCity},
+				}).Return([]store.Suggestion{}, nil),
+			},
+			expected: &grpc.Suggestions{Suggestions: []*grpc.Suggestion{}},
+		},
+		{
+			name:        "Unspecified field",
+			req:         &grpc.SuggestRequest{Text: "jo", Fields: []grpc.SuggestRequest_Field{grpc.SuggestRequest_FIELD_UNSPECIFIED}},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: fields"),
+		},
+		{
+			name: "Missing text",
+			req:  &grpc.SuggestRequest{},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Suggest(gomock.Any(), store.SuggestQuery{}).Return(nil, errors.MissingParams{Params: []string{"text"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "missing param: text"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handler.Suggest(context.Background(), tc.req)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
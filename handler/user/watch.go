@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// Watch streams the changes made to the users of the tenant until the client goes away.
+func (u *user) Watch(req *grpc.WatchRequest, stream grpc.UserService_WatchServer) error {
+	var (
+		filters *models.Filters
+		err     error
+	)
+
+	if req.GetFilters() != nil {
+		filters, err = u.grpcFiltersToFilters(req.GetFilters())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	err = u.userService.Watch(stream.Context(), filters, req.GetAfterRevision(), func(event store.Event) error {
+		return stream.Send(u.eventToGRPCEvent(stream.Context(), event))
+	})
+
+	var (
+		invalidErr   errors.InvalidParams
+		compactedErr errors.RevisionCompacted
+	)
+
+	switch {
+	case stdErrors.As(err, &invalidErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case stdErrors.As(err, &compactedErr):
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.FromContextError(err).Err()
+}
+
+func (u *user) eventToGRPCEvent(ctx context.Context, event store.Event) *grpc.WatchEvent {
+	var eventType grpc.WatchEvent_Type
+
+	switch event.Type {
+	case store.EventCreated:
+		eventType = grpc.WatchEvent_TYPE_CREATED
+	case store.EventUpdated:
+		eventType = grpc.WatchEvent_TYPE_UPDATED
+	case store.EventDeleted:
+		eventType = grpc.WatchEvent_TYPE_DELETED
+	case store.EventMerged:
+		eventType = grpc.WatchEvent_TYPE_MERGED
+	case store.EventRestored:
+		return &grpc.WatchEvent{Revision: event.Revision, Type: grpc.WatchEvent_TYPE_RESTORED, RestoredUsers: int32(event.Restored)}
+	}
+
+	grpcEvent := &grpc.WatchEvent{Revision: event.Revision, Type: eventType, User: u.userToGRPCUser(ctx, &event.User)}
+
+	if event.Previous != nil {
+		grpcEvent.PreviousUser = u.userToGRPCUser(ctx, event.Previous)
+	}
+
+	if event.Merged != nil {
+		grpcEvent.MergedUser = u.userToGRPCUser(ctx, event.Merged)
+	}
+
+	return grpcEvent
+}
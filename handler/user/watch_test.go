@@ -0,0 +1,128 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+type mockWatchServer struct {
+	gogrpc.ServerStream
+
+	events []*grpc.WatchEvent
+}
+
+func (m *mockWatchServer) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockWatchServer) Send(event *grpc.WatchEvent) error {
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+func Test_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	handler := New(mockService)
+
+	john := models.User{ID: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9}
+	grpcJohn := &grpc.User{Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9}
+	jane := models.User{ID: 2, Fname: "Jane", City: "Boston", Phone: "1234567891", Height: 5.5}
+	grpcJane := &grpc.User{Id: 2, Fname: "Jane", City: "Boston", Phone: "1234567891", Height: 5.5}
+	city := "Boston"
+
+	sendAll := func(events ...store.Event) func(context.Context, *models.Filters, int64, func(store.Event) error) error {
+		return func(ctx context.Context, _ *models.Filters, _ int64, send func(store.Event) error) error {
+			for _, event := range events {
+				if err := send(event); err != nil {
+					return err
+				}
+			}
+
+			return context.Canceled
+		}
+	}
+
+	tests := []struct {
+		name        string
+		req         *grpc.WatchRequest
+		mockCalls   []*gomock.Call
+		wantEvents  []*grpc.WatchEvent
+		expectedErr error
+	}{
+		{
+			name: "Every change",
+			req:  &grpc.WatchRequest{},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Watch(gomock.Any(), nil, int64(0), gomock.Any()).DoAndReturn(sendAll(
+					store.Event{Revision: 1, Type: store.EventCreated, User: john},
+					store.Event{Revision: 2, Type: store.EventUpdated, User: john, Previous: &jane},
+					store.Event{Revision: 3, Type: store.EventDeleted, User: john},
+					store.Event{Revision: 4, Type: store.EventMerged, User: jane, Previous: &jane, Merged: &john},
+					store.Event{Revision: 5, Type: store.EventRestored, Restored: 2},
+				)),
+			},
+			wantEvents: []*grpc.WatchEvent{
+				{Revision: 1, Type: grpc.WatchEvent_TYPE_CREATED, User: grpcJohn},
+				{Revision: 2, Type: grpc.WatchEvent_TYPE_UPDATED, User: grpcJohn, PreviousUser: grpcJane},
+				{Revision: 3, Type: grpc.WatchEvent_TYPE_DELETED, User: grpcJohn},
+				{Revision: 4, Type: grpc.WatchEvent_TYPE_MERGED, User: grpcJane, MergedUser: grpcJohn, PreviousUser: grpcJane},
+				{Revision: 5, Type: grpc.WatchEvent_TYPE_RESTORED, RestoredUsers: 2},
+			},
+			expectedErr: status.Error(codes.Canceled, "context canceled"),
+		},
+		{
+			name: "Resumed with filters",
+			req:  &grpc.WatchRequest{Filters: &grpc.Filters{City: "Boston"}, AfterRevision: 7},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Watch(gomock.Any(), &models.Filters{City: &city}, int64(7), gomock.Any()).DoAndReturn(sendAll(
+					store.Event{Revision: 9, Type: store.EventCreated, User: john},
+				)),
+			},
+			wantEvents:  []*grpc.WatchEvent{{Revision: 9, Type: grpc.WatchEvent_TYPE_CREATED, User: grpcJohn}},
+			expectedErr: status.Error(codes.Canceled, "context canceled"),
+		},
+		{
+			name: "Compacted revision",
+			req:  &grpc.WatchRequest{AfterRevision: 1},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Watch(gomock.Any(), nil, int64(1), gomock.Any()).Return(errors.RevisionCompacted{Revision: 1, Oldest: 5}),
+			},
+			expectedErr: status.Error(codes.OutOfRange, "revision 1 is compacted, the oldest revision to resume from is 5"),
+		},
+		{
+			name: "Invalid revision",
+			req:  &grpc.WatchRequest{AfterRevision: 12},
+			mockCalls: []*gomock.Call{
+				mockService.EXPECT().Watch(gomock.Any(), nil, int64(12), gomock.Any()).Return(errors.InvalidParams{Params: []string{"after_revision"}}),
+			},
+			expectedErr: status.Error(codes.InvalidArgument, "invalid param: after_revision"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &mockWatchServer{}
+
+			err := handler.Watch(tc.req, stream)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.wantEvents, stream.events)
+		})
+	}
+}
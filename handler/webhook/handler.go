@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+	"github.com/ssshekhu53/user-detail-management/webhook"
+)
+
+type handler struct {
+	grpc.UnimplementedWebhookServiceServer
+
+	dispatcher *webhook.Dispatcher
+}
+
+func New(dispatcher *webhook.Dispatcher) *handler {
+	return &handler{dispatcher: dispatcher}
+}
+
+// ListDeadLetters returns the webhook deliveries of the tenant that failed in every attempt.
+func (h *handler) ListDeadLetters(ctx context.Context, _ *grpc.ListDeadLettersRequest) (*grpc.DeadLetters, error) {
+	deadLetters := h.dispatcher.DeadLetters(tenant.FromContext(ctx))
+
+	return &grpc.DeadLetters{DeadLetters: h.deadLettersToGRPCDeadLetters(deadLetters)}, nil
+}
+
+// ReplayDeadLetters makes a single attempt at delivering again the given dead letters of the
+// tenant, or all of them when no ID is given.
+func (h *handler) ReplayDeadLetters(ctx context.Context, req *grpc.ReplayDeadLettersRequest) (*grpc.ReplayDeadLettersResponse, error) {
+	for _, id := range req.GetIds() {
+		if id <= 0 {
+			err := errors.InvalidParams{Params: []string{"ids"}}
+
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	delivered, failed, err := h.dispatcher.Replay(ctx, tenant.FromContext(ctx), req.GetIds())
+	if err != nil {
+		var notFoundErr errors.DeadLetterNotFound
+
+		if stdErrors.As(err, &notFoundErr) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return &grpc.ReplayDeadLettersResponse{Delivered: delivered, Failed: h.deadLettersToGRPCDeadLetters(failed)}, nil
+}
+
+func (h *handler) deadLettersToGRPCDeadLetters(deadLetters []webhook.DeadLetter) []*grpc.DeadLetter {
+	grpcDeadLetters := make([]*grpc.DeadLetter, 0, len(deadLetters))
+
+	for i := range deadLetters {
+		grpcDeadLetters = append(grpcDeadLetters, h.deadLetterToGRPCDeadLetter(&deadLetters[i]))
+	}
+
+	return grpcDeadLetters
+}
+
+func (h *handler) deadLetterToGRPCDeadLetter(deadLetter *webhook.DeadLetter) *grpc.DeadLetter {
+	grpcDeadLetter := &grpc.DeadLetter{
+		Id:            deadLetter.ID,
+		Endpoint:      deadLetter.Endpoint,
+		EventId:       deadLetter.MessageID,
+		EventType:     deadLetter.Event.Type,
+		Revision:      deadLetter.Event.Revision,
+		User:          h.userToGRPCUser(&deadLetter.Event.User),
+		OccurredAt:    deadLetter.Event.OccurredAt.Format(time.RFC3339),
+		Attempts:      int32(deadLetter.Attempts),
+		LastError:     deadLetter.LastError,
+		FailedAt:      deadLetter.FailedAt.UTC().Format(time.RFC3339),
+		RestoredUsers: int32(deadLetter.Event.RestoredUsers),
+	}
+
+	if deadLetter.Event.MergedUser != nil {
+		grpcDeadLetter.MergedUser = h.userToGRPCUser(deadLetter.Event.MergedUser)
+	}
+
+	return grpcDeadLetter
+}
+
+func (h *handler) userToGRPCUser(usr *models.User) *grpc.User {
+	return &grpc.User{
+		Id:      int32(usr.ID),
+		Fname:   usr.Fname,
+		City:    usr.City,
+		Phone:   usr.Phone,
+		Height:  usr.Height,
+		Married: usr.Married,
+	}
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+	"github.com/ssshekhu53/user-detail-management/webhook"
+)
+
+func Test_DeadLetters(t *testing.T) {
+	var accept atomic.Bool
+
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !accept.Load() {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer endpoint.Close()
+
+	userStore := storeUser.New(storeUser.WithOutbox())
+	dispatcher := webhook.New(userStore, webhook.Config{Endpoints: []webhook.Endpoint{{URL: endpoint.URL, Secret: "secret", Tenants: []string{"acme"}}}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		dispatcher.Run(ctx)
+	}()
+
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	handler := New(dispatcher)
+
+	userStore.Create(acme, &models.User{Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9})
+	userStore.Create(acme, &models.User{Fname: "Jane", City: "Denver", Phone: "0987654321", Height: 5.5})
+
+	assert.Eventually(t, func() bool { return len(dispatcher.DeadLetters("acme")) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	res, err := handler.ListDeadLetters(acme, &grpc.ListDeadLettersRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetDeadLetters(), 2)
+
+	deadLetter := res.GetDeadLetters()[0]
+	assert.Equal(t, int64(1), deadLetter.GetId())
+	assert.Equal(t, endpoint.URL, deadLetter.GetEndpoint())
+	assert.Equal(t, int64(1), deadLetter.GetEventId())
+	assert.Equal(t, "user.created", deadLetter.GetEventType())
+	assert.Equal(t, int64(1), deadLetter.GetRevision())
+	assert.Equal(t, &grpc.User{Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9}, deadLetter.GetUser())
+	assert.Equal(t, int32(1), deadLetter.GetAttempts())
+	assert.Equal(t, "unexpected status 410 Gone", deadLetter.GetLastError())
+
+	_, err = time.Parse(time.RFC3339, deadLetter.GetFailedAt())
+	assert.NoError(t, err)
+
+	res, err = handler.ListDeadLetters(context.Background(), &grpc.ListDeadLettersRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.GetDeadLetters(), "dead letters are scoped to their tenant")
+
+	_, err = handler.ReplayDeadLetters(acme, &grpc.ReplayDeadLettersRequest{Ids: []int64{0}})
+	assert.Equal(t, status.Error(codes.InvalidArgument, "invalid param: ids"), err)
+
+	_, err = handler.ReplayDeadLetters(context.Background(), &grpc.ReplayDeadLettersRequest{Ids: []int64{1}})
+	assert.Equal(t, status.Error(codes.NotFound, "dead letter with ID 1 not found"), err)
+
+	replayed, err := handler.ReplayDeadLetters(acme, &grpc.ReplayDeadLettersRequest{Ids: []int64{2}})
+	require.NoError(t, err)
+	assert.Empty(t, replayed.GetDelivered())
+	require.Len(t, replayed.GetFailed(), 1)
+	assert.Equal(t, int32(2), replayed.GetFailed()[0].GetAttempts())
+
+	accept.Store(true)
+
+	replayed, err = handler.ReplayDeadLetters(acme, &grpc.ReplayDeadLettersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, replayed.GetDelivered())
+	assert.Empty(t, replayed.GetFailed())
+
+	res, err = handler.ListDeadLetters(acme, &grpc.ListDeadLettersRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.GetDeadLetters())
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"encoding/csv"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// Fields lists the user fields a file can hold, in their canonical column order.
+var Fields = []string{"fname", "city", "phone", "height", "married"}
+
+// OptionalFields lists the fields a file may hold besides Fields.
+var OptionalFields = []string{"height_unit"}
+
+type csvReader struct {
+	r *csv.Reader
+	// fields holds the user field of every column.
+	fields []string
+}
+
+// newCSVReader reads the header of r and maps its columns to user fields, either through columns
+// or by their name. Every field of Fields must be present exactly once, those of OptionalFields at
+// most once.
+func newCSVReader(r io.Reader, columns map[string]string) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.InvalidLine{Line: 1, Reason: "missing header"}
+	}
+
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	mapping := make(map[string]string, len(columns))
+	for column, field := range columns {
+		mapping[strings.ToLower(strings.TrimSpace(column))] = strings.ToLower(field)
+	}
+
+	fields := make([]string, len(header))
+	found := make(map[string]bool)
+
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+
+		field, ok := mapping[column]
+		if !ok {
+			field = column
+		}
+
+		if !knownField(field) {
+			return nil, errors.InvalidLine{Line: 1, Reason: fmt.Sprintf("column %q is not a user field", header[i])}
+		}
+
+		if found[field] {
+			return nil, errors.InvalidLine{Line: 1, Reason: fmt.Sprintf("several columns hold %s", field)}
+		}
+
+		fields[i] = field
+		found[field] = true
+	}
+
+	var missing []string
+
+	for _, field := range Fields {
+		if !found[field] {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.InvalidLine{Line: 1, Reason: errors.MissingParams{Params: missing}.Error()}
+	}
+
+	return &csvReader{r: cr, fields: fields}, nil
+}
+
+func knownField(field string) bool {
+	return slices.Contains(Fields, field) || slices.Contains(OptionalFields, field)
+}
+
+func (c *csvReader) next() (row, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return row{}, io.EOF
+	}
+
+	if err != nil {
+		return row{}, csvError(err)
+	}
+
+	line, _ := c.r.FieldPos(0)
+
+	usr := &models.UserRequest{}
+
+	var invalid []string
+
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch c.fields[i] {
+		case "fname":
+			usr.Fname = &value
+		case "city":
+			usr.City = &value
+		case "phone":
+			usr.Phone = &value
+		case "height":
+			height, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				invalid = append(invalid, "height")
+
+				continue
+			}
+
+			usr.Height = &height
+		case "height_unit":
+			unit, ok := models.ParseHeightUnit(value)
+			if !ok {
+				invalid = append(invalid, "height_unit")
+
+				continue
+			}
+
+			usr.HeightUnit = unit
+		case "married":
+			married, err := strconv.ParseBool(value)
+			if err != nil {
+				invalid = append(invalid, "married")
+
+				continue
+			}
+
+			usr.Married = &married
+		}
+	}
+
+	if len(invalid) > 0 {
+		return row{}, errors.InvalidLine{Line: line, Reason: errors.InvalidParams{Params: invalid}.Error()}
+	}
+
+	return row{line: line, user: usr}, nil
+}
+
+// csvError turns the parse errors of a single record into an errors.InvalidLine, so that the
+// remaining records are still read.
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if stdErrors.As(err, &parseErr) {
+		return errors.InvalidLine{Line: parseErr.StartLine, Reason: parseErr.Err.Error()}
+	}
+
+	return err
+}
@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/normalize"
+	"github.com/ssshekhu53/user-detail-management/service"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+const (
+	DefaultBatchSize = 100
+	// maxReportedErrors bounds the line errors kept in a Summary; Failed still counts them all.
+	maxReportedErrors = 1000
+)
+
+type Options struct {
+	Format Format
+	// Columns maps CSV header names to user fields (fname, city, phone, height, married,
+	// height_unit). Headers that already are field names need no entry.
+	Columns map[string]string
+	// DryRun validates the file and reports what would change without writing anything.
+	DryRun bool
+	// BatchSize is the number of valid rows applied together, in a single store operation. It
+	// defaults to DefaultBatchSize.
+	BatchSize int
+	// HeightUnit is the unit of the heights of the rows that do not name one.
+	HeightUnit models.HeightUnit
+}
+
+type Summary struct {
+	Rows    int
+	Created int
+	Updated int
+	Failed  int
+	Errors  []errors.InvalidLine
+	DryRun  bool
+}
+
+// row is a line of the imported file holding a user.
+type row struct {
+	line int
+	user *models.UserRequest
+}
+
+// rowReader yields the rows of a file. Rows that cannot be read are reported as an
+// errors.InvalidLine, after which reading may go on; any other error ends the import.
+type rowReader interface {
+	next() (row, error)
+}
+
+type importer struct {
+	userService service.User
+	opts        Options
+	summary     Summary
+	// seen holds the phone numbers, as normalized, applied by a dry run, which the store does not
+	// know about.
+	seen map[string]bool
+}
+
+// Import reads users from r and creates them, or updates the user holding the same phone number.
+// Every row is checked with the models validators; invalid rows are reported in the Summary by
+// line number and do not stop the import. The returned error is only set when the file as a whole
+// cannot be read, in which case the rows applied so far stay applied.
+func Import(ctx context.Context, r io.Reader, userService service.User, opts Options) (Summary, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	im := &importer{userService: userService, opts: opts, summary: Summary{DryRun: opts.DryRun}, seen: make(map[string]bool)}
+
+	var (
+		rows rowReader
+		err  error
+	)
+
+	switch opts.Format {
+	case FormatCSV:
+		rows, err = newCSVReader(r, opts.Columns)
+	case FormatNDJSON:
+		rows = newNDJSONReader(r)
+	default:
+		err = fmt.Errorf("unknown import format %q", opts.Format)
+	}
+
+	if err != nil {
+		return im.summary, err
+	}
+
+	batch := make([]row, 0, opts.BatchSize)
+
+	for {
+		rw, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+
+		if lineErr, ok := err.(errors.InvalidLine); ok {
+			im.summary.Rows++
+			im.fail(lineErr)
+
+			continue
+		}
+
+		if err != nil {
+			return im.summary, err
+		}
+
+		im.summary.Rows++
+
+		if rw.user.HeightUnit == models.HeightUnitUnspecified {
+			rw.user.HeightUnit = opts.HeightUnit
+		}
+
+		if err := validate(rw.user); err != nil {
+			im.fail(errors.InvalidLine{Line: rw.line, Reason: err.Error()})
+
+			continue
+		}
+
+		batch = append(batch, rw)
+
+		if len(batch) == opts.BatchSize {
+			if err := im.apply(ctx, batch); err != nil {
+				return im.summary, err
+			}
+
+			batch = batch[:0]
+		}
+	}
+
+	if err := im.apply(ctx, batch); err != nil {
+		return im.summary, err
+	}
+
+	logging.FromContext(ctx).Info("import completed", "rows", im.summary.Rows, "created", im.summary.Created,
+		"updated", im.summary.Updated, "failed", im.summary.Failed, "dry_run", opts.DryRun)
+
+	return im.summary, nil
+}
+
+func validate(usr *models.UserRequest) error {
+	err := usr.ValidateMissingParam()
+	if err != nil {
+		return err
+	}
+
+	return usr.ValidateInvalidParam()
+}
+
+func (im *importer) apply(ctx context.Context, batch []row) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if im.opts.DryRun {
+		for _, rw := range batch {
+			created, err := im.plan(ctx, rw.user)
+			im.count(rw, created, err)
+		}
+
+		return nil
+	}
+
+	users := make([]*models.UserRequest, 0, len(batch))
+
+	for _, rw := range batch {
+		users = append(users, rw.user)
+	}
+
+	for i, result := range im.userService.Upsert(ctx, users) {
+		im.count(batch[i], result.Created, result.Err)
+	}
+
+	return nil
+}
+
+// count records the outcome of applying rw in the summary.
+func (im *importer) count(rw row, created bool, err error) {
+	switch {
+	case err != nil:
+		im.fail(errors.InvalidLine{Line: rw.line, Reason: err.Error()})
+	case created:
+		im.summary.Created++
+	default:
+		im.summary.Updated++
+	}
+}
+
+// plan reports what Upsert would do with usr, accounting for the rows of the file already planned.
+func (im *importer) plan(ctx context.Context, usr *models.UserRequest) (bool, error) {
+	matches := len(im.userService.GetByPhone(ctx, *usr.Phone))
+	if matches > 1 {
+		return false, errors.AmbiguousPhone{Matches: matches}
+	}
+
+	phone := normalize.Phone(*usr.Phone)
+
+	created := matches == 0 && !im.seen[phone]
+	im.seen[phone] = true
+
+	return created, nil
+}
+
+func (im *importer) fail(err errors.InvalidLine) {
+	im.summary.Failed++
+
+	if len(im.summary.Errors) < maxReportedErrors {
+		im.summary.Errors = append(im.summary.Errors, err)
+	}
+}
@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/service"
+	serviceUser "github.com/ssshekhu53/user-detail-management/service/user"
+	"github.com/ssshekhu53/user-detail-management/store"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+)
+
+func newService(t *testing.T, users ...models.UserRequest) service.User {
+	t.Helper()
+
+	svc := serviceUser.New(storeUser.New())
+
+	for i := range users {
+		_, err := svc.Create(context.Background(), &users[i])
+		require.NoError(t, err)
+	}
+
+	return svc
+}
+
+func existingUser() models.UserRequest {
+	fname, city, phone, height, married := "John", "New York", "1234567890", 180.0, false
+
+	return models.UserRequest{Fname: &fname, City: &city, Phone: &phone, Height: &height, Married: &married}
+}
+
+func Test_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, existingUser())
+
+	file := `First Name,City,Mobile,height,married,height_unit
+Johnny,Boston,1234567890,1.75,true,
+Jane,San Francisco,0987654321,165,false,cm
+Jim,Chicago,123,1.5,false,
+Jack,Denver,5555555555,tall,false,
+Jill,Austin,,1.5,true,
+"Jo,Dallas,4444444444,1.5,false,
+`
+
+	summary, err := Import(ctx, strings.NewReader(file), svc, Options{
+		Format:     FormatCSV,
+		Columns:    map[string]string{"First Name": "fname", "mobile": "phone"},
+		HeightUnit: models.HeightUnitMeters,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{
+		Rows:    6,
+		Created: 1,
+		Updated: 1,
+		Failed:  4,
+		Errors: []errors.InvalidLine{
+			{Line: 4, Reason: "invalid param: phone"},
+			{Line: 5, Reason: "invalid param: height"},
+			{Line: 6, Reason: "missing param: phone"},
+			{Line: 7, Reason: `extraneous or missing " in quoted-field`},
+		},
+	}, summary)
+
+	updated, err := svc.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.User{ID: 1, Fname: "Johnny", City: "Boston", Phone: "1234567890", Height: 175, Married: true}, *updated)
+
+	users := svc.Get(ctx)
+	require.Len(t, users, 2)
+	assert.Equal(t, 165.0, users[1].Height)
+}
+
+func Test_ImportCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		columns map[string]string
+		wantErr string
+	}{
+		{"Empty file", "", nil, "line 1: missing header"},
+		{"Unknown column", "fname,city,phone,height,married,email\n", nil, `line 1: column "email" is not a user field`},
+		{"Missing columns", "fname,city\n", nil, "line 1: missing params: phone, height, married"},
+		{"Duplicate column", "fname,city,phone,height,married,Name\n", map[string]string{"name": "fname"}, "line 1: several columns hold fname"},
+		{"Duplicate optional column", "fname,city,phone,height,married,height_unit,unit\n", map[string]string{"unit": "height_unit"}, "line 1: several columns hold height_unit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Import(context.Background(), strings.NewReader(tt.file), newService(t), Options{Format: FormatCSV, Columns: tt.columns})
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_ImportNDJSON(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t)
+
+	file := `{"fname":"John","city":"New York","phone":"1234567890","height":180,"married":false}
+
+{"fname":"Jane","city":"Boston","phone":"0987654321","height":165}
+{"fname":"Jim","city":"Chicago","phone":"5555555555","height":165,"married":true,"email":"jim@example.com"}
+not json
+{"fname":"Jack","city":"Denver","phone":"+1 123-456-7890","height":6.5,"height_unit":"ft","married":true}
+` + `{"fname":"` + strings.Repeat("a", maxLineSize) + `"}
+{"fname":"Jill","city":"Austin","phone":"4444444444","height":158,"married":true}`
+
+	summary, err := Import(ctx, strings.NewReader(file), svc, Options{Format: FormatNDJSON, BatchSize: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, summary.Rows)
+	assert.Equal(t, 2, summary.Created)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 4, summary.Failed)
+
+	lines := make([]int, 0, len(summary.Errors))
+	for _, lineErr := range summary.Errors {
+		lines = append(lines, lineErr.Line)
+	}
+
+	assert.Equal(t, []int{3, 4, 5, 7}, lines)
+	assert.Equal(t, "missing param: married", summary.Errors[0].Reason)
+	assert.Contains(t, summary.Errors[1].Reason, `unknown field "email"`)
+	assert.Equal(t, "line longer than 1048576 bytes", summary.Errors[3].Reason)
+
+	users := svc.Get(ctx)
+	require.Len(t, users, 2)
+	assert.Equal(t, "Jack", users[0].Fname)
+	assert.Equal(t, 198.12, users[0].Height)
+	assert.Equal(t, "Jill", users[1].Fname)
+}
+
+func Test_ImportDryRun(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, existingUser())
+
+	file := `fname,city,phone,height,married
+Johnny,Boston,1234567890,183,true
+Jane,Boston,0987654321,165,false
+Janet,Boston,(098) 765-4321,162,false
+`
+
+	summary, err := Import(ctx, strings.NewReader(file), svc, Options{Format: FormatCSV, DryRun: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Rows: 3, Created: 1, Updated: 2, DryRun: true}, summary)
+
+	users := svc.Get(ctx)
+	require.Len(t, users, 1)
+	assert.Equal(t, "John", users[0].Fname)
+}
+
+func Test_ImportBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	file := `fname,city,phone,height,married
+John,Boston,1111111111,183,true
+Jane,Boston,2222222222,165,false
+Jim,Boston,3333333333,162,false
+`
+
+	// Every batch is applied in a single call. The call is cancelled while the first batch is
+	// applied, so the second batch is never applied.
+	mockService.EXPECT().Upsert(gomock.Any(), gomock.Len(2)).DoAndReturn(func(context.Context, []*models.UserRequest) []store.UpsertResult {
+		cancel()
+
+		return []store.UpsertResult{{Created: true}, {Created: true}}
+	})
+
+	summary, err := Import(ctx, strings.NewReader(file), mockService, Options{Format: FormatCSV, BatchSize: 2})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, summary.Rows)
+	assert.Equal(t, 2, summary.Created)
+}
+
+func Test_ImportAmbiguousPhone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := service.NewMockUser(ctrl)
+	mockService.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return([]store.UpsertResult{{Err: errors.AmbiguousPhone{Matches: 2}}})
+
+	summary, err := Import(context.Background(), strings.NewReader("fname,city,phone,height,married\nJohn,Boston,1111111111,183,true\n"), mockService, Options{Format: FormatCSV})
+	require.NoError(t, err)
+
+	assert.Equal(t, []errors.InvalidLine{{Line: 2, Reason: "phone matches 2 users"}}, summary.Errors)
+}
+
+func Test_ImportUnknownFormat(t *testing.T) {
+	_, err := Import(context.Background(), strings.NewReader(""), newService(t), Options{Format: "xml"})
+
+	assert.EqualError(t, err, `unknown import format "xml"`)
+}
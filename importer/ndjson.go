@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+// maxLineSize bounds the length of an NDJSON line.
+const maxLineSize = 1 << 20
+
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+	eof  bool
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	return &ndjsonReader{r: bufio.NewReader(r)}
+}
+
+func (n *ndjsonReader) next() (row, error) {
+	for !n.eof {
+		data, err := n.readLine()
+		if err != nil {
+			return row{}, err
+		}
+
+		n.line++
+
+		if data == nil {
+			return row{}, errors.InvalidLine{Line: n.line, Reason: fmt.Sprintf("line longer than %d bytes", maxLineSize)}
+		}
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+
+		usr := &models.UserRequest{}
+
+		err = dec.Decode(usr)
+		if err != nil {
+			return row{}, errors.InvalidLine{Line: n.line, Reason: "invalid JSON: " + err.Error()}
+		}
+
+		if dec.More() {
+			return row{}, errors.InvalidLine{Line: n.line, Reason: "invalid JSON: more than one object on the line"}
+		}
+
+		return row{line: n.line, user: usr}, nil
+	}
+
+	return row{}, io.EOF
+}
+
+// readLine returns the next line, or nil when the line exceeds maxLineSize; the rest of such a
+// line is skipped so that reading can go on.
+func (n *ndjsonReader) readLine() ([]byte, error) {
+	var line []byte
+
+	tooLong := false
+
+	for {
+		chunk, err := n.r.ReadSlice('\n')
+
+		if !tooLong {
+			tooLong = len(line)+len(chunk) > maxLineSize
+			line = append(line, chunk...)
+		}
+
+		if tooLong {
+			line = nil
+		}
+
+		switch err {
+		case nil:
+		case bufio.ErrBufferFull:
+			continue
+		case io.EOF:
+			n.eof = true
+		default:
+			return nil, err
+		}
+
+		if tooLong {
+			return nil, nil
+		}
+
+		if line == nil {
+			line = []byte{}
+		}
+
+		return line, nil
+	}
+}
@@ -0,0 +1,77 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+const (
+	authorizationMetadataKey = "authorization"
+	apiKeyMetadataKey        = "x-api-key"
+	bearerPrefix             = "bearer "
+)
+
+type authInterceptor struct {
+	authenticator *auth.Authenticator
+}
+
+func NewAuthInterceptor(authenticator *auth.Authenticator) *authInterceptor {
+	return &authInterceptor{authenticator: authenticator}
+}
+
+func (a *authInterceptor) UnaryAuthInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *authInterceptor) StreamAuthInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate verifies the credentials carried in the incoming metadata, preferring a bearer
+// token over an API key, and stores the caller identity in the returned context.
+func (a *authInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var (
+		identity *auth.Identity
+		err      error
+	)
+
+	if values := md.Get(authorizationMetadataKey); len(values) > 0 {
+		value := values[0]
+
+		if len(value) <= len(bearerPrefix) || !strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			err = errors.Unauthenticated{Reason: "authorization must use the Bearer scheme"}
+		} else {
+			identity, err = a.authenticator.VerifyToken(strings.TrimSpace(value[len(bearerPrefix):]))
+		}
+	} else if values := md.Get(apiKeyMetadataKey); len(values) > 0 {
+		identity, err = a.authenticator.VerifyAPIKey(values[0])
+	} else {
+		err = errors.Unauthenticated{Reason: "missing credentials"}
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return auth.NewContext(ctx, identity), nil
+}
@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/errors"
+)
+
+type authzInterceptor struct {
+	policy *auth.Policy
+}
+
+// NewAuthzInterceptor returns an interceptor enforcing policy on the identity placed in the
+// context by the auth interceptor, so it has to run after it.
+func NewAuthzInterceptor(policy *auth.Policy) *authzInterceptor {
+	return &authzInterceptor{policy: policy}
+}
+
+func (a *authzInterceptor) UnaryAuthzInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *authzInterceptor) StreamAuthzInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (a *authzInterceptor) authorize(ctx context.Context, method string) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, errors.Unauthenticated{}.Error())
+	}
+
+	if !a.policy.Allowed(method, identity.Roles) {
+		return status.Error(codes.PermissionDenied, errors.PermissionDenied{Method: method}.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+)
+
+// readOnlyMethods lists the UserService methods the reader role may call with the policy shipped
+// in config/policy.json. Every other method is reserved to admins.
+var readOnlyMethods = map[string]bool{
+	"Get":            true,
+	"GetByID":        true,
+	"GetByIDs":       true,
+	"Search":         true,
+	"Export":         true,
+	"Watch":          true,
+	"Suggest":        true,
+	"Stats":          true,
+	"Count":          true,
+	"Exists":         true,
+	"FindDuplicates": true,
+	"ListMerges":     true,
+}
+
+func userServiceMethods() []string {
+	methods := make([]string, 0)
+
+	for _, method := range pb.UserService_ServiceDesc.Methods {
+		methods = append(methods, fmt.Sprintf("/%s/%s", pb.UserService_ServiceDesc.ServiceName, method.MethodName))
+	}
+
+	for _, stream := range pb.UserService_ServiceDesc.Streams {
+		methods = append(methods, fmt.Sprintf("/%s/%s", pb.UserService_ServiceDesc.ServiceName, stream.StreamName))
+	}
+
+	return methods
+}
+
+func Test_UnaryAuthzInterceptor(t *testing.T) {
+	policy, err := auth.LoadPolicy("../config/policy.json")
+	require.NoError(t, err)
+
+	interceptor := NewAuthzInterceptor(policy)
+
+	handler := func(context.Context, any) (any, error) {
+		return "ok", nil
+	}
+
+	type roleCase struct {
+		role    string
+		allowed func(method string) bool
+	}
+
+	roles := []roleCase{
+		{"admin", func(string) bool { return true }},
+		{"reader", func(method string) bool { return readOnlyMethods[method] }},
+		{"guest", func(string) bool { return false }},
+	}
+
+	for _, fullMethod := range userServiceMethods() {
+		method := path.Base(fullMethod)
+
+		for _, rc := range roles {
+			t.Run(fmt.Sprintf("%s as %s", method, rc.role), func(t *testing.T) {
+				ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "alice", Roles: []string{rc.role}})
+
+				resp, err := interceptor.UnaryAuthzInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+
+				if rc.allowed(method) {
+					assert.NoError(t, err)
+					assert.Equal(t, "ok", resp)
+
+					return
+				}
+
+				assert.Nil(t, resp)
+				assert.Equal(t, status.Error(codes.PermissionDenied, "permission denied for "+fullMethod), err)
+			})
+		}
+	}
+
+	t.Run("Without identity", func(t *testing.T) {
+		_, err := interceptor.UnaryAuthzInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"}, handler)
+
+		assert.Equal(t, status.Error(codes.Unauthenticated, "unauthenticated"), err)
+	})
+}
+
+func Test_StreamAuthzInterceptor(t *testing.T) {
+	interceptor := NewAuthzInterceptor(auth.NewPolicy(map[string][]string{"/user.UserService/Export": {"admin"}}))
+	info := &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}
+
+	tests := []struct {
+		name    string
+		roles   []string
+		wantErr error
+	}{
+		{"Allowed role", []string{"admin"}, nil},
+		{"Denied role", []string{"reader"}, status.Error(codes.PermissionDenied, "permission denied for /user.UserService/Export")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "alice", Roles: tt.roles})
+
+			err := interceptor.StreamAuthzInterceptor(nil, &mockServerStream{ctx: ctx}, info, func(any, grpc.ServerStream) error {
+				return nil
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_PolicyCoversEveryMethod(t *testing.T) {
+	policy, err := auth.LoadPolicy("../config/policy.json")
+	require.NoError(t, err)
+
+	for _, method := range userServiceMethods() {
+		assert.True(t, policy.Allowed(method, []string{"admin"}), "%s is not granted to admin", method)
+	}
+}
@@ -0,0 +1,196 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+// IdempotencyKeyMetadataKey carries the key making a unary call idempotent.
+const IdempotencyKeyMetadataKey = "x-idempotency-key"
+
+// DefaultIdempotencyTTL is how long the response of a call is replayed for its idempotency key.
+const DefaultIdempotencyTTL = 10 * time.Minute
+
+// mutatingMethods are the unary calls making changes, the only ones worth replaying: reads are
+// served again, as retrying them is safe and their response may have changed since.
+var mutatingMethods = map[string]bool{
+	"/user.UserService/Create":               true,
+	"/user.UserService/Update":               true,
+	"/user.UserService/Delete":               true,
+	"/user.UserService/Merge":                true,
+	"/user.WebhookService/ReplayDeadLetters": true,
+}
+
+// responseMetadataKeys are the metadata shaping the response of a call, which a replayed call must
+// send as the first one did.
+var responseMetadataKeys = []string{heightUnitMetadataKey, heightPrecisionMetadataKey}
+
+// idempotentCall is the outcome of the first call made with an idempotency key. done is closed
+// once resp and err are set.
+type idempotentCall struct {
+	digest  [sha256.Size]byte
+	done    chan struct{}
+	resp    any
+	err     error
+	expires time.Time
+}
+
+type idempotencyInterceptor struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	calls map[string]*idempotentCall
+	// expiry holds the keys of the completed calls in the order they expire.
+	expiry []string
+}
+
+// NewIdempotencyInterceptor returns an interceptor replaying, for ttl, the successful response of
+// a unary call making changes to any later call made with the same idempotency key by the same
+// caller and tenant. The later calls must send the same request and response metadata.
+// Calls made while the first one runs wait for its outcome. Failed calls are not remembered, so
+// they can be retried.
+func NewIdempotencyInterceptor(ttl time.Duration) *idempotencyInterceptor {
+	return &idempotencyInterceptor{ttl: ttl, now: time.Now, calls: make(map[string]*idempotentCall)}
+}
+
+func (i *idempotencyInterceptor) UnaryIdempotencyInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get(IdempotencyKeyMetadataKey)
+	if len(values) == 0 || values[0] == "" || !mutatingMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return handler(ctx, req)
+	}
+
+	key := i.key(ctx, info.FullMethod, values[0])
+	digest := i.digest(md, data)
+
+	i.mu.Lock()
+
+	i.sweep()
+
+	call, ok := i.calls[key]
+	if !ok {
+		call = &idempotentCall{digest: digest, done: make(chan struct{})}
+		i.calls[key] = call
+	}
+
+	i.mu.Unlock()
+
+	if ok {
+		return i.replay(ctx, call, digest)
+	}
+
+	resp, err := handler(ctx, req)
+
+	i.mu.Lock()
+
+	call.resp, call.err = resp, err
+
+	if err != nil {
+		delete(i.calls, key)
+	} else {
+		call.expires = i.now().Add(i.ttl)
+		i.expiry = append(i.expiry, key)
+	}
+
+	i.mu.Unlock()
+
+	close(call.done)
+
+	return resp, err
+}
+
+// StreamIdempotencyInterceptor leaves streaming calls alone: only unary calls can be replayed.
+func (i *idempotencyInterceptor) StreamIdempotencyInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, ss)
+}
+
+func (i *idempotencyInterceptor) replay(ctx context.Context, call *idempotentCall, digest [sha256.Size]byte) (any, error) {
+	if call.digest != digest {
+		return nil, status.Error(codes.InvalidArgument, errors.IdempotencyKeyReused{}.Error())
+	}
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	logging.FromContext(ctx).Debug("idempotent call replayed")
+
+	return call.resp, call.err
+}
+
+// key scopes an idempotency key to the caller, the tenant and the method, so that keys chosen by
+// different clients never collide.
+func (i *idempotencyInterceptor) key(ctx context.Context, method, idempotencyKey string) string {
+	var subject string
+
+	if identity, ok := auth.FromContext(ctx); ok {
+		subject = identity.Subject
+	}
+
+	return strings.Join([]string{subject, tenant.FromContext(ctx), method, idempotencyKey}, "\x00")
+}
+
+// digest sums up a call from its request, as marshalled in data, and the metadata shaping its
+// response, such as the unit heights are returned in.
+func (i *idempotencyInterceptor) digest(md metadata.MD, data []byte) [sha256.Size]byte {
+	h := sha256.New()
+
+	for _, key := range responseMetadataKeys {
+		h.Write([]byte(strings.Join(md.Get(key), ",")))
+		h.Write([]byte{0})
+	}
+
+	h.Write(data)
+
+	var digest [sha256.Size]byte
+
+	h.Sum(digest[:0])
+
+	return digest
+}
+
+// sweep forgets the calls whose response expired. It must be called with i.mu held.
+func (i *idempotencyInterceptor) sweep() {
+	now := i.now()
+
+	for len(i.expiry) > 0 {
+		call, ok := i.calls[i.expiry[0]]
+		if ok && call.expires.After(now) {
+			return
+		}
+
+		if ok {
+			delete(i.calls, i.expiry[0])
+		}
+
+		i.expiry = i.expiry[1:]
+	}
+}
@@ -0,0 +1,252 @@
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+// countingHandler creates users with consecutive IDs, failing while fail is set.
+type countingHandler struct {
+	mu    sync.Mutex
+	calls int
+	fail  bool
+}
+
+func (h *countingHandler) handle(_ context.Context, _ any) (any, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.calls++
+
+	if h.fail {
+		return nil, status.Error(codes.Unavailable, "store unavailable")
+	}
+
+	return &pb.User{Id: int32(h.calls)}, nil
+}
+
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("x-idempotency-key", key))
+}
+
+func Test_UnaryIdempotencyInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Create"}
+	john := &pb.UserRequest{Fname: "John", Phone: "1234567890"}
+	jane := &pb.UserRequest{Fname: "Jane", Phone: "0987654321"}
+
+	t.Run("Without key", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor.UnaryIdempotencyInterceptor(context.Background(), john, info, h.handle)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, h.calls)
+	})
+
+	t.Run("Replayed", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+
+		first, err := interceptor.UnaryIdempotencyInterceptor(ctx, john, info, h.handle)
+		require.NoError(t, err)
+
+		second, err := interceptor.UnaryIdempotencyInterceptor(ctx, &pb.UserRequest{Fname: "John", Phone: "1234567890"}, info, h.handle)
+		require.NoError(t, err)
+
+		assert.Same(t, first, second)
+		assert.Equal(t, 1, h.calls)
+
+		_, err = interceptor.UnaryIdempotencyInterceptor(ctx, jane, info, h.handle)
+		assert.Equal(t, status.Error(codes.InvalidArgument, "idempotency key reused with a different request"), err)
+
+		other := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Update"}
+		_, err = interceptor.UnaryIdempotencyInterceptor(ctx, john, other, h.handle)
+		require.NoError(t, err)
+		assert.Equal(t, 2, h.calls, "keys are scoped to the method")
+	})
+
+	t.Run("Response metadata", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+
+		_, err := interceptor.UnaryIdempotencyInterceptor(metadata.NewIncomingContext(ctx, metadata.Pairs(
+			"x-idempotency-key", "key-1", "x-height-unit", "ft", "x-height-precision", "1",
+		)), john, info, h.handle)
+		require.NoError(t, err)
+
+		for _, md := range []metadata.MD{
+			metadata.Pairs("x-idempotency-key", "key-1"),
+			metadata.Pairs("x-idempotency-key", "key-1", "x-height-unit", "cm", "x-height-precision", "1"),
+			metadata.Pairs("x-idempotency-key", "key-1", "x-height-unit", "ft", "x-height-precision", "2"),
+		} {
+			_, err = interceptor.UnaryIdempotencyInterceptor(metadata.NewIncomingContext(ctx, md), john, info, h.handle)
+			assert.Equal(t, status.Error(codes.InvalidArgument, "idempotency key reused with a different request"), err,
+				"a replayed response is shaped by the metadata of the first call")
+		}
+
+		assert.Equal(t, 1, h.calls)
+	})
+
+	t.Run("Reads are not replayed", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+		search := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Search"}
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor.UnaryIdempotencyInterceptor(ctx, &pb.Filters{City: "Boston"}, search, h.handle)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, h.calls)
+		assert.Empty(t, interceptor.calls)
+	})
+
+	t.Run("Scoped to caller and tenant", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+
+		contexts := []context.Context{
+			ctx,
+			tenant.NewContext(ctx, "acme"),
+			auth.NewContext(ctx, &auth.Identity{Subject: "alice"}),
+			auth.NewContext(ctx, &auth.Identity{Subject: "bob"}),
+		}
+
+		for _, ctx := range contexts {
+			_, err := interceptor.UnaryIdempotencyInterceptor(ctx, john, info, h.handle)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, len(contexts), h.calls)
+	})
+
+	t.Run("Failures are not remembered", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		h := &countingHandler{fail: true}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+
+		_, err := interceptor.UnaryIdempotencyInterceptor(ctx, john, info, h.handle)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		h.fail = false
+
+		resp, err := interceptor.UnaryIdempotencyInterceptor(ctx, john, info, h.handle)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), resp.(*pb.User).GetId())
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		interceptor := NewIdempotencyInterceptor(time.Minute)
+		now := time.Now()
+		interceptor.now = func() time.Time { return now }
+
+		h := &countingHandler{}
+		ctx := withIdempotencyKey(context.Background(), "key-1")
+
+		_, err := interceptor.UnaryIdempotencyInterceptor(ctx, john, info, h.handle)
+		require.NoError(t, err)
+
+		now = now.Add(time.Minute)
+
+		_, err = interceptor.UnaryIdempotencyInterceptor(ctx, jane, info, h.handle)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, h.calls)
+		assert.Len(t, interceptor.calls, 1)
+	})
+}
+
+func Test_UnaryIdempotencyInterceptorConcurrentCalls(t *testing.T) {
+	interceptor := NewIdempotencyInterceptor(time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Create"}
+	ctx := withIdempotencyKey(context.Background(), "key-1")
+	req := &pb.UserRequest{Fname: "John"}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	calls := 0
+
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		close(started)
+		<-release
+
+		return &pb.User{Id: 1}, nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		replays [3]any
+	)
+
+	go func() {
+		_, _ = interceptor.UnaryIdempotencyInterceptor(ctx, req, info, handler)
+	}()
+
+	<-started
+
+	for i := range replays {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			replays[i], _ = interceptor.UnaryIdempotencyInterceptor(ctx, req, info, handler)
+		}(i)
+	}
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+
+	for _, resp := range replays {
+		assert.Equal(t, int32(1), resp.(*pb.User).GetId())
+	}
+
+	canceled, cancel := context.WithCancel(withIdempotencyKey(context.Background(), "key-2"))
+
+	blocked := make(chan struct{})
+
+	go func() {
+		_, _ = interceptor.UnaryIdempotencyInterceptor(withIdempotencyKey(context.Background(), "key-2"), req, info,
+			func(context.Context, any) (any, error) {
+				<-blocked
+
+				return &pb.User{Id: 2}, nil
+			})
+	}()
+
+	assert.Eventually(t, func() bool {
+		interceptor.mu.Lock()
+		defer interceptor.mu.Unlock()
+
+		return len(interceptor.calls) == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	_, err := interceptor.UnaryIdempotencyInterceptor(canceled, req, info, handler)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+
+	close(blocked)
+}
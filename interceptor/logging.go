@@ -2,18 +2,27 @@ package interceptor
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/redact"
 )
 
 type loggingInterceptor struct {
-	logger *log.Logger
+	logPayloads bool
 }
 
-func NewLoggingInterceptor(logger *log.Logger) *loggingInterceptor {
-	return &loggingInterceptor{logger: logger}
+// NewLoggingInterceptor logs every completed RPC with the logger of its context, so that entries
+// hold the request ID, tenant and trace of the call. With logPayloads, the request and response
+// are added to the entry when the logger is at debug level, with personal data redacted.
+func NewLoggingInterceptor(logPayloads bool) *loggingInterceptor {
+	return &loggingInterceptor{logPayloads: logPayloads}
 }
 
 func (l *loggingInterceptor) UnaryLoggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
@@ -21,9 +30,61 @@ func (l *loggingInterceptor) UnaryLoggingInterceptor(ctx context.Context, req an
 
 	h, err := handler(ctx, req)
 
-	end := time.Now()
+	var payloads []any
+	if l.logPayloads && logging.FromContext(ctx).Enabled(ctx, slog.LevelDebug) {
+		payloads = append(payloads, slog.Any("request", redact.Payload(req)))
+
+		if err == nil {
+			payloads = append(payloads, slog.Any("response", redact.Payload(h)))
+		}
+	}
 
-	l.logger.Printf("RPC: %s, Duration: %s, Error: %v", info.FullMethod, end.Sub(start), err)
+	l.log(ctx, info.FullMethod, time.Since(start), err, payloads...)
 
 	return h, err
 }
+
+func (l *loggingInterceptor) StreamLoggingInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	l.log(ss.Context(), info.FullMethod, time.Since(start), err)
+
+	return err
+}
+
+func (l *loggingInterceptor) log(ctx context.Context, method string, duration time.Duration, err error, extra ...any) {
+	code := status.Code(err)
+
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", duration),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redact.Text(status.Convert(err).Message())))
+	}
+
+	attrs = append(attrs, extra...)
+
+	logging.FromContext(ctx).Log(ctx, levelForCode(code), "rpc completed", attrs...)
+}
+
+// levelForCode logs failures caused by the server at error level, failures caused by the client
+// at warn level and successful calls at info level.
+func levelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
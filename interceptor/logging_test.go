@@ -0,0 +1,159 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/logging"
+)
+
+func Test_UnaryLoggingInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantLevel string
+		wantCode  string
+		wantError any
+	}{
+		{"Success", nil, "INFO", "OK", nil},
+		{"Client error", status.Error(codes.NotFound, "user with ID 1 not found"), "WARN", "NotFound", "user with ID 1 not found"},
+		{"Server error", status.Error(codes.Internal, "boom"), "ERROR", "Internal", "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			interceptor := NewLoggingInterceptor(false)
+
+			logger := logging.New(&buf, slog.LevelDebug).With("request_id", "req-1", "tenant", "acme")
+			ctx := logging.NewContext(context.Background(), logger)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+
+			_, err := interceptor.UnaryLoggingInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetByID"}, func(context.Context, any) (any, error) {
+				return nil, tt.err
+			})
+			assert.Equal(t, tt.err, err)
+
+			var record map[string]any
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			assert.Equal(t, tt.wantLevel, record["level"])
+			assert.Equal(t, "rpc completed", record["msg"])
+			assert.Equal(t, "/user.UserService/GetByID", record["method"])
+			assert.Equal(t, tt.wantCode, record["code"])
+			assert.Equal(t, "10.0.0.1:5000", record["peer"])
+			assert.Equal(t, "req-1", record["request_id"])
+			assert.Equal(t, "acme", record["tenant"], "entries hold the attributes of the logger of the call")
+			assert.Equal(t, tt.wantError, record["error"])
+			assert.Contains(t, record, "duration")
+		})
+	}
+}
+
+func Test_StreamLoggingInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+
+	interceptor := NewLoggingInterceptor(true)
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelInfo).With("request_id", "req-1"))
+
+	err := interceptor.StreamLoggingInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}, func(any, grpc.ServerStream) error {
+		return status.Error(codes.Unavailable, "export aborted")
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "rpc completed", record["msg"])
+	assert.Equal(t, "/user.UserService/Export", record["method"])
+	assert.Equal(t, "Unavailable", record["code"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "export aborted", record["error"])
+}
+
+func Test_UnaryLoggingInterceptorPayloads(t *testing.T) {
+	req := &pb.Filters{Fname: "John", City: "Boston", Phone: "5551234567"}
+	resp := &pb.Users{Users: []*pb.User{{Id: 1, Fname: "John", City: "Boston", Phone: "5551234567"}}}
+
+	tests := []struct {
+		name         string
+		level        slog.Level
+		logPayloads  bool
+		wantPayloads bool
+	}{
+		{"Debug level with payloads", slog.LevelDebug, true, true},
+		{"Info level with payloads", slog.LevelInfo, true, false},
+		{"Debug level without payloads", slog.LevelDebug, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			interceptor := NewLoggingInterceptor(tt.logPayloads)
+			ctx := logging.NewContext(context.Background(), logging.New(&buf, tt.level))
+
+			_, err := interceptor.UnaryLoggingInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Search"}, func(context.Context, any) (any, error) {
+				return resp, nil
+			})
+			require.NoError(t, err)
+
+			assert.NotContains(t, buf.String(), "John")
+			assert.NotContains(t, buf.String(), "5551234567")
+
+			var record map[string]any
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+			if !tt.wantPayloads {
+				assert.NotContains(t, record, "request")
+				assert.NotContains(t, record, "response")
+
+				return
+			}
+
+			assert.Equal(t, map[string]any{"fname": "[REDACTED]", "city": "[REDACTED]", "phone": "******4567"}, record["request"])
+			assert.Equal(t, map[string]any{"users": []any{
+				map[string]any{"id": float64(1), "fname": "[REDACTED]", "city": "[REDACTED]", "phone": "******4567"},
+			}}, record["response"])
+		})
+	}
+}
+
+func Test_UnaryLoggingInterceptorRedactsErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	interceptor := NewLoggingInterceptor(false)
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelInfo))
+
+	_, _ = interceptor.UnaryLoggingInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Create"}, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.InvalidArgument, "phone 555-123-4567 is invalid")
+	})
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "phone ********4567 is invalid", record["error"])
+}
+
+func Test_levelForCode(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, levelForCode(codes.OK))
+	assert.Equal(t, slog.LevelWarn, levelForCode(codes.InvalidArgument))
+	assert.Equal(t, slog.LevelWarn, levelForCode(codes.ResourceExhausted))
+	assert.Equal(t, slog.LevelError, levelForCode(codes.Internal))
+	assert.Equal(t, slog.LevelError, levelForCode(codes.Unavailable))
+}
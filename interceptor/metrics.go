@@ -0,0 +1,39 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/metrics"
+)
+
+type metricsInterceptor struct {
+	metrics *metrics.Metrics
+}
+
+func NewMetricsInterceptor(m *metrics.Metrics) *metricsInterceptor {
+	return &metricsInterceptor{metrics: m}
+}
+
+func (m *metricsInterceptor) UnaryMetricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	h, err := handler(ctx, req)
+
+	m.metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+	return h, err
+}
+
+func (m *metricsInterceptor) StreamMetricsInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	m.metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+	return err
+}
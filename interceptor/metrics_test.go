@@ -0,0 +1,56 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/metrics"
+)
+
+func scrapeMetrics(t *testing.T, m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	return string(body)
+}
+
+func Test_UnaryMetricsInterceptor(t *testing.T) {
+	m := metrics.New()
+	interceptor := NewMetricsInterceptor(m)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetByID"}
+
+	_, _ = interceptor.UnaryMetricsInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	_, err := interceptor.UnaryMetricsInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	body := scrapeMetrics(t, m)
+
+	assert.Contains(t, body, `user_detail_management_rpc_handled_total{code="OK",method="/user.UserService/GetByID"} 1`)
+	assert.Contains(t, body, `user_detail_management_rpc_handled_total{code="NotFound",method="/user.UserService/GetByID"} 1`)
+	assert.Contains(t, body, `user_detail_management_rpc_duration_seconds_count{method="/user.UserService/GetByID"} 2`)
+}
+
+func Test_StreamMetricsInterceptor(t *testing.T) {
+	m := metrics.New()
+	interceptor := NewMetricsInterceptor(m)
+
+	err := interceptor.StreamMetricsInterceptor(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Contains(t, scrapeMetrics(t, m), `user_detail_management_rpc_handled_total{code="OK",method="/user.UserService/Export"} 1`)
+}
@@ -0,0 +1,163 @@
+package interceptor
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Stage names an interceptor of the pipeline.
+type Stage string
+
+const (
+	StageRecovery    Stage = "recovery"
+	StageRequestID   Stage = "request_id"
+	StageTracing     Stage = "tracing"
+	StageAuth        Stage = "auth"
+	StageTenant      Stage = "tenant"
+	StageUnits       Stage = "units"
+	StageAuthz       Stage = "authz"
+	StageRateLimit   Stage = "rate_limit"
+	StageLogging     Stage = "logging"
+	StageMetrics     Stage = "metrics"
+	StageIdempotency Stage = "idempotency"
+)
+
+// stageOrder is the order calls go through the stages, outermost first. Recovery comes first so
+// that a panic anywhere in the chain is caught, and each later stage relies on the context the
+// earlier ones built: the request ID for logs and spans, the identity for the tenant, the
+// authorization decision and the rate limit key.
+var stageOrder = []Stage{
+	StageRecovery,
+	StageRequestID,
+	StageTracing,
+	StageAuth,
+	StageTenant,
+	StageUnits,
+	StageAuthz,
+	StageRateLimit,
+	StageLogging,
+	StageMetrics,
+	StageIdempotency,
+}
+
+// Pipeline composes unary and stream interceptors in the fixed stageOrder, whatever the order they
+// are added in.
+type Pipeline struct {
+	unary    map[Stage]grpc.UnaryServerInterceptor
+	stream   map[Stage]grpc.StreamServerInterceptor
+	disabled map[Stage]bool
+}
+
+// NewPipeline returns an empty pipeline that ignores the stages listed in disabled.
+func NewPipeline(disabled ...Stage) (*Pipeline, error) {
+	p := &Pipeline{
+		unary:    make(map[Stage]grpc.UnaryServerInterceptor),
+		stream:   make(map[Stage]grpc.StreamServerInterceptor),
+		disabled: make(map[Stage]bool),
+	}
+
+	for _, stage := range disabled {
+		if !knownStage(stage) {
+			return nil, fmt.Errorf("interceptor: unknown stage %q", stage)
+		}
+
+		p.disabled[stage] = true
+	}
+
+	return p, nil
+}
+
+// ParseStages reads a comma separated list of stage names such as "logging,metrics".
+func ParseStages(s string) []Stage {
+	var stages []Stage
+
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			stages = append(stages, Stage(strings.ToLower(name)))
+		}
+	}
+
+	return stages
+}
+
+func knownStage(stage Stage) bool {
+	for _, s := range stageOrder {
+		if s == stage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Enabled reports whether stage has not been disabled.
+func (p *Pipeline) Enabled(stage Stage) bool {
+	return !p.disabled[stage]
+}
+
+// Add installs the interceptors of stage, replacing any added before. Either may be nil when the
+// stage only applies to one kind of RPC. Disabled stages are ignored.
+func (p *Pipeline) Add(stage Stage, unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) {
+	if !knownStage(stage) {
+		panic(fmt.Sprintf("interceptor: unknown stage %q", stage))
+	}
+
+	if !p.Enabled(stage) {
+		return
+	}
+
+	if unary != nil {
+		p.unary[stage] = unary
+	}
+
+	if stream != nil {
+		p.stream[stage] = stream
+	}
+}
+
+// Stages lists the stages holding an interceptor, in the order calls go through them.
+func (p *Pipeline) Stages() []Stage {
+	var stages []Stage
+
+	for _, stage := range stageOrder {
+		if p.unary[stage] != nil || p.stream[stage] != nil {
+			stages = append(stages, stage)
+		}
+	}
+
+	return stages
+}
+
+func (p *Pipeline) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+	var interceptors []grpc.UnaryServerInterceptor
+
+	for _, stage := range stageOrder {
+		if i, ok := p.unary[stage]; ok {
+			interceptors = append(interceptors, i)
+		}
+	}
+
+	return interceptors
+}
+
+func (p *Pipeline) StreamInterceptors() []grpc.StreamServerInterceptor {
+	var interceptors []grpc.StreamServerInterceptor
+
+	for _, stage := range stageOrder {
+		if i, ok := p.stream[stage]; ok {
+			interceptors = append(interceptors, i)
+		}
+	}
+
+	return interceptors
+}
+
+// ServerOptions installs the pipeline on a gRPC server.
+func (p *Pipeline) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(p.UnaryInterceptors()...),
+		grpc.ChainStreamInterceptor(p.StreamInterceptors()...),
+	}
+}
@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// recordingStage returns interceptors appending name to calls when they run.
+func recordingStage(name string, calls *[]string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		*calls = append(*calls, name)
+
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		*calls = append(*calls, name)
+
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+// runUnary calls the unary interceptors of p as grpc.ChainUnaryInterceptor would.
+func runUnary(p *Pipeline) {
+	interceptors := p.UnaryInterceptors()
+
+	var next func(i int) grpc.UnaryHandler
+	next = func(i int) grpc.UnaryHandler {
+		return func(ctx context.Context, req any) (any, error) {
+			if i == len(interceptors) {
+				return nil, nil
+			}
+
+			return interceptors[i](ctx, req, &grpc.UnaryServerInfo{}, next(i+1))
+		}
+	}
+
+	_, _ = next(0)(context.Background(), nil)
+}
+
+func Test_Pipeline(t *testing.T) {
+	var calls []string
+
+	p, err := NewPipeline(StageTracing)
+	require.NoError(t, err)
+
+	for _, stage := range []Stage{StageMetrics, StageLogging, StageAuth, StageTracing, StageRequestID, StageRecovery} {
+		unary, stream := recordingStage(string(stage), &calls)
+		p.Add(stage, unary, stream)
+	}
+
+	unary, _ := recordingStage(string(StageRateLimit), &calls)
+	p.Add(StageRateLimit, unary, nil)
+
+	assert.Equal(t, []Stage{StageRecovery, StageRequestID, StageAuth, StageRateLimit, StageLogging, StageMetrics}, p.Stages())
+	assert.True(t, p.Enabled(StageAuth))
+	assert.False(t, p.Enabled(StageTracing))
+
+	runUnary(p)
+
+	assert.Equal(t, []string{"recovery", "request_id", "auth", "rate_limit", "logging", "metrics"}, calls)
+	assert.Len(t, p.StreamInterceptors(), 5, "rate limiting was only added for unary calls")
+	assert.Len(t, p.ServerOptions(), 2)
+}
+
+func Test_NewPipeline(t *testing.T) {
+	_, err := NewPipeline(ParseStages("logging, Metrics")...)
+	assert.NoError(t, err)
+
+	_, err = NewPipeline(ParseStages("logging,audit")...)
+	assert.EqualError(t, err, `interceptor: unknown stage "audit"`)
+}
+
+func Test_ParseStages(t *testing.T) {
+	assert.Nil(t, ParseStages(""))
+	assert.Equal(t, []Stage{StageLogging, StageMetrics}, ParseStages(" logging,,METRICS "))
+}
+
+func Test_PipelineAddUnknownStage(t *testing.T) {
+	p, err := NewPipeline()
+	require.NoError(t, err)
+
+	assert.Panics(t, func() { p.Add("audit", nil, nil) })
+}
@@ -0,0 +1,124 @@
+package interceptor
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/ratelimit"
+)
+
+const retryAfterMetadataKey = "retry-after"
+
+// inFlightRetryAfter is suggested to clients rejected because the server is at capacity, where no
+// exact wait time is known.
+const inFlightRetryAfter = time.Second
+
+// openEndedMethods are the streams that stay open for as long as their client wants. They need an
+// in-flight slot to be admitted but give it back right away, so that open streams cannot starve
+// the other calls.
+var openEndedMethods = map[string]bool{
+	"/user.UserService/Watch": true,
+}
+
+type rateLimitInterceptor struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitInterceptor(limiter *ratelimit.Limiter) *rateLimitInterceptor {
+	return &rateLimitInterceptor{limiter: limiter}
+}
+
+func (r *rateLimitInterceptor) UnaryRateLimitInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	release, retryAfter, err := r.admit(ctx, info.FullMethod)
+	if err != nil {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(retryAfterMetadataKey, retryAfterSeconds(retryAfter)))
+
+		return nil, err
+	}
+
+	defer release()
+
+	return handler(ctx, req)
+}
+
+func (r *rateLimitInterceptor) StreamRateLimitInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, retryAfter, err := r.admit(ss.Context(), info.FullMethod)
+	if err != nil {
+		ss.SetTrailer(metadata.Pairs(retryAfterMetadataKey, retryAfterSeconds(retryAfter)))
+
+		return err
+	}
+
+	if openEndedMethods[info.FullMethod] {
+		release()
+	} else {
+		defer release()
+	}
+
+	return handler(srv, ss)
+}
+
+// admit applies the per client token bucket of method and then claims an in-flight slot.
+func (r *rateLimitInterceptor) admit(ctx context.Context, method string) (func(), time.Duration, error) {
+	allowed, retryAfter := r.limiter.Allow(clientKey(ctx), method)
+	if !allowed {
+		return nil, retryAfter, resourceExhausted(errors.RateLimited{RetryAfter: retryAfter}, retryAfter)
+	}
+
+	release, ok := r.limiter.Acquire()
+	if !ok {
+		err := errors.RateLimited{RetryAfter: inFlightRetryAfter}
+
+		return nil, inFlightRetryAfter, resourceExhausted(err, inFlightRetryAfter)
+	}
+
+	return release, 0, nil
+}
+
+func resourceExhausted(err error, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+
+	detailed, detailsErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// retryAfterSeconds renders d as whole seconds, rounded up, as in the HTTP Retry-After header.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// clientKey identifies the caller for rate limiting: the authenticated identity when there is one
+// (so every API key or token subject gets its own bucket), the peer IP address otherwise.
+func clientKey(ctx context.Context) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		return identity.Method + ":" + identity.Subject
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+
+		return "peer:" + host
+	}
+
+	return "unknown"
+}
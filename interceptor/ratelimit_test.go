@@ -0,0 +1,188 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/ratelimit"
+)
+
+type mockTransportStream struct {
+	grpc.ServerTransportStream
+
+	trailer metadata.MD
+}
+
+func (m *mockTransportStream) SetTrailer(md metadata.MD) error {
+	m.trailer = metadata.Join(m.trailer, md)
+
+	return nil
+}
+
+func peerContext(addr string) context.Context {
+	tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+}
+
+func Test_clientKey(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"Identity", auth.NewContext(peerContext("10.0.0.1:5000"), &auth.Identity{Subject: "batch-job", Method: auth.MethodAPIKey}), "api_key:batch-job"},
+		{"Peer address ignores port", peerContext("10.0.0.1:5000"), "peer:10.0.0.1"},
+		{"Unknown", context.Background(), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, clientKey(tt.ctx))
+		})
+	}
+}
+
+func Test_UnaryRateLimitInterceptor(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Default: ratelimit.Limit{Rate: 0.5, Burst: 1}})
+	interceptor := NewRateLimitInterceptor(limiter)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"}
+
+	handler := func(context.Context, any) (any, error) {
+		return "ok", nil
+	}
+
+	stream := &mockTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(peerContext("10.0.0.1:5000"), stream)
+
+	resp, err := interceptor.UnaryRateLimitInterceptor(ctx, nil, info, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	resp, err = interceptor.UnaryRateLimitInterceptor(ctx, nil, info, handler)
+	assert.Nil(t, resp)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Contains(t, st.Message(), "rate limit exceeded")
+	assert.Equal(t, []string{"2"}, stream.trailer.Get("retry-after"))
+
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.InDelta(t, 2*time.Second, retryInfo.RetryDelay.AsDuration(), float64(10*time.Millisecond))
+
+	resp, err = interceptor.UnaryRateLimitInterceptor(peerContext("10.0.0.2:5000"), nil, info, handler)
+	assert.NoError(t, err, "other clients are not affected")
+	assert.Equal(t, "ok", resp)
+}
+
+func Test_UnaryRateLimitInterceptorMaxInFlight(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{MaxInFlight: 1})
+	interceptor := NewRateLimitInterceptor(limiter)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"}
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = interceptor.UnaryRateLimitInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			close(started)
+			<-unblock
+
+			return nil, nil
+		})
+	}()
+
+	<-started
+
+	_, err := interceptor.UnaryRateLimitInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	close(unblock)
+	<-done
+
+	_, err = interceptor.UnaryRateLimitInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+}
+
+type trailerServerStream struct {
+	mockServerStream
+
+	trailer metadata.MD
+}
+
+func (s *trailerServerStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func Test_StreamRateLimitInterceptor(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Default: ratelimit.Limit{Rate: 1, Burst: 1}})
+	interceptor := NewRateLimitInterceptor(limiter)
+	info := &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}
+	stream := &trailerServerStream{mockServerStream: mockServerStream{ctx: peerContext("10.0.0.1:5000")}}
+
+	handler := func(any, grpc.ServerStream) error {
+		return nil
+	}
+
+	assert.NoError(t, interceptor.StreamRateLimitInterceptor(nil, stream, info, handler))
+
+	err := interceptor.StreamRateLimitInterceptor(nil, stream, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1"}, stream.trailer.Get("retry-after"))
+}
+
+func Test_StreamRateLimitInterceptorOpenEnded(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{MaxInFlight: 1})
+	interceptor := NewRateLimitInterceptor(limiter)
+	stream := &trailerServerStream{mockServerStream: mockServerStream{ctx: context.Background()}}
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		watch := &grpc.StreamServerInfo{FullMethod: "/user.UserService/Watch", IsServerStream: true}
+		_ = interceptor.StreamRateLimitInterceptor(nil, stream, watch, func(any, grpc.ServerStream) error {
+			close(started)
+			<-unblock
+
+			return nil
+		})
+	}()
+
+	<-started
+
+	// The open Watch holds no slot, so unary calls are still served.
+	_, err := interceptor.UnaryRateLimitInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"},
+		func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+	assert.NoError(t, err)
+
+	close(unblock)
+	<-done
+}
@@ -0,0 +1,64 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/metrics"
+	"github.com/ssshekhu53/user-detail-management/redact"
+)
+
+type recoveryInterceptor struct {
+	metrics *metrics.Metrics
+}
+
+func NewRecoveryInterceptor(m *metrics.Metrics) *recoveryInterceptor {
+	return &recoveryInterceptor{metrics: m}
+}
+
+func (r *recoveryInterceptor) UnaryRecoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (h any, err error) {
+	ctx, requestID := logging.WithRequestIDSlot(ctx)
+
+	defer func() {
+		if p := recover(); p != nil {
+			h, err = nil, r.recovered(ctx, requestID(), info.FullMethod, p)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func (r *recoveryInterceptor) StreamRecoveryInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, requestID := logging.WithRequestIDSlot(ss.Context())
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.recovered(ctx, requestID(), info.FullMethod, p)
+		}
+	}()
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// recovered logs a panic along with its stack trace and turns it into an Internal status. The
+// panic value is kept out of the status so that no internal detail reaches the client. Recovery
+// runs ahead of the request ID interceptor, so the ID assigned further down the chain is passed
+// in explicitly.
+func (r *recoveryInterceptor) recovered(ctx context.Context, requestID, method string, p any) error {
+	logger := logging.FromContext(ctx)
+	if requestID != "" && logging.RequestIDFromContext(ctx) == "" {
+		logger = logger.With("request_id", requestID)
+	}
+
+	logger.Error("panic recovered", "method", method, "panic", redact.Text(fmt.Sprint(p)), "stack", string(debug.Stack()))
+
+	r.metrics.IncPanic(method)
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,101 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/metrics"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+func Test_UnaryRecoveryInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := metrics.New()
+	interceptor := NewRecoveryInterceptor(m)
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelInfo).With("request_id", "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Create"}
+
+	t.Run("Panic becomes Internal", func(t *testing.T) {
+		resp, err := interceptor.UnaryRecoveryInterceptor(ctx, &models.UserRequest{}, info, func(_ context.Context, req any) (any, error) {
+			return nil, req.(*models.UserRequest).ValidateInvalidParam()
+		})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "internal error", status.Convert(err).Message())
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+		assert.Equal(t, "panic recovered", entry["msg"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, "/user.UserService/Create", entry["method"])
+		assert.Contains(t, entry["panic"], "nil pointer dereference")
+		assert.Contains(t, entry["stack"], "ValidateInvalidParam")
+
+		assert.Contains(t, scrapeMetrics(t, m), `user_detail_management_panics_recovered_total{method="/user.UserService/Create"} 1`)
+	})
+
+	t.Run("Calls without panic are untouched", func(t *testing.T) {
+		resp, err := interceptor.UnaryRecoveryInterceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+			return "ok", status.Error(codes.NotFound, "user not found")
+		})
+
+		assert.Equal(t, "ok", resp)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func Test_StreamRecoveryInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := metrics.New()
+	interceptor := NewRecoveryInterceptor(m)
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelInfo))
+
+	err := interceptor.StreamRecoveryInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}, func(any, grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, buf.String(), `"panic":"boom"`)
+	assert.Contains(t, scrapeMetrics(t, m), `user_detail_management_panics_recovered_total{method="/user.UserService/Export"} 1`)
+}
+
+func Test_RecoveryAheadOfRequestID(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := logging.New(&buf, slog.LevelInfo)
+	recovery := NewRecoveryInterceptor(metrics.New())
+	requestID := NewRequestIDInterceptor(logger)
+	ctx := grpc.NewContextWithServerTransportStream(logging.NewContext(context.Background(), logger), &headerTransportStream{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Create"}
+
+	var assigned string
+
+	_, err := recovery.UnaryRecoveryInterceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		return requestID.UnaryRequestIDInterceptor(ctx, req, info, func(ctx context.Context, _ any) (any, error) {
+			assigned = logging.RequestIDFromContext(ctx)
+
+			panic("boom")
+		})
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.NotEmpty(t, assigned)
+	assert.Equal(t, assigned, entry["request_id"])
+}
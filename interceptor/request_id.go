@@ -0,0 +1,57 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+type requestIDInterceptor struct {
+	logger *slog.Logger
+}
+
+// NewRequestIDInterceptor returns an interceptor that tags every call with a request ID and makes a
+// logger carrying it available through logging.FromContext.
+func NewRequestIDInterceptor(logger *slog.Logger) *requestIDInterceptor {
+	return &requestIDInterceptor{logger: logger}
+}
+
+func (r *requestIDInterceptor) UnaryRequestIDInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, id := r.withRequestID(ctx)
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+
+	return handler(ctx, req)
+}
+
+func (r *requestIDInterceptor) StreamRequestIDInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := r.withRequestID(ss.Context())
+
+	_ = ss.SetHeader(metadata.Pairs(requestIDMetadataKey, id))
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// withRequestID reuses a well formed x-request-id sent by the client so that calls can be traced
+// across services, and generates a new one otherwise.
+func (r *requestIDInterceptor) withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(requestIDMetadataKey); len(values) > 0 && logging.ValidRequestID(values[0]) {
+		id = values[0]
+	} else {
+		id = logging.NewRequestID()
+	}
+
+	ctx = logging.WithRequestID(ctx, id)
+	ctx = logging.NewContext(ctx, r.logger.With("request_id", id))
+
+	return ctx, id
+}
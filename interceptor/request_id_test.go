@@ -0,0 +1,105 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+)
+
+type headerTransportStream struct {
+	grpc.ServerTransportStream
+
+	header metadata.MD
+}
+
+func (h *headerTransportStream) SetHeader(md metadata.MD) error {
+	h.header = metadata.Join(h.header, md)
+
+	return nil
+}
+
+func Test_UnaryRequestIDInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantReused bool
+	}{
+		{"Reuses client request ID", metadata.Pairs("x-request-id", "client-id-1"), true},
+		{"Generates request ID", metadata.MD{}, false},
+		{"Replaces malformed request ID", metadata.Pairs("x-request-id", "bad id\n"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			interceptor := NewRequestIDInterceptor(logging.New(&buf, slog.LevelInfo))
+			stream := &headerTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), tt.md), stream)
+
+			var gotID string
+
+			handler := func(ctx context.Context, _ any) (any, error) {
+				gotID = logging.RequestIDFromContext(ctx)
+				logging.FromContext(ctx).Info("inside handler")
+
+				return nil, nil
+			}
+
+			_, err := interceptor.UnaryRequestIDInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			require.NoError(t, err)
+
+			if tt.wantReused {
+				assert.Equal(t, "client-id-1", gotID)
+			} else {
+				assert.True(t, logging.ValidRequestID(gotID))
+				assert.NotEqual(t, "bad id\n", gotID)
+			}
+
+			assert.Equal(t, []string{gotID}, stream.header.Get("x-request-id"))
+
+			var record map[string]any
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			assert.Equal(t, gotID, record["request_id"])
+		})
+	}
+}
+
+type headerServerStream struct {
+	mockServerStream
+
+	header metadata.MD
+}
+
+func (h *headerServerStream) SetHeader(md metadata.MD) error {
+	h.header = metadata.Join(h.header, md)
+
+	return nil
+}
+
+func Test_StreamRequestIDInterceptor(t *testing.T) {
+	interceptor := NewRequestIDInterceptor(logging.New(&bytes.Buffer{}, slog.LevelInfo))
+	stream := &headerServerStream{mockServerStream: mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "stream-id"))}}
+
+	var gotID string
+
+	err := interceptor.StreamRequestIDInterceptor(nil, stream, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		gotID = logging.RequestIDFromContext(ss.Context())
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "stream-id", gotID)
+	assert.Equal(t, []string{"stream-id"}, stream.header.Get("x-request-id"))
+}
@@ -0,0 +1,75 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+const tenantMetadataKey = "x-tenant-id"
+
+type tenantInterceptor struct{}
+
+func NewTenantInterceptor() *tenantInterceptor {
+	return &tenantInterceptor{}
+}
+
+func (t *tenantInterceptor) UnaryTenantInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (t *tenantInterceptor) StreamTenantInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := t.resolve(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// resolve determines the tenant of a call. A tenant bound to the caller identity always wins and
+// may only be repeated, not overridden, by the x-tenant-id metadata; callers without one pick the
+// tenant through the metadata. Calls naming no tenant use tenant.Default.
+func (t *tenantInterceptor) resolve(ctx context.Context) (context.Context, error) {
+	var requested string
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(tenantMetadataKey); len(values) > 0 {
+		requested = values[0]
+	}
+
+	id := requested
+
+	if identity, ok := auth.FromContext(ctx); ok && identity.Tenant != "" {
+		if requested != "" && requested != identity.Tenant {
+			return nil, status.Error(codes.PermissionDenied, errors.TenantMismatch{Tenant: requested}.Error())
+		}
+
+		id = identity.Tenant
+	}
+
+	if id == "" {
+		return ctx, nil
+	}
+
+	if !tenant.Valid(id) {
+		return nil, status.Error(codes.InvalidArgument, errors.InvalidParams{Params: []string{"tenant"}}.Error())
+	}
+
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("tenant", id))
+
+	return tenant.NewContext(ctx, id), nil
+}
@@ -0,0 +1,83 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+func Test_UnaryTenantInterceptor(t *testing.T) {
+	interceptor := NewTenantInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"}
+
+	tests := []struct {
+		name       string
+		identity   *auth.Identity
+		md         metadata.MD
+		wantTenant string
+		wantErr    error
+	}{
+		{"No tenant", nil, metadata.MD{}, tenant.Default, nil},
+		{"Tenant from metadata", nil, metadata.Pairs("x-tenant-id", "acme"), "acme", nil},
+		{"Tenant from identity", &auth.Identity{Subject: "alice", Tenant: "acme"}, metadata.MD{}, "acme", nil},
+		{"Identity without tenant uses metadata", &auth.Identity{Subject: "alice"}, metadata.Pairs("x-tenant-id", "globex"), "globex", nil},
+		{"Metadata repeating identity tenant", &auth.Identity{Subject: "alice", Tenant: "acme"}, metadata.Pairs("x-tenant-id", "acme"), "acme", nil},
+		{
+			"Metadata overriding identity tenant",
+			&auth.Identity{Subject: "alice", Tenant: "acme"}, metadata.Pairs("x-tenant-id", "globex"),
+			"", status.Error(codes.PermissionDenied, "tenant globex not permitted for caller"),
+		},
+		{
+			"Invalid tenant",
+			nil, metadata.Pairs("x-tenant-id", "../etc"),
+			"", status.Error(codes.InvalidArgument, "invalid param: tenant"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			if tt.identity != nil {
+				ctx = auth.NewContext(ctx, tt.identity)
+			}
+
+			var gotTenant string
+
+			handler := func(ctx context.Context, _ any) (any, error) {
+				gotTenant = tenant.FromContext(ctx)
+
+				return nil, nil
+			}
+
+			_, err := interceptor.UnaryTenantInterceptor(ctx, nil, info, handler)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantTenant, gotTenant)
+		})
+	}
+}
+
+func Test_StreamTenantInterceptor(t *testing.T) {
+	interceptor := NewTenantInterceptor()
+	stream := &mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "acme"))}
+
+	var gotTenant string
+
+	err := interceptor.StreamTenantInterceptor(nil, stream, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		gotTenant = tenant.FromContext(ss.Context())
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", gotTenant)
+}
@@ -0,0 +1,114 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/redact"
+)
+
+const tracerName = "github.com/ssshekhu53/user-detail-management/interceptor"
+
+// metadataCarrier adapts incoming gRPC metadata to the carrier propagators read from.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+type tracingInterceptor struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func NewTracingInterceptor(tp trace.TracerProvider, propagator propagation.TextMapPropagator) *tracingInterceptor {
+	return &tracingInterceptor{tracer: tp.Tracer(tracerName), propagator: propagator}
+}
+
+func (t *tracingInterceptor) UnaryTracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, span := t.start(ctx, info.FullMethod)
+	defer span.End()
+
+	h, err := handler(ctx, req)
+
+	t.finish(span, err)
+
+	return h, err
+}
+
+func (t *tracingInterceptor) StreamTracingInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := t.start(ss.Context(), info.FullMethod)
+	defer span.End()
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+	t.finish(span, err)
+
+	return err
+}
+
+// start continues the trace named by the W3C trace context of the incoming metadata, if any. The
+// logger of the returned context logs the IDs of the trace and span, when sampled or continued.
+func (t *tracingInterceptor) start(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = t.propagator.Extract(ctx, metadataCarrier(md.Copy()))
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	attrs := []attribute.KeyValue{
+		semconv.RPCSystemGRPC,
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+
+	if id := logging.RequestIDFromContext(ctx); id != "" {
+		attrs = append(attrs, attribute.String("request_id", id))
+	}
+
+	ctx, span := t.tracer.Start(ctx, strings.TrimPrefix(fullMethod, "/"), trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()))
+	}
+
+	return ctx, span
+}
+
+func (t *tracingInterceptor) finish(span trace.Span, err error) {
+	code := status.Code(err)
+
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+
+	if err != nil {
+		span.SetStatus(otelcodes.Error, redact.Text(status.Convert(err).Message()))
+	}
+}
@@ -0,0 +1,121 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/logging"
+)
+
+func Test_UnaryTracingInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	interceptor := NewTracingInterceptor(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), propagation.TraceContext{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Search"}
+
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		err        error
+		wantTrace  string
+		wantParent bool
+		wantStatus otelcodes.Code
+	}{
+		{
+			"Continues incoming trace",
+			metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+			nil, "4bf92f3577b34da6a3ce929d0e0e4736", true, otelcodes.Unset,
+		},
+		{"Starts new trace", metadata.MD{}, nil, "", false, otelcodes.Unset},
+		{"Records failures", nil, status.Error(codes.NotFound, "user not found"), "", false, otelcodes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var handlerSpan trace.SpanContext
+
+			_, err := interceptor.UnaryTracingInterceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+				handlerSpan = trace.SpanContextFromContext(ctx)
+
+				return nil, tt.err
+			})
+			assert.Equal(t, tt.err, err)
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			assert.Equal(t, "user.UserService/Search", span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, span.SpanContext(), handlerSpan)
+			assert.Equal(t, tt.wantParent, span.Parent().IsValid())
+			assert.Equal(t, tt.wantStatus, span.Status().Code)
+
+			if tt.wantTrace != "" {
+				assert.Equal(t, tt.wantTrace, span.SpanContext().TraceID().String())
+			}
+		})
+	}
+}
+
+func Test_StreamTracingInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	interceptor := NewTracingInterceptor(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), propagation.TraceContext{})
+
+	var handlerSpan trace.SpanContext
+
+	err := interceptor.StreamTracingInterceptor(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/user.UserService/Export"}, func(_ any, ss grpc.ServerStream) error {
+		handlerSpan = trace.SpanContextFromContext(ss.Context())
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, recorder.Ended(), 1) {
+		assert.Equal(t, recorder.Ended()[0].SpanContext(), handlerSpan)
+	}
+}
+
+func Test_TracingInterceptorLogsTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	interceptor := NewTracingInterceptor(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), propagation.TraceContext{})
+
+	var buf bytes.Buffer
+
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelInfo))
+
+	_, err := interceptor.UnaryTracingInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Search"}, func(ctx context.Context, _ any) (any, error) {
+		logging.FromContext(ctx).Info("searched")
+
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	if assert.Len(t, recorder.Ended(), 1) {
+		sc := recorder.Ended()[0].SpanContext()
+		assert.Equal(t, sc.TraceID().String(), record["trace_id"])
+		assert.Equal(t, sc.SpanID().String(), record["span_id"])
+	}
+}
@@ -0,0 +1,79 @@
+package interceptor
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+const (
+	heightUnitMetadataKey      = "x-height-unit"
+	heightPrecisionMetadataKey = "x-height-precision"
+)
+
+type unitsInterceptor struct{}
+
+func NewUnitsInterceptor() *unitsInterceptor {
+	return &unitsInterceptor{}
+}
+
+func (u *unitsInterceptor) UnaryUnitsInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := u.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (u *unitsInterceptor) StreamUnitsInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := u.resolve(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// resolve reads the format heights are returned in from the x-height-unit metadata, holding a unit
+// such as cm or ft, and the x-height-precision metadata, holding the number of decimals they are
+// rounded to. Heights are returned in centimeters, unrounded, when the metadata is missing.
+func (u *unitsInterceptor) resolve(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var (
+		format  models.HeightFormat
+		invalid []string
+	)
+
+	if values := md.Get(heightUnitMetadataKey); len(values) > 0 {
+		unit, ok := models.ParseHeightUnit(values[0])
+		if !ok {
+			invalid = append(invalid, "height_unit")
+		}
+
+		format.Unit = unit
+	}
+
+	if values := md.Get(heightPrecisionMetadataKey); len(values) > 0 {
+		precision, err := strconv.Atoi(values[0])
+		if err != nil || precision < 0 || precision > models.MaxHeightPrecision {
+			invalid = append(invalid, "height_precision")
+		}
+
+		format.Precision = &precision
+	}
+
+	if len(invalid) > 0 {
+		return nil, status.Error(codes.InvalidArgument, errors.InvalidParams{Params: invalid}.Error())
+	}
+
+	return models.NewHeightFormatContext(ctx, format), nil
+}
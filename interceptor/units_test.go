@@ -0,0 +1,76 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+)
+
+func Test_UnaryUnitsInterceptor(t *testing.T) {
+	interceptor := NewUnitsInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/Get"}
+	two := 2
+
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantFormat models.HeightFormat
+		wantErr    error
+	}{
+		{"No metadata", metadata.MD{}, models.HeightFormat{}, nil},
+		{"Unit", metadata.Pairs("x-height-unit", "FT"), models.HeightFormat{Unit: models.HeightUnitFeet}, nil},
+		{
+			"Unit and precision",
+			metadata.Pairs("x-height-unit", "meters", "x-height-precision", "2"),
+			models.HeightFormat{Unit: models.HeightUnitMeters, Precision: &two}, nil,
+		},
+		{
+			"Invalid unit and precision",
+			metadata.Pairs("x-height-unit", "cubits", "x-height-precision", "7"),
+			models.HeightFormat{}, status.Error(codes.InvalidArgument, "invalid params: height_unit, height_precision"),
+		},
+		{"Invalid precision", metadata.Pairs("x-height-precision", "two"), models.HeightFormat{}, status.Error(codes.InvalidArgument, "invalid param: height_precision")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var gotFormat models.HeightFormat
+
+			handler := func(ctx context.Context, _ any) (any, error) {
+				gotFormat = models.HeightFormatFromContext(ctx)
+
+				return nil, nil
+			}
+
+			_, err := interceptor.UnaryUnitsInterceptor(ctx, nil, info, handler)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantFormat, gotFormat)
+		})
+	}
+}
+
+func Test_StreamUnitsInterceptor(t *testing.T) {
+	interceptor := NewUnitsInterceptor()
+	stream := &mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-height-unit", "in"))}
+
+	var gotFormat models.HeightFormat
+
+	err := interceptor.StreamUnitsInterceptor(nil, stream, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		gotFormat = models.HeightFormatFromContext(ss.Context())
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.HeightFormat{Unit: models.HeightUnitInches}, gotFormat)
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+const maxRequestIDLength = 128
+
+var requestIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._:-]+$`)
+
+type requestIDKey struct{}
+
+type requestIDSlotKey struct{}
+
+// NewRequestID returns a random 128 bit request ID rendered as hex.
+func NewRequestID() string {
+	b := make([]byte, 16)
+
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether a request ID supplied by a client is safe to reuse in logs and
+// response headers.
+func ValidRequestID(id string) bool {
+	return len(id) <= maxRequestIDLength && requestIDPattern.MatchString(id)
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if slot, ok := ctx.Value(requestIDSlotKey{}).(*string); ok {
+		*slot = id
+	}
+
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// WithRequestIDSlot lets code running before the request ID is assigned, such as panic recovery,
+// learn it afterwards: the returned func reports the ID later stored with WithRequestID on a
+// context derived from the returned one.
+func WithRequestIDSlot(ctx context.Context) (context.Context, func() string) {
+	slot := new(string)
+
+	return context.WithValue(ctx, requestIDSlotKey{}, slot), func() string { return *slot }
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
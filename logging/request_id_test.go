@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewRequestID(t *testing.T) {
+	id := NewRequestID()
+
+	assert.Len(t, id, 32)
+	assert.True(t, ValidRequestID(id))
+	assert.NotEqual(t, id, NewRequestID())
+}
+
+func Test_ValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"UUID", "3f2c1f9e-7a4b-4c1d-9a57-0c6f1f2b9b1e", true},
+		{"Empty", "", false},
+		{"Newline injection", "abc\ninjected", false},
+		{"Too long", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidRequestID(tt.id))
+		})
+	}
+}
+
+func Test_RequestIDFromContext(t *testing.T) {
+	assert.Empty(t, RequestIDFromContext(context.Background()))
+	assert.Equal(t, "abc", RequestIDFromContext(WithRequestID(context.Background(), "abc")))
+}
+
+func Test_WithRequestIDSlot(t *testing.T) {
+	ctx, requestID := WithRequestIDSlot(context.Background())
+
+	assert.Empty(t, requestID())
+
+	WithRequestID(context.WithValue(ctx, struct{}{}, "derived"), "abc")
+
+	assert.Equal(t, "abc", requestID())
+	assert.Empty(t, RequestIDFromContext(ctx))
+}
@@ -1,27 +1,59 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/ssshekhu53/user-detail-management/interceptor"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/ssshekhu53/user-detail-management/auth"
+	"github.com/ssshekhu53/user-detail-management/events"
 	pb "github.com/ssshekhu53/user-detail-management/grpc"
 	handlerUser "github.com/ssshekhu53/user-detail-management/handler/user"
+	handlerWebhook "github.com/ssshekhu53/user-detail-management/handler/webhook"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/metrics"
+	"github.com/ssshekhu53/user-detail-management/ratelimit"
 	serviceUser "github.com/ssshekhu53/user-detail-management/service/user"
+	"github.com/ssshekhu53/user-detail-management/store"
 	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+	"github.com/ssshekhu53/user-detail-management/tlsconfig"
+	"github.com/ssshekhu53/user-detail-management/tracing"
+	"github.com/ssshekhu53/user-detail-management/webhook"
 )
 
+// shutdownTimeout bounds how long flushing spans and stopping the metrics server may take on exit.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
-	logger := log.New(os.Stdout, "user-detail-management ", log.Ldate|log.Ltime)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logLevel, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(os.Stdout, logLevel).With("service", "user-detail-management")
+	slog.SetDefault(logger)
 
 	grpcPortEnv := os.Getenv("GRPC_PORT")
 
-	_, err := strconv.Atoi(grpcPortEnv)
+	_, err = strconv.Atoi(grpcPortEnv)
 	if err != nil {
 		grpcPortEnv = "9000"
 	}
@@ -30,21 +62,280 @@ func main() {
 
 	lis, err := net.Listen("tcp", grpcPort)
 	if err != nil {
-		logger.Fatalf("Failed to listen: %v", err)
+		fatal(logger, "Failed to listen", err)
+	}
+
+	tracingCfg := tracing.Config{
+		Exporter:    os.Getenv("TRACING_EXPORTER"),
+		File:        os.Getenv("TRACING_FILE"),
+		ServiceName: "user-detail-management",
+	}
+
+	webhookFile := os.Getenv("WEBHOOK_CONFIG_FILE")
+
+	var webhookCfg webhook.Config
+
+	// Changes are only written to the outbox when webhooks are configured, as nothing else drains it.
+	var storeOpts []storeUser.Option
+
+	if webhookFile != "" {
+		webhookCfg, err = webhook.LoadConfig(webhookFile)
+		if err != nil {
+			fatal(logger, "Failed to load webhooks", err)
+		}
+
+		storeOpts = append(storeOpts, storeUser.WithOutbox())
+	}
+
+	if eventsFile := os.Getenv("EVENTS_FILE"); eventsFile != "" {
+		publisher, err := events.OpenNDJSON(eventsFile)
+		if err != nil {
+			fatal(logger, "Failed to open event file", err)
+		}
+
+		defer publisher.Close()
+
+		queueSize, err := strconv.Atoi(os.Getenv("EVENTS_QUEUE_SIZE"))
+		if err != nil || queueSize <= 0 {
+			queueSize = events.DefaultQueueSize
+		}
+
+		// The store calls observers under its lock, so the file is written from the queue.
+		queue := events.NewQueue(publisher, queueSize)
+		defer queue.Close()
+
+		storeOpts = append(storeOpts, storeUser.WithObserver(serviceUser.Publisher(queue)))
+
+		logger.Info("Event publishing enabled", "file", eventsFile, "queue_size", queueSize)
+	}
+
+	var baseStore store.User = storeUser.New(storeOpts...)
+
+	var tp *sdktrace.TracerProvider
+
+	if tracingCfg.Enabled() {
+		tp, err = tracing.New(context.Background(), tracingCfg)
+		if err != nil {
+			fatal(logger, "Failed to configure tracing", err)
+		}
+
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		baseStore = tracing.TraceStore(baseStore, tp)
+
+		logger.Info("Tracing enabled", "exporter", tracingCfg.Exporter)
+	}
+
+	m := metrics.New()
+
+	userStore, err := metrics.InstrumentStore(baseStore, m)
+	if err != nil {
+		fatal(logger, "Failed to instrument user store", err)
 	}
 
-	userStore := storeUser.New()
 	userSvc := serviceUser.New(userStore)
-	userHandler := handlerUser.New(userSvc)
+	maxBackupSize, err := strconv.Atoi(os.Getenv("MAX_BACKUP_SIZE"))
+	if err != nil || maxBackupSize <= 0 {
+		maxBackupSize = handlerUser.DefaultMaxBackupSize
+	}
+
+	userHandler := handlerUser.New(userSvc, handlerUser.WithMaxBackupSize(maxBackupSize))
+
+	pipeline, err := interceptor.NewPipeline(interceptor.ParseStages(os.Getenv("DISABLED_INTERCEPTORS"))...)
+	if err != nil {
+		fatal(logger, "Failed to configure interceptors", err)
+	}
+
+	recoveryInterceptor := interceptor.NewRecoveryInterceptor(m)
+	pipeline.Add(interceptor.StageRecovery, recoveryInterceptor.UnaryRecoveryInterceptor, recoveryInterceptor.StreamRecoveryInterceptor)
+
+	requestIDInterceptor := interceptor.NewRequestIDInterceptor(logger)
+	pipeline.Add(interceptor.StageRequestID, requestIDInterceptor.UnaryRequestIDInterceptor, requestIDInterceptor.StreamRequestIDInterceptor)
+
+	if tracingCfg.Enabled() {
+		tracingInterceptor := interceptor.NewTracingInterceptor(otel.GetTracerProvider(), otel.GetTextMapPropagator())
+		pipeline.Add(interceptor.StageTracing, tracingInterceptor.UnaryTracingInterceptor, tracingInterceptor.StreamTracingInterceptor)
+	}
+
+	authCfg := auth.Config{
+		HMACSecretFile:   os.Getenv("AUTH_JWT_HMAC_SECRET_FILE"),
+		RSAPublicKeyFile: os.Getenv("AUTH_JWT_RSA_PUBLIC_KEY_FILE"),
+		JWKSFile:         os.Getenv("AUTH_JWKS_FILE"),
+		APIKeysFile:      os.Getenv("AUTH_API_KEYS_FILE"),
+		Issuer:           os.Getenv("AUTH_JWT_ISSUER"),
+		Audience:         os.Getenv("AUTH_JWT_AUDIENCE"),
+	}
+
+	authEnabled := authCfg.Enabled() && pipeline.Enabled(interceptor.StageAuth)
+
+	if authEnabled {
+		authenticator, err := auth.New(authCfg)
+		if err != nil {
+			fatal(logger, "Failed to configure authentication", err)
+		}
+
+		authInterceptor := interceptor.NewAuthInterceptor(authenticator)
+		pipeline.Add(interceptor.StageAuth, authInterceptor.UnaryAuthInterceptor, authInterceptor.StreamAuthInterceptor)
+
+		logger.Info("Authentication enabled")
+	}
+
+	tenantInterceptor := interceptor.NewTenantInterceptor()
+	pipeline.Add(interceptor.StageTenant, tenantInterceptor.UnaryTenantInterceptor, tenantInterceptor.StreamTenantInterceptor)
+
+	unitsInterceptor := interceptor.NewUnitsInterceptor()
+	pipeline.Add(interceptor.StageUnits, unitsInterceptor.UnaryUnitsInterceptor, unitsInterceptor.StreamUnitsInterceptor)
+
+	if policyFile := os.Getenv("AUTHZ_POLICY_FILE"); policyFile != "" && pipeline.Enabled(interceptor.StageAuthz) {
+		if !authEnabled {
+			fatal(logger, "AUTHZ_POLICY_FILE requires authentication to be configured", nil)
+		}
 
-	loggingInterceptor := interceptor.NewLoggingInterceptor(logger)
+		policy, err := auth.LoadPolicy(policyFile)
+		if err != nil {
+			fatal(logger, "Failed to load authorization policy", err)
+		}
 
-	s := grpc.NewServer(grpc.UnaryInterceptor(loggingInterceptor.UnaryLoggingInterceptor))
+		authzInterceptor := interceptor.NewAuthzInterceptor(policy)
+		pipeline.Add(interceptor.StageAuthz, authzInterceptor.UnaryAuthzInterceptor, authzInterceptor.StreamAuthzInterceptor)
+
+		logger.Info("Authorization enabled", "policy", policyFile)
+	}
+
+	if rateLimitFile := os.Getenv("RATE_LIMIT_CONFIG_FILE"); rateLimitFile != "" && pipeline.Enabled(interceptor.StageRateLimit) {
+		rateLimitCfg, err := ratelimit.LoadConfig(rateLimitFile)
+		if err != nil {
+			fatal(logger, "Failed to load rate limits", err)
+		}
+
+		rateLimitInterceptor := interceptor.NewRateLimitInterceptor(ratelimit.New(rateLimitCfg))
+		pipeline.Add(interceptor.StageRateLimit, rateLimitInterceptor.UnaryRateLimitInterceptor, rateLimitInterceptor.StreamRateLimitInterceptor)
+
+		logger.Info("Rate limiting enabled", "config", rateLimitFile)
+	}
+
+	// LOG_PAYLOADS only takes effect at debug level; payloads are always redacted.
+	logPayloads, _ := strconv.ParseBool(os.Getenv("LOG_PAYLOADS"))
+	loggingInterceptor := interceptor.NewLoggingInterceptor(logPayloads)
+	pipeline.Add(interceptor.StageLogging, loggingInterceptor.UnaryLoggingInterceptor, loggingInterceptor.StreamLoggingInterceptor)
+
+	metricsInterceptor := interceptor.NewMetricsInterceptor(m)
+	pipeline.Add(interceptor.StageMetrics, metricsInterceptor.UnaryMetricsInterceptor, metricsInterceptor.StreamMetricsInterceptor)
+
+	idempotencyTTL, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || idempotencyTTL <= 0 {
+		idempotencyTTL = interceptor.DefaultIdempotencyTTL
+	}
+
+	idempotencyInterceptor := interceptor.NewIdempotencyInterceptor(idempotencyTTL)
+	pipeline.Add(interceptor.StageIdempotency, idempotencyInterceptor.UnaryIdempotencyInterceptor, idempotencyInterceptor.StreamIdempotencyInterceptor)
+
+	logger.Info("Interceptors configured", "stages", pipeline.Stages())
+
+	serverOpts := pipeline.ServerOptions()
+
+	tlsCfg := tlsconfig.Config{
+		CertFile:     os.Getenv("TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+	}
+
+	if tlsCfg.Enabled() {
+		serverTLS, err := tlsconfig.New(tlsCfg)
+		if err != nil {
+			fatal(logger, "Failed to configure TLS", err)
+		}
+
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(serverTLS)))
+
+		logger.Info("TLS enabled", "mutual_tls", tlsCfg.ClientCAFile != "")
+	}
+
+	metricsPortEnv := os.Getenv("METRICS_PORT")
+
+	_, err = strconv.Atoi(metricsPortEnv)
+	if err != nil {
+		metricsPortEnv = "9090"
+	}
+
+	metricsPort := fmt.Sprintf(":%s", metricsPortEnv)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+
+	metricsServer := &http.Server{Addr: metricsPort, Handler: mux}
+
+	go func() {
+		logger.Info("Starting metrics server", "port", metricsPort)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal(logger, "Failed to serve metrics", err)
+		}
+	}()
+
+	s := grpc.NewServer(serverOpts...)
 
 	pb.RegisterUserServiceServer(s, userHandler)
 
-	logger.Printf("Starting gRPC server on port %s", grpcPort)
-	if err := s.Serve(lis); err != nil {
-		logger.Fatalf("Failed to serve: %v", err)
+	if webhookFile != "" {
+		dispatcher := webhook.New(userStore, webhookCfg)
+
+		go dispatcher.Run(logging.NewContext(ctx, logger))
+
+		pb.RegisterWebhookServiceServer(s, handlerWebhook.New(dispatcher))
+
+		logger.Info("Webhooks enabled", "config", webhookFile, "endpoints", len(webhookCfg.Endpoints))
 	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		logger.Info("Starting gRPC server", "port", grpcPort)
+		serveErr <- s.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		fatal(logger, "Failed to serve", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down")
+
+	// Open-ended Watch streams only end with their clients, so they are cut off once the timeout passes.
+	stopped := make(chan struct{})
+
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		s.Stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to stop metrics server", "error", err)
+	}
+
+	if tp != nil {
+		if err := tp.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+	}
+}
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	if err != nil {
+		logger.Error(msg, "error", err)
+	} else {
+		logger.Error(msg)
+	}
+
+	os.Exit(1)
 }
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	require.Equal(t, 200, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func Test_ObserveRPC(t *testing.T) {
+	m := New()
+
+	m.ObserveRPC("/user.UserService/Get", "OK", 3*time.Millisecond)
+	m.ObserveRPC("/user.UserService/Get", "OK", 30*time.Millisecond)
+	m.ObserveRPC("/user.UserService/GetByID", "NotFound", time.Millisecond)
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, `user_detail_management_rpc_handled_total{code="OK",method="/user.UserService/Get"} 2`)
+	assert.Contains(t, body, `user_detail_management_rpc_handled_total{code="NotFound",method="/user.UserService/GetByID"} 1`)
+	assert.Contains(t, body, `user_detail_management_rpc_duration_seconds_bucket{method="/user.UserService/Get",le="0.005"} 1`)
+	assert.Contains(t, body, `user_detail_management_rpc_duration_seconds_count{method="/user.UserService/Get"} 2`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func Test_IncStoreOperation(t *testing.T) {
+	m := New()
+
+	m.IncStoreOperation("create")
+	m.IncStoreOperation("create")
+
+	assert.Contains(t, scrape(t, m), `user_detail_management_store_operations_total{operation="create"} 2`)
+}
+
+func Test_IncPanic(t *testing.T) {
+	m := New()
+
+	m.IncPanic("/user.UserService/Create")
+
+	assert.Contains(t, scrape(t, m), `user_detail_management_panics_recovered_total{method="/user.UserService/Create"} 1`)
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+var (
+	usersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "store", "users"),
+		"Number of users held by the store, by tenant.",
+		[]string{"tenant"}, nil,
+	)
+	indexSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "store", "index_entries"),
+		"Number of entries of the indexes kept by the store, by index.",
+		[]string{"index"}, nil,
+	)
+)
+
+// storeCollector reads the store usage at scrape time, so the gauges never drift from the data.
+type storeCollector struct {
+	userStore store.User
+}
+
+func (c storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- usersDesc
+	ch <- indexSizeDesc
+}
+
+func (c storeCollector) Collect(ch chan<- prometheus.Metric) {
+	usage := c.userStore.Usage()
+
+	for tenant, users := range usage.UsersByTenant {
+		ch <- prometheus.MustNewConstMetric(usersDesc, prometheus.GaugeValue, float64(users), tenant)
+	}
+
+	for index, size := range usage.IndexSizes {
+		ch <- prometheus.MustNewConstMetric(indexSizeDesc, prometheus.GaugeValue, float64(size), index)
+	}
+}
+
+type instrumentedStore struct {
+	store.User
+
+	metrics *Metrics
+}
+
+// InstrumentStore registers gauges reporting the usage of userStore and returns a store.User
+// counting the mutations applied through it.
+func InstrumentStore(userStore store.User, m *Metrics) (store.User, error) {
+	err := m.registry.Register(storeCollector{userStore: userStore})
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedStore{User: userStore, metrics: m}, nil
+}
+
+func (s *instrumentedStore) Create(ctx context.Context, user *models.User) int {
+	id := s.User.Create(ctx, user)
+
+	s.metrics.IncStoreOperation("create")
+
+	return id
+}
+
+func (s *instrumentedStore) Update(ctx context.Context, user *models.User) {
+	s.User.Update(ctx, user)
+
+	s.metrics.IncStoreOperation("update")
+}
+
+func (s *instrumentedStore) Upsert(ctx context.Context, users []models.User) []store.UpsertResult {
+	results := s.User.Upsert(ctx, users)
+
+	s.metrics.IncStoreOperation("upsert")
+
+	return results
+}
+
+func (s *instrumentedStore) Delete(ctx context.Context, id int) {
+	s.User.Delete(ctx, id)
+
+	s.metrics.IncStoreOperation("delete")
+}
+
+func (s *instrumentedStore) Restore(ctx context.Context, snapshot store.Snapshot) error {
+	err := s.User.Restore(ctx, snapshot)
+	if err == nil {
+		s.metrics.IncStoreOperation("restore")
+	}
+
+	return err
+}
+
+func (s *instrumentedStore) Merge(ctx context.Context, req models.MergeRequest) (*models.User, error) {
+	user, err := s.User.Merge(ctx, req)
+	if err == nil {
+		s.metrics.IncStoreOperation("merge")
+	}
+
+	return user, err
+}
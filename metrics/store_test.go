@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+func Test_InstrumentStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	ctx := context.Background()
+	m := New()
+
+	instrumented, err := InstrumentStore(mockStore, m)
+	require.NoError(t, err)
+
+	usr := &models.User{Fname: "John"}
+
+	mockStore.EXPECT().Create(ctx, usr).Return(1)
+	mockStore.EXPECT().Update(ctx, usr)
+	mockStore.EXPECT().Delete(ctx, 1)
+	mockStore.EXPECT().Merge(ctx, models.MergeRequest{SurvivorID: 1, MergedID: 2}).Return(usr, nil)
+	mockStore.EXPECT().Merge(ctx, models.MergeRequest{SurvivorID: 1, MergedID: 3}).Return(nil, errors.UserNotFound{ID: 3})
+	mockStore.EXPECT().GetByID(ctx, 1).Return(usr, nil)
+	mockStore.EXPECT().Usage().Return(store.Usage{
+		UsersByTenant: map[string]int{"acme": 3},
+		IndexSizes:    map[string]int{"id": 3},
+	})
+
+	assert.Equal(t, 1, instrumented.Create(ctx, usr))
+	instrumented.Update(ctx, usr)
+	instrumented.Delete(ctx, 1)
+	_, err = instrumented.Merge(ctx, models.MergeRequest{SurvivorID: 1, MergedID: 2})
+	assert.NoError(t, err)
+	_, err = instrumented.Merge(ctx, models.MergeRequest{SurvivorID: 1, MergedID: 3})
+	assert.Error(t, err)
+
+	got, err := instrumented.GetByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, usr, got)
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, `user_detail_management_store_operations_total{operation="create"} 1`)
+	assert.Contains(t, body, `user_detail_management_store_operations_total{operation="update"} 1`)
+	assert.Contains(t, body, `user_detail_management_store_operations_total{operation="delete"} 1`)
+	assert.Contains(t, body, `user_detail_management_store_operations_total{operation="merge"} 1`)
+	assert.Contains(t, body, `user_detail_management_store_users{tenant="acme"} 3`)
+	assert.Contains(t, body, `user_detail_management_store_index_entries{index="id"} 3`)
+
+	_, err = InstrumentStore(mockStore, m)
+	assert.Error(t, err, "a registry instruments a single store")
+}
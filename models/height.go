@@ -0,0 +1,169 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// HeightUnit is the unit a height is given or shown in. Heights are stored in centimeters, which
+// is also the unit of HeightUnitUnspecified.
+type HeightUnit int
+
+const (
+	HeightUnitUnspecified HeightUnit = iota
+	HeightUnitCentimeters
+	HeightUnitMeters
+	HeightUnitFeet
+	HeightUnitInches
+)
+
+const (
+	// MinHeight and MaxHeight bound the plausible heights, in centimeters.
+	MinHeight = 30.0
+	MaxHeight = 280.0
+	// MaxHeightPrecision is the largest number of decimals heights can be rounded to. Heights are
+	// stored with at most as many decimals, dropping the noise of unit conversions.
+	MaxHeightPrecision = 6
+)
+
+// centimetersPer holds the number of centimeters in one of every unit.
+var centimetersPer = map[HeightUnit]float64{
+	HeightUnitUnspecified: 1,
+	HeightUnitCentimeters: 1,
+	HeightUnitMeters:      100,
+	HeightUnitFeet:        30.48,
+	HeightUnitInches:      2.54,
+}
+
+var heightUnitNames = map[string]HeightUnit{
+	"cm":          HeightUnitCentimeters,
+	"centimeters": HeightUnitCentimeters,
+	"m":           HeightUnitMeters,
+	"meters":      HeightUnitMeters,
+	"ft":          HeightUnitFeet,
+	"feet":        HeightUnitFeet,
+	"in":          HeightUnitInches,
+	"inches":      HeightUnitInches,
+}
+
+// ParseHeightUnit returns the unit named name, either by its symbol (cm, m, ft, in) or in full,
+// ignoring case.
+func ParseHeightUnit(name string) (HeightUnit, bool) {
+	unit, ok := heightUnitNames[strings.ToLower(strings.TrimSpace(name))]
+
+	return unit, ok
+}
+
+// Valid reports whether u is a known unit.
+func (u HeightUnit) Valid() bool {
+	_, ok := centimetersPer[u]
+
+	return ok
+}
+
+// String returns the symbol of the unit.
+func (u HeightUnit) String() string {
+	switch u {
+	case HeightUnitUnspecified, HeightUnitCentimeters:
+		return "cm"
+	case HeightUnitMeters:
+		return "m"
+	case HeightUnitFeet:
+		return "ft"
+	case HeightUnitInches:
+		return "in"
+	}
+
+	return "unknown"
+}
+
+func (u HeightUnit) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses the unit as ParseHeightUnit does. An empty text leaves the unit
+// unspecified.
+func (u *HeightUnit) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*u = HeightUnitUnspecified
+
+		return nil
+	}
+
+	unit, ok := ParseHeightUnit(string(text))
+	if !ok {
+		return fmt.Errorf("unknown height unit %q", text)
+	}
+
+	*u = unit
+
+	return nil
+}
+
+// Height is a height given in a unit.
+type Height struct {
+	Value float64
+	Unit  HeightUnit
+}
+
+// Centimeters returns the height in centimeters, the unit heights are stored in, rounded to
+// MaxHeightPrecision decimals.
+func (h Height) Centimeters() float64 {
+	return round(h.Value*centimetersPer[h.Unit], MaxHeightPrecision)
+}
+
+// Plausible reports whether the unit is known and the height lies between MinHeight and
+// MaxHeight.
+func (h Height) Plausible() bool {
+	if !h.Unit.Valid() {
+		return false
+	}
+
+	cm := h.Centimeters()
+
+	return cm >= MinHeight && cm <= MaxHeight
+}
+
+// HeightFormat tells how stored heights are shown: converted to Unit and, unless Precision is
+// nil, rounded to Precision decimals. The zero value shows heights as they are stored.
+type HeightFormat struct {
+	Unit      HeightUnit
+	Precision *int
+}
+
+// Convert returns the height of cm centimeters in the unit and precision of the format.
+func (f HeightFormat) Convert(cm float64) float64 {
+	value := cm
+
+	if f.Unit.Valid() {
+		value /= centimetersPer[f.Unit]
+	}
+
+	if f.Precision == nil {
+		return value
+	}
+
+	return round(value, *f.Precision)
+}
+
+func round(value float64, precision int) float64 {
+	scale := math.Pow10(precision)
+
+	return math.Round(value*scale) / scale
+}
+
+type heightFormatKey struct{}
+
+func NewHeightFormatContext(ctx context.Context, format HeightFormat) context.Context {
+	return context.WithValue(ctx, heightFormatKey{}, format)
+}
+
+// HeightFormatFromContext returns the format stored in ctx, or the zero HeightFormat when there is
+// none.
+func HeightFormatFromContext(ctx context.Context) HeightFormat {
+	format, _ := ctx.Value(heightFormatKey{}).(HeightFormat)
+
+	return format
+}
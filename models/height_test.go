@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseHeightUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantUnit HeightUnit
+		wantOK   bool
+	}{
+		{"cm", HeightUnitCentimeters, true},
+		{"Meters", HeightUnitMeters, true},
+		{" FT ", HeightUnitFeet, true},
+		{"inches", HeightUnitInches, true},
+		{"yards", HeightUnitUnspecified, false},
+		{"", HeightUnitUnspecified, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, ok := ParseHeightUnit(tt.name)
+
+			assert.Equal(t, tt.wantUnit, unit)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func Test_HeightCentimeters(t *testing.T) {
+	tests := []struct {
+		name          string
+		height        Height
+		want          float64
+		wantPlausible bool
+	}{
+		{"Unspecified unit", Height{Value: 180}, 180, true},
+		{"Centimeters", Height{Value: 180, Unit: HeightUnitCentimeters}, 180, true},
+		{"Meters", Height{Value: 1.8, Unit: HeightUnitMeters}, 180, true},
+		{"Feet", Height{Value: 5.9, Unit: HeightUnitFeet}, 179.832, true},
+		{"Inches", Height{Value: 70, Unit: HeightUnitInches}, 177.8, true},
+		{"Too short", Height{Value: 1.8}, 1.8, false},
+		{"Too tall", Height{Value: 18, Unit: HeightUnitFeet}, 548.64, false},
+		{"Unknown unit", Height{Value: 180, Unit: HeightUnit(9)}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.height.Centimeters())
+			assert.Equal(t, tt.wantPlausible, tt.height.Plausible())
+		})
+	}
+}
+
+func Test_HeightFormatConvert(t *testing.T) {
+	zero, two := 0, 2
+
+	tests := []struct {
+		name   string
+		format HeightFormat
+		cm     float64
+		want   float64
+	}{
+		{"Zero value", HeightFormat{}, 179.832, 179.832},
+		{"Centimeters rounded", HeightFormat{Unit: HeightUnitCentimeters, Precision: &zero}, 179.832, 180},
+		{"Meters", HeightFormat{Unit: HeightUnitMeters, Precision: &two}, 179.832, 1.8},
+		{"Feet", HeightFormat{Unit: HeightUnitFeet, Precision: &two}, 179.832, 5.9},
+		{"Inches", HeightFormat{Unit: HeightUnitInches, Precision: &zero}, 177.8, 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.Convert(tt.cm))
+		})
+	}
+}
+
+func Test_HeightFormatContext(t *testing.T) {
+	assert.Equal(t, HeightFormat{}, HeightFormatFromContext(context.Background()))
+
+	format := HeightFormat{Unit: HeightUnitFeet}
+	assert.Equal(t, format, HeightFormatFromContext(NewHeightFormatContext(context.Background(), format)))
+}
+
+func Test_UserRequestHeightUnitJSON(t *testing.T) {
+	var req UserRequest
+
+	require.NoError(t, json.Unmarshal([]byte(`{"height":5.9,"height_unit":"ft"}`), &req))
+	assert.Equal(t, HeightUnitFeet, req.HeightUnit)
+	assert.Equal(t, 179.832, req.HeightCentimeters())
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"height_unit":"cubits"}`), &req), `unknown height unit "cubits"`)
+}
@@ -17,6 +17,8 @@ type UserRequest struct {
 	Phone   *string  `json:"phone"`
 	Height  *float64 `json:"height"`
 	Married *bool    `json:"married"`
+	// HeightUnit is the unit of Height.
+	HeightUnit HeightUnit `json:"height_unit"`
 }
 
 func (u UserRequest) ValidateMissingParam() error {
@@ -56,7 +58,10 @@ func (u UserRequest) ValidateInvalidParam() error {
 		invalid = append(invalid, "phone")
 	}
 
-	if *u.Height <= 0.0 {
+	switch {
+	case !u.HeightUnit.Valid():
+		invalid = append(invalid, "height_unit")
+	case !(Height{Value: *u.Height, Unit: u.HeightUnit}).Plausible():
 		invalid = append(invalid, "height")
 	}
 
@@ -67,6 +72,11 @@ func (u UserRequest) ValidateInvalidParam() error {
 	return nil
 }
 
+// HeightCentimeters returns Height in centimeters, the unit heights are stored in.
+func (u UserRequest) HeightCentimeters() float64 {
+	return Height{Value: *u.Height, Unit: u.HeightUnit}.Centimeters()
+}
+
 type UserUpdateRequest struct {
 	ID      *int     `json:"id"`
 	Fname   *string  `json:"fname"`
@@ -74,6 +84,8 @@ type UserUpdateRequest struct {
 	Phone   *string  `json:"phone"`
 	Height  *float64 `json:"height"`
 	Married *bool    `json:"married"`
+	// HeightUnit is the unit of Height.
+	HeightUnit HeightUnit `json:"height_unit"`
 }
 
 func (u UserUpdateRequest) ValidateMissingParam() error {
@@ -121,7 +133,10 @@ func (u UserUpdateRequest) ValidateInvalidParam() error {
 		invalid = append(invalid, "phone")
 	}
 
-	if *u.Height <= 0.0 {
+	switch {
+	case !u.HeightUnit.Valid():
+		invalid = append(invalid, "height_unit")
+	case !(Height{Value: *u.Height, Unit: u.HeightUnit}).Plausible():
 		invalid = append(invalid, "height")
 	}
 
@@ -131,3 +146,80 @@ func (u UserUpdateRequest) ValidateInvalidParam() error {
 
 	return nil
 }
+
+// HeightCentimeters returns Height in centimeters, the unit heights are stored in.
+func (u UserUpdateRequest) HeightCentimeters() float64 {
+	return Height{Value: *u.Height, Unit: u.HeightUnit}.Centimeters()
+}
+
+// MergeSource tells which of two merged users a field of the result is taken from.
+type MergeSource int
+
+const (
+	MergeFromSurvivor MergeSource = iota
+	MergeFromMerged
+)
+
+// MergeRequest merges the user of ID MergedID into the user of ID SurvivorID, which keeps its ID
+// and takes every field from the user named by the source of the field.
+type MergeRequest struct {
+	SurvivorID int         `json:"survivor_id"`
+	MergedID   int         `json:"merged_id"`
+	Fname      MergeSource `json:"fname"`
+	City       MergeSource `json:"city"`
+	Phone      MergeSource `json:"phone"`
+	Height     MergeSource `json:"height"`
+	Married    MergeSource `json:"married"`
+}
+
+func (m MergeRequest) ValidateInvalidParam() error {
+	var invalid []string
+
+	if m.SurvivorID <= 0 {
+		invalid = append(invalid, "survivor_id")
+	}
+
+	if m.MergedID <= 0 || m.MergedID == m.SurvivorID {
+		invalid = append(invalid, "merged_id")
+	}
+
+	for _, field := range []struct {
+		name   string
+		source MergeSource
+	}{{"fname", m.Fname}, {"city", m.City}, {"phone", m.Phone}, {"height", m.Height}, {"married", m.Married}} {
+		if field.source != MergeFromSurvivor && field.source != MergeFromMerged {
+			invalid = append(invalid, field.name)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return errors.InvalidParams{Params: invalid}
+	}
+
+	return nil
+}
+
+// Merge returns survivor with the fields m takes from merged.
+func (m MergeRequest) Merge(survivor, merged User) User {
+	if m.Fname == MergeFromMerged {
+		survivor.Fname = merged.Fname
+	}
+
+	if m.City == MergeFromMerged {
+		survivor.City = merged.City
+	}
+
+	if m.Phone == MergeFromMerged {
+		survivor.Phone = merged.Phone
+	}
+
+	if m.Height == MergeFromMerged {
+		survivor.Height = merged.Height
+	}
+
+	if m.Married == MergeFromMerged {
+		survivor.Married = merged.Married
+	}
+
+	return survivor
+}
@@ -65,7 +65,7 @@ func Test_UserRequestValidateInvalidParam(t *testing.T) {
 				Fname:   utils.StrPtr("John"),
 				City:    utils.StrPtr("New York"),
 				Phone:   utils.StrPtr("1234567890"),
-				Height:  utils.Float64Ptr(5.9),
+				Height:  utils.Float64Ptr(180.0),
 				Married: utils.BoolPtr(true),
 			},
 			wantErr: nil,
@@ -76,7 +76,7 @@ func Test_UserRequestValidateInvalidParam(t *testing.T) {
 				Fname:   utils.StrPtr("John"),
 				City:    utils.StrPtr("New York"),
 				Phone:   utils.StrPtr("123"),
-				Height:  utils.Float64Ptr(5.9),
+				Height:  utils.Float64Ptr(180.0),
 				Married: utils.BoolPtr(true),
 			},
 			wantErr: errors.InvalidParams{Params: []string{"phone"}},
@@ -92,6 +92,41 @@ func Test_UserRequestValidateInvalidParam(t *testing.T) {
 			},
 			wantErr: errors.InvalidParams{Params: []string{"height"}},
 		},
+		{
+			name: "Height in feet",
+			request: UserRequest{
+				Fname:      utils.StrPtr("John"),
+				City:       utils.StrPtr("New York"),
+				Phone:      utils.StrPtr("1234567890"),
+				Height:     utils.Float64Ptr(5.9),
+				Married:    utils.BoolPtr(true),
+				HeightUnit: HeightUnitFeet,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Implausible Height",
+			request: UserRequest{
+				Fname:   utils.StrPtr("John"),
+				City:    utils.StrPtr("New York"),
+				Phone:   utils.StrPtr("1234567890"),
+				Height:  utils.Float64Ptr(5.9),
+				Married: utils.BoolPtr(true),
+			},
+			wantErr: errors.InvalidParams{Params: []string{"height"}},
+		},
+		{
+			name: "Invalid Height Unit",
+			request: UserRequest{
+				Fname:      utils.StrPtr("John"),
+				City:       utils.StrPtr("New York"),
+				Phone:      utils.StrPtr("1234567890"),
+				Height:     utils.Float64Ptr(180.0),
+				Married:    utils.BoolPtr(true),
+				HeightUnit: HeightUnit(9),
+			},
+			wantErr: errors.InvalidParams{Params: []string{"height_unit"}},
+		},
 		{
 			name: "Invalid Phone and Height",
 			request: UserRequest{
@@ -173,7 +208,7 @@ func Test_UserUpdateRequestValidateInvalidParam(t *testing.T) {
 				Fname:   utils.StrPtr("John"),
 				City:    utils.StrPtr("New York"),
 				Phone:   utils.StrPtr("1234567890"),
-				Height:  utils.Float64Ptr(5.9),
+				Height:  utils.Float64Ptr(180.0),
 				Married: utils.BoolPtr(true),
 			},
 			wantErr: nil,
@@ -185,7 +220,7 @@ func Test_UserUpdateRequestValidateInvalidParam(t *testing.T) {
 				Fname:   utils.StrPtr("John"),
 				City:    utils.StrPtr("New York"),
 				Phone:   utils.StrPtr("1234567890"),
-				Height:  utils.Float64Ptr(5.9),
+				Height:  utils.Float64Ptr(180.0),
 				Married: utils.BoolPtr(true),
 			},
 			wantErr: errors.InvalidParams{Params: []string{"id"}},
@@ -197,7 +232,7 @@ func Test_UserUpdateRequestValidateInvalidParam(t *testing.T) {
 				Fname:   utils.StrPtr("John"),
 				City:    utils.StrPtr("New York"),
 				Phone:   utils.StrPtr("123"),
-				Height:  utils.Float64Ptr(5.9),
+				Height:  utils.Float64Ptr(180.0),
 				Married: utils.BoolPtr(true),
 			},
 			wantErr: errors.InvalidParams{Params: []string{"phone"}},
@@ -214,6 +249,19 @@ func Test_UserUpdateRequestValidateInvalidParam(t *testing.T) {
 			},
 			wantErr: errors.InvalidParams{Params: []string{"height"}},
 		},
+		{
+			name: "Implausible Height in meters",
+			request: UserUpdateRequest{
+				ID:         utils.IntPtr(1),
+				Fname:      utils.StrPtr("John"),
+				City:       utils.StrPtr("New York"),
+				Phone:      utils.StrPtr("1234567890"),
+				Height:     utils.Float64Ptr(180.0),
+				Married:    utils.BoolPtr(true),
+				HeightUnit: HeightUnitMeters,
+			},
+			wantErr: errors.InvalidParams{Params: []string{"height"}},
+		},
 		{
 			name: "Invalid Phone and Height",
 			request: UserUpdateRequest{
@@ -235,3 +283,33 @@ func Test_UserUpdateRequestValidateInvalidParam(t *testing.T) {
 		})
 	}
 }
+
+func Test_MergeRequestValidateInvalidParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		request MergeRequest
+		wantErr error
+	}{
+		{"Valid", MergeRequest{SurvivorID: 1, MergedID: 2, City: MergeFromMerged}, nil},
+		{"Invalid IDs", MergeRequest{SurvivorID: 0, MergedID: -1}, errors.InvalidParams{Params: []string{"survivor_id", "merged_id"}}},
+		{"Same user", MergeRequest{SurvivorID: 1, MergedID: 1}, errors.InvalidParams{Params: []string{"merged_id"}}},
+		{"Invalid sources", MergeRequest{SurvivorID: 1, MergedID: 2, Phone: 2, Married: -1}, errors.InvalidParams{Params: []string{"phone", "married"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.request.ValidateInvalidParam())
+		})
+	}
+}
+
+func Test_MergeRequestMerge(t *testing.T) {
+	survivor := User{ID: 1, Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9}
+	merged := User{ID: 2, Fname: "Jon", City: "Denver", Phone: "5550100001", Height: 5.8, Married: true}
+
+	assert.Equal(t, survivor, MergeRequest{SurvivorID: 1, MergedID: 2}.Merge(survivor, merged))
+	assert.Equal(t,
+		User{ID: 1, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9, Married: true},
+		MergeRequest{SurvivorID: 1, MergedID: 2, City: MergeFromMerged, Married: MergeFromMerged}.Merge(survivor, merged),
+	)
+}
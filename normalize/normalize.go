@@ -0,0 +1,76 @@
+// Package normalize puts names and cities in a canonical form, and derives the keys they and
+// phone numbers are compared by.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Text returns s in Unicode normalization form C, trimmed, with every run of whitespace replaced
+// by a single space. It is applied to names and cities before they are stored, so that text
+// typed on different systems is stored the same way.
+func Text(s string) string {
+	return collapseSpaces(norm.NFC.String(s))
+}
+
+// Key returns the key s is compared by: Text(s) without accents and case folded, so that "José",
+// its decomposed form "José", "JOSE" and "jose" share the key "jose".
+func Key(s string) string {
+	if isASCII(s) {
+		return collapseSpaces(strings.ToLower(s))
+	}
+
+	// Marks are stripped once decomposed, so that "é" becomes "e" rather than disappearing.
+	stripMarks := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	key, _, err := transform.String(stripMarks, s)
+	if err != nil {
+		key = s
+	}
+
+	return collapseSpaces(cases.Fold().String(key))
+}
+
+// phoneDigits is the number of digits of a phone number without its country code.
+const phoneDigits = 10
+
+// Phone returns the key phone numbers are compared by: their last ten digits, so that
+// "+1 (555) 010-0000", "555-010-0000" and "5550100000" share the key "5550100000". Numbers of
+// fewer digits keep all of them.
+func Phone(s string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+
+		return -1
+	}, s)
+
+	return digits[max(len(digits)-phoneDigits, 0):]
+}
+
+// Equal reports whether a and b have the same key.
+func Equal(a, b string) bool {
+	return Key(a) == Key(b)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package normalize
+
+import (
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ASCII", "John", "John"},
+		{"Composed", "Jos\u00e9", "Jos\u00e9"},
+		{"Decomposed", "Jose\u0301", "Jos\u00e9"},
+		{"Surrounding spaces", "  New York\t", "New York"},
+		{"Inner spaces", "New \t\n York", "New York"},
+		{"Unicode spaces", "São\u00a0Paulo", "São Paulo"},
+		{"Case kept", "MÜNCHEN", "MÜNCHEN"},
+		{"Empty", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.input); got != tt.expected {
+				t.Errorf("Text(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ASCII", "John", "john"},
+		{"ASCII spaces", " New   York ", "new york"},
+		{"Composed", "Jos\u00e9", "jose"},
+		{"Decomposed", "Jose\u0301", "jose"},
+		{"Upper case accents", "ÉLODIE", "elodie"},
+		{"Several marks", "Nguyễn", "nguyen"},
+		{"Case folding", "Straße", "strasse"},
+		{"Unicode spaces", "São\u00a0Paulo", "sao paulo"},
+		{"Non Latin", "Москва", "москва"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.input); got != tt.expected {
+				t.Errorf("Key(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"José", "Jose\u0301", true},
+		{"José", "jose", true},
+		{"Zürich", "ZURICH", true},
+		{"John", "Jon", false},
+		{"New York", "NewYork", false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestPhone(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Digits", "5550100000", "5550100000"},
+		{"Separators", "555-010 0000", "5550100000"},
+		{"Country code", "+1 (555) 010-0000", "5550100000"},
+		{"International prefix", "0044 5550100000", "5550100000"},
+		{"Short", "010-0000", "0100000"},
+		{"No digits", "n/a", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Phone(tt.input); got != tt.expected {
+				t.Errorf("Phone(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
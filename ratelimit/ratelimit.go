@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// idleTTL is how long the bucket of a client that stopped calling is kept around.
+	idleTTL = 10 * time.Minute
+	// sweepInterval bounds how often idle buckets are looked for.
+	sweepInterval = time.Minute
+)
+
+// Limit is a token bucket refilled at Rate tokens per second holding at most Burst tokens. A zero
+// Rate disables the limit.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+type Config struct {
+	// Default applies to every method without an entry in Methods.
+	Default Limit `json:"default"`
+	// Methods holds per method limits keyed by full method name, e.g. "/user.UserService/Get".
+	Methods map[string]Limit `json:"methods"`
+	// MaxInFlight caps the number of calls served concurrently across all clients. Zero means
+	// unlimited.
+	MaxInFlight int `json:"max_in_flight"`
+}
+
+func LoadConfig(file string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return cfg, fmt.Errorf("ratelimit: %w", err)
+	}
+
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("ratelimit: parsing %s: %w", file, err)
+	}
+
+	for method, limit := range cfg.Methods {
+		if limit.Rate < 0 || limit.Burst < 0 {
+			return cfg, fmt.Errorf("ratelimit: negative limit for %s", method)
+		}
+	}
+
+	if cfg.Default.Rate < 0 || cfg.Default.Burst < 0 || cfg.MaxInFlight < 0 {
+		return cfg, fmt.Errorf("ratelimit: limits must not be negative")
+	}
+
+	return cfg, nil
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	inFlight chan struct{}
+}
+
+func New(cfg Config) *Limiter {
+	l := &Limiter{
+		cfg:     cfg,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+
+	if cfg.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+
+	return l
+}
+
+func (l *Limiter) limit(method string) Limit {
+	if limit, ok := l.cfg.Methods[method]; ok {
+		return limit
+	}
+
+	return l.cfg.Default
+}
+
+// Allow takes a token from the bucket of client for method. When the bucket is empty it returns
+// false along with how long the client should wait before retrying.
+func (l *Limiter) Allow(client, method string) (bool, time.Duration) {
+	limit := l.limit(method)
+	if limit.Rate == 0 {
+		return true, 0
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	key := client + "|" + method
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.buckets[key] = b
+	}
+
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		// A zero burst never admits a call; ask the client to wait a full refill period.
+		return false, time.Duration(float64(time.Second) / limit.Rate)
+	}
+
+	delay := r.DelayFrom(now)
+	if delay > 0 {
+		r.CancelAt(now)
+
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// sweep drops the buckets of clients idle for longer than idleTTL. It must be called with l.mu
+// held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Acquire claims one of the MaxInFlight slots without waiting. The returned release func must be
+// called once the call has completed; ok is false when every slot is taken.
+func (l *Limiter) Acquire() (release func(), ok bool) {
+	if l.inFlight == nil {
+		return func() {}, true
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, true
+	default:
+		return nil, false
+	}
+}
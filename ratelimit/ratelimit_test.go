@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(cfg Config) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	l := New(cfg)
+	l.now = clock.Now
+
+	return l, clock
+}
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	valid := write("valid.json", `{
+		"default": {"rate": 10, "burst": 20},
+		"methods": {"/user.UserService/Get": {"rate": 1, "burst": 2}},
+		"max_in_flight": 50
+	}`)
+
+	cfg, err := LoadConfig(valid)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Default:     Limit{Rate: 10, Burst: 20},
+		Methods:     map[string]Limit{"/user.UserService/Get": {Rate: 1, Burst: 2}},
+		MaxInFlight: 50,
+	}, cfg)
+
+	_, err = LoadConfig(write("negative.json", `{"methods": {"/user.UserService/Get": {"rate": -1}}}`))
+	assert.Error(t, err)
+
+	_, err = LoadConfig(write("invalid.json", `[]`))
+	assert.Error(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func Test_Allow(t *testing.T) {
+	l, clock := newTestLimiter(Config{
+		Default: Limit{Rate: 10, Burst: 10},
+		Methods: map[string]Limit{
+			"/user.UserService/Get":    {Rate: 1, Burst: 2},
+			"/user.UserService/Search": {Rate: 0},
+		},
+	})
+
+	t.Run("Burst then throttled with retry after", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			allowed, _ := l.Allow("alice", "/user.UserService/Get")
+			assert.True(t, allowed)
+		}
+
+		allowed, retryAfter := l.Allow("alice", "/user.UserService/Get")
+		assert.False(t, allowed)
+		assert.Equal(t, time.Second, retryAfter)
+	})
+
+	t.Run("Rejected calls do not consume tokens", func(t *testing.T) {
+		clock.Advance(time.Second)
+
+		allowed, _ := l.Allow("alice", "/user.UserService/Get")
+		assert.True(t, allowed)
+	})
+
+	t.Run("Clients have separate buckets", func(t *testing.T) {
+		allowed, _ := l.Allow("bob", "/user.UserService/Get")
+		assert.True(t, allowed)
+	})
+
+	t.Run("Methods have separate buckets", func(t *testing.T) {
+		allowed, _ := l.Allow("alice", "/user.UserService/GetByID")
+		assert.True(t, allowed)
+	})
+
+	t.Run("Zero rate is unlimited", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			allowed, _ := l.Allow("alice", "/user.UserService/Search")
+			assert.True(t, allowed)
+		}
+	})
+}
+
+func Test_AllowZeroBurst(t *testing.T) {
+	l, _ := newTestLimiter(Config{Default: Limit{Rate: 2, Burst: 0}})
+
+	allowed, retryAfter := l.Allow("alice", "/user.UserService/Get")
+
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+}
+
+func Test_IdleBucketsAreEvicted(t *testing.T) {
+	l, clock := newTestLimiter(Config{Default: Limit{Rate: 1, Burst: 1}})
+
+	l.Allow("alice", "/user.UserService/Get")
+	assert.Len(t, l.buckets, 1)
+
+	clock.Advance(idleTTL + sweepInterval)
+	l.Allow("bob", "/user.UserService/Get")
+
+	assert.Len(t, l.buckets, 1)
+	assert.Contains(t, l.buckets, "bob|/user.UserService/Get")
+}
+
+func Test_Acquire(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		l := New(Config{})
+
+		for i := 0; i < 10; i++ {
+			_, ok := l.Acquire()
+			assert.True(t, ok)
+		}
+	})
+
+	t.Run("Limited", func(t *testing.T) {
+		l := New(Config{MaxInFlight: 2})
+
+		release1, ok := l.Acquire()
+		assert.True(t, ok)
+
+		_, ok = l.Acquire()
+		assert.True(t, ok)
+
+		_, ok = l.Acquire()
+		assert.False(t, ok)
+
+		release1()
+
+		_, ok = l.Acquire()
+		assert.True(t, ok)
+	})
+}
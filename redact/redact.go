@@ -0,0 +1,167 @@
+package redact
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Masker hides the sensitive part of a value.
+type Masker func(value string) string
+
+// Full replaces the whole value.
+func Full(string) string {
+	return "[REDACTED]"
+}
+
+// Last4 keeps the last four characters of the value, e.g. "******7890". Values too short to keep
+// anything meaningful hidden are masked entirely.
+func Last4(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+
+	return strings.Repeat("*", len(value)-4) + value[len(value)-4:]
+}
+
+// phoneNumber matches the runs of digits in free text that may be phone numbers.
+var phoneNumber = regexp.MustCompile(`\+?\d[\d -]{5,}\d`)
+
+// Registry classifies fields holding personal data by name, and masks them wherever they
+// appear: proto messages, models and free text.
+type Registry struct {
+	fields map[string]Masker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{fields: make(map[string]Masker)}
+}
+
+// Classify marks field as personal data masked with masker. Field names are the proto field
+// names, which are also the JSON names of the models, and are compared case-insensitively.
+func (r *Registry) Classify(field string, masker Masker) {
+	r.fields[strings.ToLower(field)] = masker
+}
+
+// Value masks value if field is classified, and returns it as is otherwise.
+func (r *Registry) Value(field, value string) string {
+	if masker, ok := r.fields[strings.ToLower(field)]; ok {
+		return masker(value)
+	}
+
+	return value
+}
+
+// Text masks anything looking like a phone number in free text such as error messages.
+func (r *Registry) Text(s string) string {
+	return phoneNumber.ReplaceAllStringFunc(s, Last4)
+}
+
+// Payload returns a JSON rendering of v, a proto message or any JSON encodable value, with every
+// classified field masked. It is meant to be logged.
+func (r *Registry) Payload(v any) json.RawMessage {
+	if msg, ok := v.(proto.Message); ok {
+		msg = proto.Clone(msg)
+		r.maskMessage(msg.ProtoReflect())
+
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return json.RawMessage(`"[unloggable payload]"`)
+		}
+
+		return data
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage(`"[unloggable payload]"`)
+	}
+
+	var decoded any
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		return json.RawMessage(`"[unloggable payload]"`)
+	}
+
+	data, _ = json.Marshal(r.maskJSON("", decoded))
+
+	return data
+}
+
+func (r *Registry) maskMessage(msg protoreflect.Message) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			list := v.List()
+
+			for i := 0; i < list.Len(); i++ {
+				r.maskMessage(list.Get(i).Message())
+			}
+
+		case fd.IsList() && fd.Kind() == protoreflect.StringKind:
+			list := v.List()
+
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, protoreflect.ValueOfString(r.Value(string(fd.Name()), list.Get(i).String())))
+			}
+
+		case fd.IsMap():
+			// Map values are not classified.
+
+		case fd.Kind() == protoreflect.MessageKind:
+			r.maskMessage(v.Message())
+
+		case fd.Kind() == protoreflect.StringKind:
+			msg.Set(fd, protoreflect.ValueOfString(r.Value(string(fd.Name()), v.String())))
+		}
+
+		return true
+	})
+}
+
+func (r *Registry) maskJSON(field string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = r.maskJSON(key, value)
+		}
+
+	case []any:
+		for i, value := range v {
+			v[i] = r.maskJSON(field, value)
+		}
+
+	case string:
+		return r.Value(field, v)
+	}
+
+	return v
+}
+
+// Default classifies the personal data held by users: phone numbers keep their last four digits,
+// while first names, cities and the free text searched by Suggest, which may hold either, are
+// hidden entirely.
+var Default = func() *Registry {
+	r := NewRegistry()
+	r.Classify("phone", Last4)
+	r.Classify("fname", Full)
+	r.Classify("city", Full)
+	r.Classify("text", Full)
+
+	return r
+}()
+
+// Text masks free text with the Default registry.
+func Text(s string) string {
+	return Default.Text(s)
+}
+
+// Payload renders v with the Default registry.
+func Payload(v any) json.RawMessage {
+	return Default.Payload(v)
+}
@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/ssshekhu53/user-detail-management/grpc"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/utils"
+)
+
+func Test_Last4(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"1234567890", "******7890"},
+		{"12345", "*2345"},
+		{"1234", "****"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, Last4(tt.value))
+		})
+	}
+}
+
+func Test_Value(t *testing.T) {
+	assert.Equal(t, "******7890", Default.Value("phone", "1234567890"))
+	assert.Equal(t, "[REDACTED]", Default.Value("Fname", "John"))
+	assert.Equal(t, "[REDACTED]", Default.Value("city", "Boston"))
+	assert.Equal(t, "[REDACTED]", Default.Value("text", "jon"))
+	assert.Equal(t, "5.9", Default.Value("height", "5.9"))
+}
+
+func Test_Text(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"Plain phone number", "duplicate phone 1234567890", "duplicate phone ******7890"},
+		{"Formatted phone number", "call +1 555-123-4567 now", "call ***********4567 now"},
+		{"Short numbers are kept", "user with ID 42 not found", "user with ID 42 not found"},
+		{"No digits", "user already exists with given combination", "user already exists with given combination"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Text(tt.text))
+		})
+	}
+}
+
+func Test_Payload(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			"Proto message",
+			&pb.User{Id: 1, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9},
+			`{"id":1,"fname":"[REDACTED]","city":"[REDACTED]","phone":"******7890","height":5.9}`,
+		},
+		{
+			"Nested proto messages",
+			&pb.Users{Users: []*pb.User{{Id: 1, Fname: "John"}, {Id: 2, Phone: "0987654321"}}},
+			`{"users":[{"id":1,"fname":"[REDACTED]"},{"id":2,"phone":"******4321"}]}`,
+		},
+		{
+			"Model",
+			models.User{ID: 1, Fname: "John", City: "Boston", Phone: "1234567890"},
+			`{"city":"[REDACTED]","fname":"[REDACTED]","height":0,"id":1,"married":false,"phone":"******7890"}`,
+		},
+		{
+			"Suggest request",
+			&pb.SuggestRequest{Text: "jon", Prefix: true},
+			`{"text":"[REDACTED]","prefix":true}`,
+		},
+		{
+			"Model with pointers",
+			&models.Filters{Phone: utils.StrPtr("1234567890")},
+			`{"city":null,"fname":null,"height":null,"phone":"******7890"}`,
+		},
+		{"Nil", nil, `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.want, string(Payload(tt.v)))
+		})
+	}
+}
+
+func Test_PayloadDoesNotModifyMessage(t *testing.T) {
+	usr := &pb.User{Fname: "John", Phone: "1234567890"}
+
+	Payload(usr)
+
+	assert.Equal(t, "John", usr.Fname)
+	assert.Equal(t, "1234567890", usr.Phone)
+}
+
+func Test_Classify(t *testing.T) {
+	r := NewRegistry()
+	r.Classify("City", Full)
+
+	assert.JSONEq(t, `{"fname":"John","city":"[REDACTED]"}`, string(r.Payload(&pb.User{Fname: "John", City: "Boston"})))
+}
@@ -1,16 +1,72 @@
 package service
 
-import "github.com/ssshekhu53/user-detail-management/models"
+import (
+	"context"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 100
+
+	DefaultHistogramBuckets = 10
+	MaxHistogramBuckets     = 100
+
+	DefaultDuplicateSimilarity = 0.6
+)
 
 //go:generate mockgen -source=interface.go -destination=mock_interface.go -package=service
 
 type User interface {
-	Create(*models.UserRequest) (*models.User, error)
-	Get() []models.User
-	GetByID(int) (*models.User, error)
-	GetByIDs(ids []int) []models.User
-	Update(*models.UserUpdateRequest) (*models.User, error)
-	Delete(int) error
-
-	Search(filters *models.Filters) []models.User
+	Create(context.Context, *models.UserRequest) (*models.User, error)
+	Get(context.Context) []models.User
+	GetByID(context.Context, int) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []int) []models.User
+	Update(context.Context, *models.UserUpdateRequest) (*models.User, error)
+	Delete(context.Context, int) error
+
+	Search(ctx context.Context, filters *models.Filters) []models.User
+	// Count returns the number of users matching filters without listing them.
+	Count(ctx context.Context, filters *models.Filters) int
+	// Exists reports whether a user matches filters.
+	Exists(ctx context.Context, filters *models.Filters) bool
+
+	GetByPhone(ctx context.Context, phone string) []models.User
+	// Scan pages through the users matching filters in ID order, returning at most limit users with
+	// an ID greater than afterID.
+	Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User
+	// Upsert creates every user of users, or updates the user holding the same phone number, in a
+	// single store operation. It returns the outcome of every user, in order.
+	Upsert(ctx context.Context, users []*models.UserRequest) []store.UpsertResult
+
+	// Backup returns a consistent snapshot of the users of the tenant.
+	Backup(ctx context.Context) store.Snapshot
+	// Restore loads snapshot into the tenant, which must hold no users.
+	Restore(ctx context.Context, snapshot store.Snapshot) error
+
+	// Watch calls send with every change made to the users of the tenant matching filters after
+	// afterRevision, or after the current revision when afterRevision is 0, until ctx is done or
+	// send fails.
+	Watch(ctx context.Context, filters *models.Filters, afterRevision int64, send func(store.Event) error) error
+
+	// Suggest returns the users whose first name or city resembles query.Text, or starts with it
+	// for prefix queries, best matches first. A zero query.Limit returns DefaultSuggestLimit
+	// suggestions at most.
+	Suggest(ctx context.Context, query store.SuggestQuery) ([]store.Suggestion, error)
+
+	// Stats aggregates the users matching query.Filters, in total and by the value of
+	// query.GroupBy. A zero query.HistogramBuckets splits heights in DefaultHistogramBuckets buckets.
+	Stats(ctx context.Context, query store.StatsQuery) (store.Stats, error)
+
+	// FindDuplicates returns the clusters of users likely to be duplicates. Empty query.Rules
+	// apply every rule, and a zero query.MinSimilarity is DefaultDuplicateSimilarity.
+	FindDuplicates(ctx context.Context, query store.DuplicatesQuery) ([]store.DuplicateCluster, error)
+	// Merge merges a user into another one, which keeps its ID, and deletes it. It returns the
+	// merged user.
+	Merge(ctx context.Context, req *models.MergeRequest) (*models.User, error)
+	// Merges returns the merges the user of ID id took part in, as the survivor or as the merged
+	// user, oldest first. They are kept for as long as the users, unlike the history of changes.
+	Merges(ctx context.Context, id int) ([]store.MergeRecord, error)
 }
@@ -10,9 +10,11 @@
 package service
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/ssshekhu53/user-detail-management/models"
+	store "github.com/ssshekhu53/user-detail-management/store"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,103 +41,290 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 	return m.recorder
 }
 
+// Backup mocks base method.
+func (m *MockUser) Backup(ctx context.Context) store.Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", ctx)
+	ret0, _ := ret[0].(store.Snapshot)
+	return ret0
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockUserMockRecorder) Backup(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockUser)(nil).Backup), ctx)
+}
+
+// Count mocks base method.
+func (m *MockUser) Count(ctx context.Context, filters *models.Filters) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filters)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserMockRecorder) Count(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUser)(nil).Count), ctx, filters)
+}
+
 // Create mocks base method.
-func (m *MockUser) Create(arg0 *models.UserRequest) (*models.User, error) {
+func (m *MockUser) Create(arg0 context.Context, arg1 *models.UserRequest) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserMockRecorder) Create(arg0 any) *gomock.Call {
+func (mr *MockUserMockRecorder) Create(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method.
-func (m *MockUser) Delete(arg0 int) error {
+func (m *MockUser) Delete(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserMockRecorder) Delete(arg0 any) *gomock.Call {
+func (mr *MockUserMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), arg0, arg1)
+}
+
+// Exists mocks base method.
+func (m *MockUser) Exists(ctx context.Context, filters *models.Filters) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, filters)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockUserMockRecorder) Exists(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockUser)(nil).Exists), ctx, filters)
+}
+
+// FindDuplicates mocks base method.
+func (m *MockUser) FindDuplicates(ctx context.Context, query store.DuplicatesQuery) ([]store.DuplicateCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", ctx, query)
+	ret0, _ := ret[0].([]store.DuplicateCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockUserMockRecorder) FindDuplicates(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockUser)(nil).FindDuplicates), ctx, query)
 }
 
 // Get mocks base method.
-func (m *MockUser) Get() []models.User {
+func (m *MockUser) Get(arg0 context.Context) []models.User {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get")
+	ret := m.ctrl.Call(m, "Get", arg0)
 	ret0, _ := ret[0].([]models.User)
 	return ret0
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockUserMockRecorder) Get() *gomock.Call {
+func (mr *MockUserMockRecorder) Get(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get), arg0)
 }
 
 // GetByID mocks base method.
-func (m *MockUser) GetByID(arg0 int) (*models.User, error) {
+func (m *MockUser) GetByID(arg0 context.Context, arg1 int) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockUserMockRecorder) GetByID(arg0 any) *gomock.Call {
+func (mr *MockUserMockRecorder) GetByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUser)(nil).GetByID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUser)(nil).GetByID), arg0, arg1)
 }
 
 // GetByIDs mocks base method.
-func (m *MockUser) GetByIDs(ids []int) []models.User {
+func (m *MockUser) GetByIDs(ctx context.Context, ids []int) []models.User {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIDs", ids)
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
 	ret0, _ := ret[0].([]models.User)
 	return ret0
 }
 
 // GetByIDs indicates an expected call of GetByIDs.
-func (mr *MockUserMockRecorder) GetByIDs(ids any) *gomock.Call {
+func (mr *MockUserMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUser)(nil).GetByIDs), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUser)(nil).GetByIDs), ctx, ids)
+}
+
+// GetByPhone mocks base method.
+func (m *MockUser) GetByPhone(ctx context.Context, phone string) []models.User {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPhone", ctx, phone)
+	ret0, _ := ret[0].([]models.User)
+	return ret0
+}
+
+// GetByPhone indicates an expected call of GetByPhone.
+func (mr *MockUserMockRecorder) GetByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPhone", reflect.TypeOf((*MockUser)(nil).GetByPhone), ctx, phone)
+}
+
+// Merge mocks base method.
+func (m *MockUser) Merge(ctx context.Context, req *models.MergeRequest) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, req)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserMockRecorder) Merge(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUser)(nil).Merge), ctx, req)
+}
+
+// Merges mocks base method.
+func (m *MockUser) Merges(ctx context.Context, id int) ([]store.MergeRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merges", ctx, id)
+	ret0, _ := ret[0].([]store.MergeRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merges indicates an expected call of Merges.
+func (mr *MockUserMockRecorder) Merges(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merges", reflect.TypeOf((*MockUser)(nil).Merges), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockUser) Restore(ctx context.Context, snapshot store.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserMockRecorder) Restore(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUser)(nil).Restore), ctx, snapshot)
+}
+
+// Scan mocks base method.
+func (m *MockUser) Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, filters, afterID, limit)
+	ret0, _ := ret[0].([]models.User)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockUserMockRecorder) Scan(ctx, filters, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockUser)(nil).Scan), ctx, filters, afterID, limit)
 }
 
 // Search mocks base method.
-func (m *MockUser) Search(filters *models.Filters) []models.User {
+func (m *MockUser) Search(ctx context.Context, filters *models.Filters) []models.User {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", filters)
+	ret := m.ctrl.Call(m, "Search", ctx, filters)
 	ret0, _ := ret[0].([]models.User)
 	return ret0
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockUserMockRecorder) Search(filters any) *gomock.Call {
+func (mr *MockUserMockRecorder) Search(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUser)(nil).Search), ctx, filters)
+}
+
+// Stats mocks base method.
+func (m *MockUser) Stats(ctx context.Context, query store.StatsQuery) (store.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, query)
+	ret0, _ := ret[0].(store.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockUserMockRecorder) Stats(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUser)(nil).Search), filters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockUser)(nil).Stats), ctx, query)
+}
+
+// Suggest mocks base method.
+func (m *MockUser) Suggest(ctx context.Context, query store.SuggestQuery) ([]store.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, query)
+	ret0, _ := ret[0].([]store.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockUserMockRecorder) Suggest(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockUser)(nil).Suggest), ctx, query)
 }
 
 // Update mocks base method.
-func (m *MockUser) Update(arg0 *models.UserUpdateRequest) (*models.User, error) {
+func (m *MockUser) Update(arg0 context.Context, arg1 *models.UserUpdateRequest) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserMockRecorder) Update(arg0 any) *gomock.Call {
+func (mr *MockUserMockRecorder) Update(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), arg0, arg1)
+}
+
+// Upsert mocks base method.
+func (m *MockUser) Upsert(ctx context.Context, users []*models.UserRequest) []store.UpsertResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, users)
+	ret0, _ := ret[0].([]store.UpsertResult)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockUserMockRecorder) Upsert(ctx, users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockUser)(nil).Upsert), ctx, users)
+}
+
+// Watch mocks base method.
+func (m *MockUser) Watch(ctx context.Context, filters *models.Filters, afterRevision int64, send func(store.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, filters, afterRevision, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockUserMockRecorder) Watch(ctx, filters, afterRevision, send any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockUser)(nil).Watch), ctx, filters, afterRevision, send)
 }
@@ -1,12 +1,29 @@
 package user
 
 import (
+	"context"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/events"
+	"github.com/ssshekhu53/user-detail-management/logging"
 	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/redact"
 	"github.com/ssshekhu53/user-detail-management/service"
 	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tracing"
 )
 
+// tracer follows the provider installed with otel.SetTracerProvider and records nothing until one
+// is.
+var tracer = otel.Tracer("github.com/ssshekhu53/user-detail-management/service/user")
+
 type user struct {
 	userStore store.User
 }
@@ -15,80 +32,506 @@ func New(userStore store.User) service.User {
 	return &user{userStore: userStore}
 }
 
-func (u *user) Create(usr *models.UserRequest) (*models.User, error) {
-	existingUsers := u.Search(&models.Filters{Fname: usr.Fname, City: usr.City, Phone: usr.Phone, Height: usr.Height})
+func (u *user) Create(ctx context.Context, usr *models.UserRequest) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Create")
+	defer span.End()
+
+	height := usr.HeightCentimeters()
+
+	existingUsers := u.Search(ctx, &models.Filters{Fname: usr.Fname, City: usr.City, Phone: usr.Phone, Height: &height})
 	if len(existingUsers) != 0 {
-		return nil, errors.UserAlreadyExists{}
+		logging.FromContext(ctx).Debug("duplicate user rejected", "existing_user_id", existingUsers[0].ID)
+
+		err := errors.UserAlreadyExists{}
+		recordError(span, err)
+
+		return nil, err
 	}
 
 	newUser := &models.User{
 		Fname:   *usr.Fname,
 		City:    *usr.City,
 		Phone:   *usr.Phone,
-		Height:  *usr.Height,
+		Height:  height,
 		Married: *usr.Married,
 	}
 
-	id := u.userStore.Create(newUser)
+	id := u.userStore.Create(ctx, newUser)
+
+	newUser, _ = u.userStore.GetByID(ctx, id)
 
-	newUser, _ = u.userStore.GetByID(id)
+	span.SetAttributes(attribute.Int("user.id", id))
+
+	logging.FromContext(ctx).Debug("user created", "user_id", id)
 
 	return newUser, nil
 }
 
-func (u *user) Get() []models.User {
-	users := u.userStore.Get(nil)
+func (u *user) Get(ctx context.Context) []models.User {
+	ctx, span := tracer.Start(ctx, "service.User/Get")
+	defer span.End()
+
+	users := u.userStore.Get(ctx, nil)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
 
 	return users
 }
 
-func (u *user) GetByID(id int) (*models.User, error) {
-	usr, err := u.userStore.GetByID(id)
+func (u *user) GetByID(ctx context.Context, id int) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "service.User/GetByID", trace.WithAttributes(attribute.Int("user.id", id)))
+	defer span.End()
+
+	usr, err := u.userStore.GetByID(ctx, id)
 	if err != nil {
+		recordError(span, err)
+
 		return nil, err
 	}
 
 	return usr, nil
 }
 
-func (u *user) GetByIDs(ids []int) []models.User {
-	users := u.userStore.GetByIDs(ids)
+func (u *user) GetByIDs(ctx context.Context, ids []int) []models.User {
+	ctx, span := tracer.Start(ctx, "service.User/GetByIDs", trace.WithAttributes(attribute.Int("user.id_count", len(ids))))
+	defer span.End()
+
+	users := u.userStore.GetByIDs(ctx, ids)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
 
 	return users
 }
 
-func (u *user) Update(usr *models.UserUpdateRequest) (*models.User, error) {
-	existingUser, err := u.userStore.GetByID(*usr.ID)
+func (u *user) Update(ctx context.Context, usr *models.UserUpdateRequest) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Update", trace.WithAttributes(attribute.Int("user.id", *usr.ID)))
+	defer span.End()
+
+	existingUser, err := u.userStore.GetByID(ctx, *usr.ID)
 	if err != nil {
+		recordError(span, err)
+
 		return nil, err
 	}
 
 	existingUser.Fname = *usr.Fname
 	existingUser.City = *usr.City
 	existingUser.Phone = *usr.Phone
-	existingUser.Height = *usr.Height
+	existingUser.Height = usr.HeightCentimeters()
 	existingUser.Married = *usr.Married
 
-	u.userStore.Update(existingUser)
+	u.userStore.Update(ctx, existingUser)
 
-	updatedUser, _ := u.userStore.GetByID(*usr.ID)
+	updatedUser, _ := u.userStore.GetByID(ctx, *usr.ID)
+
+	logging.FromContext(ctx).Debug("user updated", "user_id", *usr.ID)
 
 	return updatedUser, nil
 }
 
-func (u *user) Delete(id int) error {
-	_, err := u.userStore.GetByID(id)
+func (u *user) Delete(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "service.User/Delete", trace.WithAttributes(attribute.Int("user.id", id)))
+	defer span.End()
+
+	_, err := u.userStore.GetByID(ctx, id)
 	if err != nil {
+		recordError(span, err)
+
 		return err
 	}
 
-	u.userStore.Delete(id)
+	u.userStore.Delete(ctx, id)
+
+	logging.FromContext(ctx).Debug("user deleted", "user_id", id)
 
 	return nil
 }
 
-func (u *user) Search(filters *models.Filters) []models.User {
-	users := u.userStore.Get(filters)
+func (u *user) Search(ctx context.Context, filters *models.Filters) []models.User {
+	ctx, span := tracer.Start(ctx, "service.User/Search", trace.WithAttributes(attribute.StringSlice("user.filter_fields", tracing.FilterFields(filters))))
+	defer span.End()
+
+	users := u.userStore.Get(ctx, filters)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
 
 	return users
 }
+
+func (u *user) Count(ctx context.Context, filters *models.Filters) int {
+	ctx, span := tracer.Start(ctx, "service.User/Count", trace.WithAttributes(attribute.StringSlice("user.filter_fields", tracing.FilterFields(filters))))
+	defer span.End()
+
+	count := u.userStore.Count(ctx, filters)
+
+	span.SetAttributes(attribute.Int("user.result_count", count))
+
+	return count
+}
+
+func (u *user) Exists(ctx context.Context, filters *models.Filters) bool {
+	ctx, span := tracer.Start(ctx, "service.User/Exists", trace.WithAttributes(attribute.StringSlice("user.filter_fields", tracing.FilterFields(filters))))
+	defer span.End()
+
+	exists := u.userStore.Exists(ctx, filters)
+
+	span.SetAttributes(attribute.Bool("user.exists", exists))
+
+	return exists
+}
+
+func (u *user) GetByPhone(ctx context.Context, phone string) []models.User {
+	ctx, span := tracer.Start(ctx, "service.User/GetByPhone")
+	defer span.End()
+
+	users := u.userStore.GetByPhone(ctx, phone)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (u *user) Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User {
+	ctx, span := tracer.Start(ctx, "service.User/Scan", trace.WithAttributes(
+		attribute.StringSlice("user.filter_fields", tracing.FilterFields(filters)),
+		attribute.Int("user.after_id", afterID),
+	))
+	defer span.End()
+
+	users := u.userStore.Scan(ctx, filters, afterID, limit)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (u *user) Upsert(ctx context.Context, users []*models.UserRequest) []store.UpsertResult {
+	ctx, span := tracer.Start(ctx, "service.User/Upsert", trace.WithAttributes(attribute.Int("user.count", len(users))))
+	defer span.End()
+
+	newUsers := make([]models.User, 0, len(users))
+
+	for _, usr := range users {
+		newUsers = append(newUsers, models.User{
+			Fname:   *usr.Fname,
+			City:    *usr.City,
+			Phone:   *usr.Phone,
+			Height:  usr.HeightCentimeters(),
+			Married: *usr.Married,
+		})
+	}
+
+	results := u.userStore.Upsert(ctx, newUsers)
+
+	var created, updated, failed int
+
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+		case result.Created:
+			created++
+		default:
+			updated++
+		}
+	}
+
+	span.SetAttributes(attribute.Int("user.created_count", created), attribute.Int("user.updated_count", updated),
+		attribute.Int("user.failed_count", failed))
+
+	logging.FromContext(ctx).Debug("users upserted", "created", created, "updated", updated, "failed", failed)
+
+	return results
+}
+
+func (u *user) Backup(ctx context.Context) store.Snapshot {
+	ctx, span := tracer.Start(ctx, "service.User/Backup")
+	defer span.End()
+
+	snapshot := u.userStore.Snapshot(ctx)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(snapshot.Users)))
+
+	logging.FromContext(ctx).Info("backup taken", "users", len(snapshot.Users), "last_inserted_id", snapshot.LastInsertedID)
+
+	return snapshot
+}
+
+func (u *user) Restore(ctx context.Context, snapshot store.Snapshot) error {
+	ctx, span := tracer.Start(ctx, "service.User/Restore", trace.WithAttributes(attribute.Int("user.count", len(snapshot.Users))))
+	defer span.End()
+
+	err := u.userStore.Restore(ctx, snapshot)
+	if err != nil {
+		recordError(span, err)
+
+		return err
+	}
+
+	logging.FromContext(ctx).Info("backup restored", "users", len(snapshot.Users), "last_inserted_id", snapshot.LastInsertedID)
+
+	return nil
+}
+
+func (u *user) Watch(ctx context.Context, filters *models.Filters, afterRevision int64, send func(store.Event) error) error {
+	ctx, span := tracer.Start(ctx, "service.User/Watch", trace.WithAttributes(
+		attribute.StringSlice("user.filter_fields", tracing.FilterFields(filters)),
+		attribute.Int64("user.after_revision", afterRevision),
+	))
+	defer span.End()
+
+	if afterRevision < 0 {
+		err := errors.InvalidParams{Params: []string{"after_revision"}}
+		recordError(span, err)
+
+		return err
+	}
+
+	if afterRevision == 0 {
+		afterRevision = store.CurrentRevision
+	}
+
+	sent := 0
+
+	defer func() {
+		span.SetAttributes(attribute.Int("user.event_count", sent))
+
+		logging.FromContext(ctx).Debug("watch ended", "events", sent, "revision", afterRevision)
+	}()
+
+	for {
+		changes, err := u.userStore.Changes(ctx, filters, afterRevision)
+		if err != nil {
+			recordError(span, err)
+
+			return err
+		}
+
+		for _, event := range changes.Events {
+			if err := send(event); err != nil {
+				recordError(span, err)
+
+				return err
+			}
+
+			sent++
+		}
+
+		afterRevision = changes.Revision
+
+		select {
+		case <-changes.Next:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (u *user) Suggest(ctx context.Context, query store.SuggestQuery) ([]store.Suggestion, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Suggest", trace.WithAttributes(
+		attribute.StringSlice("user.suggest_fields", tracing.SuggestFields(query.Fields)),
+		attribute.Bool("user.suggest_prefix", query.Prefix),
+	))
+	defer span.End()
+
+	if strings.TrimSpace(query.Text) == "" {
+		err := errors.MissingParams{Params: []string{"text"}}
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	var invalid []string
+
+	for _, field := range query.Fields {
+		if field != store.SuggestFname && field != store.SuggestCity {
+			invalid = append(invalid, "fields")
+
+			break
+		}
+	}
+
+	if query.Limit < 0 || query.Limit > service.MaxSuggestLimit {
+		invalid = append(invalid, "limit")
+	}
+
+	if len(invalid) > 0 {
+		err := errors.InvalidParams{Params: invalid}
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	if query.Limit == 0 {
+		query.Limit = service.DefaultSuggestLimit
+	}
+
+	suggestions := u.userStore.Suggest(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(suggestions)))
+
+	return suggestions, nil
+}
+
+func (u *user) Stats(ctx context.Context, query store.StatsQuery) (store.Stats, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Stats", trace.WithAttributes(
+		attribute.StringSlice("user.filter_fields", tracing.FilterFields(query.Filters)),
+		attribute.String("user.group_by", string(query.GroupBy)),
+	))
+	defer span.End()
+
+	var invalid []string
+
+	if query.GroupBy != store.GroupByNone && query.GroupBy != store.GroupByCity && query.GroupBy != store.GroupByMarried {
+		invalid = append(invalid, "group_by")
+	}
+
+	if query.HistogramBuckets < 0 || query.HistogramBuckets > service.MaxHistogramBuckets {
+		invalid = append(invalid, "histogram_buckets")
+	}
+
+	if len(invalid) > 0 {
+		err := errors.InvalidParams{Params: invalid}
+		recordError(span, err)
+
+		return store.Stats{}, err
+	}
+
+	if query.HistogramBuckets == 0 {
+		query.HistogramBuckets = service.DefaultHistogramBuckets
+	}
+
+	stats := u.userStore.Stats(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.result_count", stats.Total.Count))
+
+	return stats, nil
+}
+
+// FindDuplicates validates query and fills in its defaults before asking the store for clusters.
+func (u *user) FindDuplicates(ctx context.Context, query store.DuplicatesQuery) ([]store.DuplicateCluster, error) {
+	ctx, span := tracer.Start(ctx, "service.User/FindDuplicates", trace.WithAttributes(
+		attribute.StringSlice("user.duplicate_rules", tracing.DuplicateRules(query.Rules)),
+	))
+	defer span.End()
+
+	var invalid []string
+
+	rules := make([]store.DuplicateRule, 0, len(query.Rules))
+
+	for _, rule := range query.Rules {
+		if rule != store.DuplicatePhone && rule != store.DuplicateNameCity {
+			invalid = append(invalid, "rules")
+
+			break
+		}
+
+		if !slices.Contains(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	if query.MinSimilarity < 0 || query.MinSimilarity > 1 {
+		invalid = append(invalid, "min_similarity")
+	}
+
+	if len(invalid) > 0 {
+		err := errors.InvalidParams{Params: invalid}
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		rules = []store.DuplicateRule{store.DuplicatePhone, store.DuplicateNameCity}
+	}
+
+	query.Rules = rules
+
+	if query.MinSimilarity == 0 {
+		query.MinSimilarity = service.DefaultDuplicateSimilarity
+	}
+
+	clusters := u.userStore.FindDuplicates(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.cluster_count", len(clusters)))
+
+	return clusters, nil
+}
+
+func (u *user) Merge(ctx context.Context, req *models.MergeRequest) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Merge", trace.WithAttributes(
+		attribute.Int("user.id", req.SurvivorID),
+		attribute.Int("user.merged_id", req.MergedID),
+	))
+	defer span.End()
+
+	if err := req.ValidateInvalidParam(); err != nil {
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	// The users are read and merged by the store, under the lock of the change, so that no update
+	// made in between is lost.
+	mergedUser, err := u.userStore.Merge(ctx, *req)
+	if err != nil {
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("users merged", "user_id", req.SurvivorID, "merged_user_id", req.MergedID)
+
+	return mergedUser, nil
+}
+
+func (u *user) Merges(ctx context.Context, id int) ([]store.MergeRecord, error) {
+	ctx, span := tracer.Start(ctx, "service.User/Merges", trace.WithAttributes(attribute.Int("user.id", id)))
+	defer span.End()
+
+	if id <= 0 {
+		err := errors.InvalidParams{Params: []string{"user_id"}}
+		recordError(span, err)
+
+		return nil, err
+	}
+
+	merges := u.userStore.Merges(ctx, id)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(merges)))
+
+	return merges, nil
+}
+
+// Publisher returns an observer of the user store publishing an event of every change to
+// publisher. The store calls it while making the change, under its lock, so the events reach
+// publisher in revision order, and publisher must not wait for its sink: see events.Queue. The
+// change is made already, so failing to publish it is logged and does not fail the call.
+func Publisher(publisher events.Publisher) store.Observer {
+	return func(ctx context.Context, tenant string, change store.Event) {
+		publish(ctx, publisher, events.FromChange(tenant, change))
+	}
+}
+
+// publish publishes event, logging failures, which do not fail the change.
+func publish(ctx context.Context, publisher events.Publisher, event events.Event) {
+	err := publisher.Publish(ctx, event)
+	if err != nil {
+		msg := redact.Text(err.Error())
+
+		trace.SpanFromContext(ctx).AddEvent("event not published", trace.WithAttributes(
+			attribute.String("event.type", event.Type),
+			attribute.String("exception.message", msg),
+		))
+
+		logging.FromContext(ctx).Warn("event not published", "event_type", event.Type, "user_id", event.User.ID, "error", msg)
+	}
+}
+
+func recordError(span trace.Span, err error) {
+	msg := redact.Text(err.Error())
+
+	span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", msg)))
+	span.SetStatus(codes.Error, msg)
+}
@@ -1,14 +1,30 @@
 package user
 
 import (
+	"bytes"
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/mock/gomock"
 
 	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/events"
+	"github.com/ssshekhu53/user-detail-management/logging"
 	"github.com/ssshekhu53/user-detail-management/models"
 	"github.com/ssshekhu53/user-detail-management/store"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+	"github.com/ssshekhu53/user-detail-management/tenant"
 	"github.com/ssshekhu53/user-detail-management/utils"
 )
 
@@ -18,6 +34,7 @@ func Test_Create(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	sampleUserReq := &models.UserRequest{
 		Fname:   utils.StrPtr("John"),
@@ -52,9 +69,9 @@ func Test_Create(t *testing.T) {
 		{
 			"Successful creation", sampleUserReq,
 			func() {
-				mockStore.EXPECT().Get(sampleFilter).Return(nil)
-				mockStore.EXPECT().Create(sampleUser).Return(1)
-				mockStore.EXPECT().GetByID(1).Return(&models.User{
+				mockStore.EXPECT().Get(gomock.Any(), sampleFilter).Return(nil)
+				mockStore.EXPECT().Create(gomock.Any(), sampleUser).Return(1)
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID:      1,
 					Fname:   "John",
 					City:    "New York",
@@ -75,7 +92,7 @@ func Test_Create(t *testing.T) {
 		{
 			"User already exists", sampleUserReq,
 			func() {
-				mockStore.EXPECT().Get(sampleFilter).Return([]models.User{
+				mockStore.EXPECT().Get(gomock.Any(), sampleFilter).Return([]models.User{
 					{
 						ID:      1,
 						Fname:   "John",
@@ -94,7 +111,7 @@ func Test_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			user, err := service.Create(tt.userRequest)
+			user, err := service.Create(ctx, tt.userRequest)
 
 			assert.Equal(t, tt.expectedUsr, user)
 			assert.Equal(t, tt.expectedErr, err)
@@ -108,6 +125,7 @@ func Test_Get(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	tests := []struct {
 		name          string
@@ -117,7 +135,7 @@ func Test_Get(t *testing.T) {
 		{
 			"Get all users",
 			func() {
-				mockStore.EXPECT().Get(nil).Return([]models.User{
+				mockStore.EXPECT().Get(gomock.Any(), nil).Return([]models.User{
 					{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180, Married: false},
 					{ID: 2, Fname: "Jane", City: "Los Angeles", Phone: "0987654321", Height: 160, Married: true},
 				})
@@ -130,7 +148,7 @@ func Test_Get(t *testing.T) {
 		{
 			"No users found",
 			func() {
-				mockStore.EXPECT().Get(nil).Return([]models.User{})
+				mockStore.EXPECT().Get(gomock.Any(), nil).Return([]models.User{})
 			},
 			[]models.User{},
 		},
@@ -140,7 +158,7 @@ func Test_Get(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			users := service.Get()
+			users := service.Get(ctx)
 			assert.Equal(t, tt.expectedUsers, users)
 		})
 	}
@@ -152,6 +170,7 @@ func Test_GetByID(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	tests := []struct {
 		name        string
@@ -163,7 +182,7 @@ func Test_GetByID(t *testing.T) {
 		{
 			"User found", 1,
 			func() {
-				mockStore.EXPECT().GetByID(1).Return(&models.User{
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID:      1,
 					Fname:   "John",
 					City:    "New York",
@@ -184,7 +203,7 @@ func Test_GetByID(t *testing.T) {
 		{
 			"User not found", 2,
 			func() {
-				mockStore.EXPECT().GetByID(2).Return(nil, errors.UserNotFound{ID: 2})
+				mockStore.EXPECT().GetByID(gomock.Any(), 2).Return(nil, errors.UserNotFound{ID: 2})
 			},
 			nil, errors.UserNotFound{ID: 2},
 		},
@@ -194,7 +213,7 @@ func Test_GetByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			user, err := service.GetByID(tt.userID)
+			user, err := service.GetByID(ctx, tt.userID)
 
 			assert.Equal(t, tt.expectedUsr, user)
 			assert.Equal(t, tt.expectedErr, err)
@@ -208,6 +227,7 @@ func Test_GetByIDs(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	ids := []int{1, 2}
 
@@ -219,7 +239,7 @@ func Test_GetByIDs(t *testing.T) {
 		{
 			"Get all users",
 			func() {
-				mockStore.EXPECT().GetByIDs(ids).Return([]models.User{
+				mockStore.EXPECT().GetByIDs(gomock.Any(), ids).Return([]models.User{
 					{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 180, Married: false},
 					{ID: 2, Fname: "Jane", City: "Los Angeles", Phone: "0987654321", Height: 160, Married: true},
 				})
@@ -232,7 +252,7 @@ func Test_GetByIDs(t *testing.T) {
 		{
 			"No users found",
 			func() {
-				mockStore.EXPECT().GetByIDs(ids).Return([]models.User{})
+				mockStore.EXPECT().GetByIDs(gomock.Any(), ids).Return([]models.User{})
 			},
 			[]models.User{},
 		},
@@ -242,7 +262,7 @@ func Test_GetByIDs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			users := service.GetByIDs(ids)
+			users := service.GetByIDs(ctx, ids)
 			assert.Equal(t, tt.expectedUsers, users)
 		})
 	}
@@ -254,6 +274,7 @@ func Test_Update(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	sampleUserReq := &models.UserUpdateRequest{
 		ID:      utils.IntPtr(1),
@@ -274,7 +295,7 @@ func Test_Update(t *testing.T) {
 		{
 			"Successful update", sampleUserReq,
 			func() {
-				mockStore.EXPECT().GetByID(1).Return(&models.User{
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID:      1,
 					Fname:   "John",
 					City:    "New York",
@@ -282,8 +303,8 @@ func Test_Update(t *testing.T) {
 					Height:  180,
 					Married: false,
 				}, nil)
-				mockStore.EXPECT().Update(gomock.Any()).Times(1)
-				mockStore.EXPECT().GetByID(1).Return(&models.User{
+				mockStore.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1)
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID:      1,
 					Fname:   "Johnny",
 					City:    "San Francisco",
@@ -305,7 +326,7 @@ func Test_Update(t *testing.T) {
 		{
 			"User not found", sampleUserReq,
 			func() {
-				mockStore.EXPECT().GetByID(1).Return(nil, errors.UserNotFound{ID: 1}).Times(1)
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(nil, errors.UserNotFound{ID: 1}).Times(1)
 			},
 			nil, errors.UserNotFound{ID: 1},
 		},
@@ -315,7 +336,7 @@ func Test_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			updatedUser, err := service.Update(tt.userRequest)
+			updatedUser, err := service.Update(ctx, tt.userRequest)
 
 			assert.Equal(t, tt.expectedUsr, updatedUser)
 			assert.Equal(t, tt.expectedErr, err)
@@ -329,6 +350,7 @@ func Test_Delete(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	tests := []struct {
 		name        string
@@ -339,17 +361,17 @@ func Test_Delete(t *testing.T) {
 		{
 			"Successful deletion", 1,
 			func() {
-				mockStore.EXPECT().GetByID(1).Return(&models.User{
+				mockStore.EXPECT().GetByID(gomock.Any(), 1).Return(&models.User{
 					ID: 1,
 				}, nil).Times(1)
-				mockStore.EXPECT().Delete(1).Times(1)
+				mockStore.EXPECT().Delete(gomock.Any(), 1).Times(1)
 			},
 			nil,
 		},
 		{
 			"User not found", 2,
 			func() {
-				mockStore.EXPECT().GetByID(2).Return(nil, errors.UserNotFound{ID: 2}).Times(1)
+				mockStore.EXPECT().GetByID(gomock.Any(), 2).Return(nil, errors.UserNotFound{ID: 2}).Times(1)
 			},
 			errors.UserNotFound{ID: 2},
 		},
@@ -359,7 +381,7 @@ func Test_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			err := service.Delete(tt.userID)
+			err := service.Delete(ctx, tt.userID)
 
 			assert.Equal(t, tt.expectedErr, err)
 		})
@@ -372,6 +394,7 @@ func Test_Search(t *testing.T) {
 
 	mockStore := store.NewMockUser(ctrl)
 	service := New(mockStore)
+	ctx := context.Background()
 
 	tests := []struct {
 		name          string
@@ -386,7 +409,7 @@ func Test_Search(t *testing.T) {
 				City:  utils.StrPtr("New York"),
 			},
 			func() {
-				mockStore.EXPECT().Get(&models.Filters{
+				mockStore.EXPECT().Get(gomock.Any(), &models.Filters{
 					Fname: utils.StrPtr("John"),
 					City:  utils.StrPtr("New York"),
 				}).Return([]models.User{
@@ -404,7 +427,7 @@ func Test_Search(t *testing.T) {
 				City:  utils.StrPtr("Los Angeles"),
 			},
 			func() {
-				mockStore.EXPECT().Get(&models.Filters{
+				mockStore.EXPECT().Get(gomock.Any(), &models.Filters{
 					Fname: utils.StrPtr("Jane"),
 					City:  utils.StrPtr("Los Angeles"),
 				}).Return([]models.User{})
@@ -417,9 +440,639 @@ func Test_Search(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			users := service.Search(tt.filters)
+			users := service.Search(ctx, tt.filters)
 
 			assert.Equal(t, tt.expectedUsers, users)
 		})
 	}
 }
+
+func Test_CountExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+	filters := &models.Filters{City: utils.StrPtr("Boston")}
+
+	mockStore.EXPECT().Count(gomock.Any(), filters).Return(2)
+	mockStore.EXPECT().Exists(gomock.Any(), filters).Return(true)
+
+	assert.Equal(t, 2, service.Count(ctx, filters))
+	assert.True(t, service.Exists(ctx, filters))
+}
+
+func Test_GetByPhone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+
+	mockStore.EXPECT().GetByPhone(gomock.Any(), "1234567890").Return([]models.User{{ID: 1, Phone: "1234567890"}})
+
+	assert.Equal(t, []models.User{{ID: 1, Phone: "1234567890"}}, service.GetByPhone(ctx, "1234567890"))
+}
+
+func Test_Upsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+
+	reqs := []*models.UserRequest{
+		{Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston"), Phone: utils.StrPtr("1234567890"), Height: utils.Float64Ptr(5.9), Married: utils.BoolPtr(true)},
+		{Fname: utils.StrPtr("Jane"), City: utils.StrPtr("Denver"), Phone: utils.StrPtr("0987654321"), Height: utils.Float64Ptr(165), Married: utils.BoolPtr(false)},
+	}
+	results := []store.UpsertResult{
+		{User: models.User{ID: 3, Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, Married: true}, Created: true},
+		{Err: errors.AmbiguousPhone{Matches: 2}},
+	}
+
+	// The whole batch is handed to the store at once.
+	mockStore.EXPECT().Upsert(gomock.Any(), []models.User{
+		{Fname: "John", City: "Boston", Phone: "1234567890", Height: 5.9, Married: true},
+		{Fname: "Jane", City: "Denver", Phone: "0987654321", Height: 165},
+	}).Return(results)
+
+	assert.Equal(t, results, service.Upsert(context.Background(), reqs))
+}
+
+func Test_CreateUniquenessIsPerTenant(t *testing.T) {
+	service := New(storeUser.New())
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	req := &models.UserRequest{
+		Fname:   utils.StrPtr("John"),
+		City:    utils.StrPtr("New York"),
+		Phone:   utils.StrPtr("1234567890"),
+		Height:  utils.Float64Ptr(180),
+		Married: utils.BoolPtr(false),
+	}
+
+	acmeUser, err := service.Create(acme, req)
+	assert.NoError(t, err)
+
+	globexUser, err := service.Create(globex, req)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, acmeUser.ID)
+	assert.Equal(t, 1, globexUser.ID)
+
+	_, err = service.Create(acme, req)
+	assert.Equal(t, errors.UserAlreadyExists{}, err)
+
+	assert.Len(t, service.Search(acme, &models.Filters{Fname: utils.StrPtr("John")}), 1)
+	assert.Empty(t, service.Get(context.Background()))
+}
+
+func Test_LoggerFromContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	service := New(storeUser.New())
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelDebug).With("request_id", "req-1"))
+
+	_, err := service.Create(ctx, &models.UserRequest{
+		Fname:   utils.StrPtr("John"),
+		City:    utils.StrPtr("New York"),
+		Phone:   utils.StrPtr("1234567890"),
+		Height:  utils.Float64Ptr(180),
+		Married: utils.BoolPtr(false),
+	})
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `"msg":"user created","request_id":"req-1","user_id":1`)
+}
+
+func Test_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	service := New(storeUser.New())
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, &models.UserRequest{
+		Fname:   utils.StrPtr("John"),
+		City:    utils.StrPtr("New York"),
+		Phone:   utils.StrPtr("1234567890"),
+		Height:  utils.Float64Ptr(5.9),
+		Married: utils.BoolPtr(false),
+	})
+	assert.NoError(t, err)
+
+	_, err = service.GetByID(ctx, 42)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	assert.Equal(t, "service.User/Search", spans[0].Name())
+	assert.Equal(t, "service.User/Create", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID(), "the duplicate check is a child of Create")
+	assert.Equal(t, "service.User/GetByID", spans[2].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func Test_BackupRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+
+	snapshot := store.Snapshot{LastInsertedID: 2, Users: []models.User{{ID: 2, Fname: "John"}}}
+
+	mockStore.EXPECT().Snapshot(gomock.Any()).Return(snapshot)
+	assert.Equal(t, snapshot, service.Backup(ctx))
+
+	mockStore.EXPECT().Restore(gomock.Any(), snapshot).Return(nil)
+	assert.NoError(t, service.Restore(ctx, snapshot))
+
+	mockStore.EXPECT().Restore(gomock.Any(), snapshot).Return(errors.StoreNotEmpty{Users: 1})
+	assert.Equal(t, errors.StoreNotEmpty{Users: 1}, service.Restore(ctx, snapshot))
+}
+
+func Test_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	filters := &models.Filters{City: utils.StrPtr("Boston")}
+
+	changed := make(chan struct{})
+	close(changed)
+
+	created := store.Event{Revision: 4, Type: store.EventCreated, User: models.User{ID: 1, Fname: "John"}}
+	deleted := store.Event{Revision: 6, Type: store.EventDeleted, User: models.User{ID: 1, Fname: "John"}}
+	sendErr := io.ErrClosedPipe
+
+	t.Run("From the current revision", func(t *testing.T) {
+		gomock.InOrder(
+			mockStore.EXPECT().Changes(gomock.Any(), filters, store.CurrentRevision).
+				Return(store.Changes{Events: []store.Event{}, Revision: 3, Next: changed}, nil),
+			mockStore.EXPECT().Changes(gomock.Any(), filters, int64(3)).
+				Return(store.Changes{Events: []store.Event{created}, Revision: 5, Next: changed}, nil),
+			mockStore.EXPECT().Changes(gomock.Any(), filters, int64(5)).
+				Return(store.Changes{Events: []store.Event{deleted}, Revision: 6, Next: changed}, nil),
+		)
+
+		var events []store.Event
+
+		err := service.Watch(context.Background(), filters, 0, func(event store.Event) error {
+			events = append(events, event)
+			if len(events) == 2 {
+				return sendErr
+			}
+
+			return nil
+		})
+
+		assert.Equal(t, sendErr, err)
+		assert.Equal(t, []store.Event{created, deleted}, events)
+	})
+
+	t.Run("Until the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mockStore.EXPECT().Changes(gomock.Any(), nil, int64(3)).
+			DoAndReturn(func(context.Context, *models.Filters, int64) (store.Changes, error) {
+				cancel()
+
+				return store.Changes{Events: []store.Event{}, Revision: 3, Next: make(chan struct{})}, nil
+			})
+
+		err := service.Watch(ctx, nil, 3, func(store.Event) error { return nil })
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("Compacted", func(t *testing.T) {
+		compacted := errors.RevisionCompacted{Revision: 1, Oldest: 2}
+
+		mockStore.EXPECT().Changes(gomock.Any(), nil, int64(1)).Return(store.Changes{}, compacted)
+
+		assert.Equal(t, compacted, service.Watch(context.Background(), nil, 1, func(store.Event) error { return nil }))
+	})
+
+	t.Run("Negative revision", func(t *testing.T) {
+		err := service.Watch(context.Background(), nil, -1, func(store.Event) error { return nil })
+		assert.Equal(t, errors.InvalidParams{Params: []string{"after_revision"}}, err)
+	})
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, events.Event) error {
+	return stdErrors.New("broker unreachable")
+}
+
+func Test_FindDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+
+	clusters := []store.DuplicateCluster{{Users: []models.User{{ID: 1}, {ID: 2}}, Rules: []store.DuplicateRule{store.DuplicatePhone}}}
+
+	tests := []struct {
+		name        string
+		query       store.DuplicatesQuery
+		mockCalls   []*gomock.Call
+		expected    []store.DuplicateCluster
+		expectedErr error
+	}{
+		{
+			name:  "Defaults",
+			query: store.DuplicatesQuery{},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().FindDuplicates(gomock.Any(), store.DuplicatesQuery{
+					Rules:         []store.DuplicateRule{store.DuplicatePhone, store.DuplicateNameCity},
+					MinSimilarity: 0.6,
+				}).Return(clusters),
+			},
+			expected: clusters,
+		},
+		{
+			name:  "Repeated rule",
+			query: store.DuplicatesQuery{Rules: []store.DuplicateRule{store.DuplicateNameCity, store.DuplicateNameCity}, MinSimilarity: 0.9},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().FindDuplicates(gomock.Any(), store.DuplicatesQuery{
+					Rules:         []store.DuplicateRule{store.DuplicateNameCity},
+					MinSimilarity: 0.9,
+				}).Return([]store.DuplicateCluster{}),
+			},
+			expected: []store.DuplicateCluster{},
+		},
+		{
+			name:        "Invalid query",
+			query:       store.DuplicatesQuery{Rules: []store.DuplicateRule{"height"}, MinSimilarity: 1.5},
+			expectedErr: errors.InvalidParams{Params: []string{"rules", "min_similarity"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := service.FindDuplicates(ctx, tc.query)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	publisher := events.NewMemory()
+	service := New(storeUser.New(storeUser.WithObserver(Publisher(publisher))))
+	ctx := context.Background()
+
+	john, err := service.Create(ctx, &models.UserRequest{
+		Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston"), Phone: utils.StrPtr("5550100000"),
+		Height: utils.Float64Ptr(5.9), Married: utils.BoolPtr(false),
+	})
+	assert.NoError(t, err)
+
+	jon, err := service.Create(ctx, &models.UserRequest{
+		Fname: utils.StrPtr("Jon"), City: utils.StrPtr("Denver"), Phone: utils.StrPtr("+1 555 010 0000"),
+		Height: utils.Float64Ptr(5.8), Married: utils.BoolPtr(true),
+	})
+	assert.NoError(t, err)
+
+	merged, err := service.Merge(ctx, &models.MergeRequest{
+		SurvivorID: john.ID, MergedID: jon.ID, City: models.MergeFromMerged, Married: models.MergeFromMerged,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.User{ID: john.ID, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9, Married: true}, merged)
+
+	_, err = service.Merge(ctx, &models.MergeRequest{SurvivorID: john.ID, MergedID: jon.ID})
+	assert.Equal(t, errors.UserNotFound{ID: jon.ID}, err)
+
+	_, err = service.Merge(ctx, &models.MergeRequest{SurvivorID: 7, MergedID: john.ID})
+	assert.Equal(t, errors.UserNotFound{ID: 7}, err)
+
+	_, err = service.Merge(ctx, &models.MergeRequest{SurvivorID: john.ID, MergedID: john.ID})
+	assert.Equal(t, errors.InvalidParams{Params: []string{"merged_id"}}, err)
+
+	published := publisher.Events()
+	if !assert.Len(t, published, 3, "failed merges publish nothing") {
+		return
+	}
+
+	assert.Equal(t, events.TypeMerged, published[2].Type)
+	assert.Equal(t, *merged, published[2].User)
+	assert.Equal(t, jon, published[2].MergedUser)
+}
+
+func Test_Merges(t *testing.T) {
+	service := New(storeUser.New())
+	ctx := context.Background()
+
+	john, err := service.Create(ctx, &models.UserRequest{
+		Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston"), Phone: utils.StrPtr("5550100000"),
+		Height: utils.Float64Ptr(5.9), Married: utils.BoolPtr(false),
+	})
+	require.NoError(t, err)
+
+	jon, err := service.Create(ctx, &models.UserRequest{
+		Fname: utils.StrPtr("Jon"), City: utils.StrPtr("Denver"), Phone: utils.StrPtr("5550100001"),
+		Height: utils.Float64Ptr(5.8), Married: utils.BoolPtr(true),
+	})
+	require.NoError(t, err)
+
+	merged, err := service.Merge(ctx, &models.MergeRequest{SurvivorID: john.ID, MergedID: jon.ID})
+	require.NoError(t, err)
+
+	merges, err := service.Merges(ctx, jon.ID)
+	assert.NoError(t, err)
+
+	if assert.Len(t, merges, 1) {
+		assert.Equal(t, *john, merges[0].Survivor)
+		assert.Equal(t, *jon, merges[0].Merged)
+		assert.Equal(t, *merged, merges[0].Result)
+	}
+
+	_, err = service.Merges(ctx, 0)
+	assert.Equal(t, errors.InvalidParams{Params: []string{"user_id"}}, err)
+}
+
+func Test_Publish(t *testing.T) {
+	publisher := events.NewMemory()
+	service := New(storeUser.New(storeUser.WithObserver(Publisher(publisher))))
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	req := &models.UserRequest{
+		Fname:   utils.StrPtr("John"),
+		City:    utils.StrPtr("New York"),
+		Phone:   utils.StrPtr("1234567890"),
+		Height:  utils.Float64Ptr(5.9),
+		Married: utils.BoolPtr(false),
+	}
+
+	created, err := service.Create(acme, req)
+	assert.NoError(t, err)
+
+	_, err = service.Create(acme, req)
+	assert.Equal(t, errors.UserAlreadyExists{}, err)
+
+	updated, err := service.Update(acme, &models.UserUpdateRequest{
+		ID: utils.IntPtr(created.ID), Fname: req.Fname, City: utils.StrPtr("Boston"), Phone: req.Phone, Height: req.Height, Married: req.Married,
+	})
+	assert.NoError(t, err)
+
+	upserted := service.Upsert(acme, []*models.UserRequest{{
+		Fname: req.Fname, City: req.City, Phone: utils.StrPtr("0987654321"), Height: req.Height, Married: req.Married,
+	}})
+	require.NoError(t, upserted[0].Err)
+
+	assert.NoError(t, service.Delete(acme, created.ID))
+	assert.Equal(t, errors.UserNotFound{ID: created.ID}, service.Delete(acme, created.ID))
+
+	assert.NoError(t, service.Restore(tenant.NewContext(context.Background(), "globex"), store.Snapshot{
+		LastInsertedID: 1, Users: []models.User{{ID: 1, Fname: "Jane"}},
+	}))
+
+	published := publisher.Events()
+	if !assert.Len(t, published, 5, "failed changes publish nothing") {
+		return
+	}
+
+	wants := []struct {
+		eventType, tenant string
+		revision          int64
+		user              models.User
+	}{
+		{events.TypeCreated, "acme", 1, *created},
+		{events.TypeUpdated, "acme", 2, *updated},
+		{events.TypeCreated, "acme", 3, upserted[0].User},
+		{events.TypeDeleted, "acme", 4, *updated},
+		{events.TypeRestored, "globex", 1, models.User{}},
+	}
+
+	for i, want := range wants {
+		assert.Equal(t, events.SchemaVersion, published[i].SchemaVersion)
+		assert.Equal(t, want.eventType, published[i].Type)
+		assert.Equal(t, want.tenant, published[i].Tenant)
+		assert.Equal(t, want.revision, published[i].Revision)
+		assert.Equal(t, want.user, published[i].User)
+	}
+
+	assert.Equal(t, 1, published[4].RestoredUsers)
+}
+
+func Test_PublishOrder(t *testing.T) {
+	publisher := events.NewMemory()
+	service := New(storeUser.New(storeUser.WithObserver(Publisher(publisher))))
+	ctx := context.Background()
+
+	req := &models.UserRequest{
+		Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston"), Phone: utils.StrPtr("1234567890"),
+		Height: utils.Float64Ptr(180), Married: utils.BoolPtr(false),
+	}
+
+	created, err := service.Create(ctx, req)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := service.Update(ctx, &models.UserUpdateRequest{
+				ID: utils.IntPtr(created.ID), Fname: req.Fname, City: utils.StrPtr(fmt.Sprintf("City %d", i)),
+				Phone: req.Phone, Height: req.Height, Married: req.Married,
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	published := publisher.Events()
+	require.Len(t, published, 21)
+
+	for i, event := range published {
+		assert.Equal(t, int64(i+1), event.Revision, "events are published in revision order")
+	}
+
+	stored, err := service.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *stored, published[20].User, "the last event published is the last change made")
+}
+
+func Test_PublishFailure(t *testing.T) {
+	var buf bytes.Buffer
+
+	service := New(storeUser.New(storeUser.WithObserver(Publisher(failingPublisher{}))))
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, slog.LevelDebug))
+
+	_, err := service.Create(ctx, &models.UserRequest{
+		Fname:   utils.StrPtr("John"),
+		City:    utils.StrPtr("New York"),
+		Phone:   utils.StrPtr("1234567890"),
+		Height:  utils.Float64Ptr(5.9),
+		Married: utils.BoolPtr(false),
+	})
+	assert.NoError(t, err, "the change is made even when its event is not published")
+
+	assert.Len(t, service.Get(ctx), 1)
+	assert.Contains(t, buf.String(), `"msg":"event not published","event_type":"user.created","user_id":1,"error":"broker unreachable"`)
+}
+
+func Test_Suggest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+
+	suggestions := []store.Suggestion{{User: models.User{ID: 1, Fname: "John"}, Score: 1, Field: store.SuggestFname}}
+
+	tests := []struct {
+		name        string
+		query       store.SuggestQuery
+		mockCalls   []*gomock.Call
+		expected    []store.Suggestion
+		expectedErr error
+	}{
+		{
+			name:  "Default limit",
+			query: store.SuggestQuery{Text: "jon"},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().Suggest(gomock.Any(), store.SuggestQuery{Text: "jon", Limit: 10}).Return(suggestions),
+			},
+			expected: suggestions,
+		},
+		{
+			name:  "Prefix on a field",
+			query: store.SuggestQuery{Text: "jo", Fields: []store.SuggestField{store.SuggestCity}, Prefix: true, Limit: 100},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().Suggest(gomock.Any(), store.SuggestQuery{
+					Text: "jo", Fields: []store.SuggestField{store.SuggestCity}, Prefix: true, Limit: 100,
+				}).Return([]store.Suggestion{}),
+			},
+			expected: []store.Suggestion{},
+		},
+		{
+			name:        "Blank text",
+			query:       store.SuggestQuery{Text: "  "},
+			expectedErr: errors.MissingParams{Params: []string{"text"}},
+		},
+		{
+			name:        "Invalid field and limit",
+			query:       store.SuggestQuery{Text: "jo", Fields: []store.SuggestField{"phone"}, Limit: 101},
+			expectedErr: errors.InvalidParams{Params: []string{"fields", "limit"}},
+		},
+		{
+			name:        "Negative limit",
+			query:       store.SuggestQuery{Text: "jo", Limit: -1},
+			expectedErr: errors.InvalidParams{Params: []string{"limit"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Suggest(ctx, tt.query)
+
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func Test_CreateDetectsDuplicatesIgnoringAccents(t *testing.T) {
+	service := New(storeUser.New())
+	ctx := context.Background()
+
+	req := func(fname, city string) *models.UserRequest {
+		return &models.UserRequest{
+			Fname:   utils.StrPtr(fname),
+			City:    utils.StrPtr(city),
+			Phone:   utils.StrPtr("1234567890"),
+			Height:  utils.Float64Ptr(5.9),
+			Married: utils.BoolPtr(false),
+		}
+	}
+
+	created, err := service.Create(ctx, req("José", " Zürich "))
+	assert.NoError(t, err)
+	assert.Equal(t, "Zürich", created.City)
+
+	for _, duplicate := range []*models.UserRequest{req("Jose\u0301", "Zu\u0308rich"), req("JOSE", "zurich")} {
+		_, err = service.Create(ctx, duplicate)
+		assert.Equal(t, errors.UserAlreadyExists{}, err)
+	}
+
+	_, err = service.Create(ctx, req("Josefa", "Zurich"))
+	assert.NoError(t, err)
+}
+
+func Test_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	service := New(mockStore)
+	ctx := context.Background()
+
+	stats := store.Stats{Total: store.StatsGroup{Count: 1}, Groups: []store.StatsGroup{{Key: "Boston", Count: 1}}}
+	filters := &models.Filters{City: utils.StrPtr("Boston")}
+
+	tests := []struct {
+		name        string
+		query       store.StatsQuery
+		mockCalls   []*gomock.Call
+		expected    store.Stats
+		expectedErr error
+	}{
+		{
+			name:  "Default buckets",
+			query: store.StatsQuery{Filters: filters, GroupBy: store.GroupByCity},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().Stats(gomock.Any(), store.StatsQuery{Filters: filters, GroupBy: store.GroupByCity, HistogramBuckets: 10}).Return(stats),
+			},
+			expected: stats,
+		},
+		{
+			name:  "Buckets",
+			query: store.StatsQuery{GroupBy: store.GroupByMarried, HistogramBuckets: 100},
+			mockCalls: []*gomock.Call{
+				mockStore.EXPECT().Stats(gomock.Any(), store.StatsQuery{GroupBy: store.GroupByMarried, HistogramBuckets: 100}).Return(stats),
+			},
+			expected: stats,
+		},
+		{
+			name:        "Invalid group and buckets",
+			query:       store.StatsQuery{GroupBy: "phone", HistogramBuckets: 101},
+			expectedErr: errors.InvalidParams{Params: []string{"group_by", "histogram_buckets"}},
+		},
+		{
+			name:        "Negative buckets",
+			query:       store.StatsQuery{HistogramBuckets: -1},
+			expectedErr: errors.InvalidParams{Params: []string{"histogram_buckets"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Stats(ctx, tt.query)
+
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -1,14 +1,271 @@
 package store
 
-import "github.com/ssshekhu53/user-detail-management/models"
+import (
+	"context"
+	"time"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+)
 
 //go:generate mockgen -source=interface.go -destination=mock_interface.go -package=store
 
+// User is scoped to the tenant carried by the context of each call.
 type User interface {
-	Create(user *models.User) int
-	Get(filters *models.Filters) []models.User
-	GetByID(id int) (*models.User, error)
-	GetByIDs(ids []int) []models.User
-	Update(user *models.User)
-	Delete(id int)
+	Create(ctx context.Context, user *models.User) int
+	Get(ctx context.Context, filters *models.Filters) []models.User
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []int) []models.User
+	// GetByPhone returns the users of the tenant holding phone, compared as normalized.
+	GetByPhone(ctx context.Context, phone string) []models.User
+	// Scan pages through the users matching filters in ID order: it returns at most limit users
+	// with an ID greater than afterID.
+	Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User
+	// Count returns the number of users of the tenant matching filters, or of every user when
+	// filters is nil.
+	Count(ctx context.Context, filters *models.Filters) int
+	// Exists reports whether a user of the tenant matches filters. It stops at the first match.
+	Exists(ctx context.Context, filters *models.Filters) bool
+	Update(ctx context.Context, user *models.User)
+	// Upsert creates every user of users, or updates the user of the tenant holding the same phone
+	// number, compared as normalized, in a single operation. Users are applied in order, so a user
+	// updates the one created by an earlier user of the same phone number. It returns the outcome
+	// of every user, in order.
+	Upsert(ctx context.Context, users []models.User) []UpsertResult
+	Delete(ctx context.Context, id int)
+	// Snapshot returns a consistent copy of the users and merges of the tenant, along with the last
+	// ID allocated to it.
+	Snapshot(ctx context.Context) Snapshot
+	// Restore loads snapshot into the directory of the tenant, which must hold no users, in a single
+	// change recorded as EventRestored. IDs allocated afterwards follow the last ID of the snapshot,
+	// and the merges of the snapshot are added to those of the tenant.
+	Restore(ctx context.Context, snapshot Snapshot) error
+	// Changes returns the changes of the tenant made after afterRevision to users matching filters,
+	// or none when afterRevision is CurrentRevision. It fails with errors.RevisionCompacted when
+	// changes following afterRevision are no longer kept.
+	Changes(ctx context.Context, filters *models.Filters, afterRevision int64) (Changes, error)
+	// Suggest returns the users of the tenant whose first name or city resembles query.Text, best
+	// matches first.
+	Suggest(ctx context.Context, query SuggestQuery) []Suggestion
+	// Stats aggregates the users of the tenant matching query.Filters, in total and by the value of
+	// query.GroupBy.
+	Stats(ctx context.Context, query StatsQuery) Stats
+	// FindDuplicates returns the clusters of users of the tenant linked by query.Rules, ordered by
+	// the ID of their first user.
+	FindDuplicates(ctx context.Context, query DuplicatesQuery) []DuplicateCluster
+	// Merge merges the user of ID req.MergedID into the user of ID req.SurvivorID, as done by
+	// req.Merge, and deletes it, reading and writing both users in a single change recorded as
+	// EventMerged and kept as a MergeRecord. It returns the survivor after the merge. It fails with
+	// errors.UserNotFound when either user does not exist, and with errors.InvalidParams when they
+	// are the same.
+	Merge(ctx context.Context, req models.MergeRequest) (*models.User, error)
+	// Merges returns the merges of the tenant the user of ID id took part in, as the survivor or as
+	// the merged user, oldest first.
+	Merges(ctx context.Context, id int) []MergeRecord
+
+	// Outbox returns, across tenants, at most limit messages of the outbox with an ID greater than
+	// afterID, along with a channel closed when the next message is written. Messages are written
+	// in the same operation as the change they announce. The outbox stays empty unless enabled.
+	Outbox(afterID int64, limit int) ([]OutboxMessage, <-chan struct{})
+	// AckOutbox removes the messages of the outbox with an ID up to id, once delivered.
+	AckOutbox(id int64)
+
+	// Usage reports the size of the store across all tenants. It is meant for monitoring.
+	Usage() Usage
+}
+
+// SuggestField is a field of the users matched by Suggest.
+type SuggestField string
+
+const (
+	SuggestFname SuggestField = "fname"
+	SuggestCity  SuggestField = "city"
+)
+
+type SuggestQuery struct {
+	Text string
+	// Fields are the fields to match, every field when empty.
+	Fields []SuggestField
+	// Prefix only matches the values starting with Text, for typeahead. Otherwise values are
+	// matched by similarity, so that misspellings still match.
+	Prefix bool
+	// Limit is the maximum number of suggestions returned.
+	Limit int
+}
+
+type Suggestion struct {
+	User models.User
+	// Score ranges from 0 to 1 for a value equal to the text, ignoring case and accents.
+	Score float64
+	// Field is the field of the best matching value.
+	Field SuggestField
+}
+
+// GroupBy is the field Stats groups users by.
+type GroupBy string
+
+const (
+	GroupByNone    GroupBy = ""
+	GroupByCity    GroupBy = "city"
+	GroupByMarried GroupBy = "married"
+)
+
+type StatsQuery struct {
+	Filters *models.Filters
+	GroupBy GroupBy
+	// HistogramBuckets is the number of buckets of the height histograms.
+	HistogramBuckets int
+}
+
+type Stats struct {
+	// Total aggregates every matching user.
+	Total StatsGroup
+	// Groups aggregate the matching users sharing a value of the GroupBy field, largest first.
+	Groups []StatsGroup
+}
+
+type StatsGroup struct {
+	// Key is the value shared by the users of the group: a city, as stored for the first of them,
+	// or "true" or "false" for married users. It is empty for the total.
+	Key   string
+	Count int
+	// Height is nil when the group has no users.
+	Height *HeightStats
+}
+
+type HeightStats struct {
+	Min float64
+	Max float64
+	Avg float64
+	// Percentiles are interpolated between the closest ranks.
+	P50 float64
+	P90 float64
+	P95 float64
+	P99 float64
+	// Histogram splits the range of heights of every matching user in buckets of equal width, so
+	// that the histograms of the groups share their buckets.
+	Histogram []HistogramBucket
+}
+
+type HistogramBucket struct {
+	// LowerBound is inclusive. UpperBound is exclusive, except for the last bucket.
+	LowerBound float64
+	UpperBound float64
+	Count      int
+}
+
+// DuplicateRule is a rule by which FindDuplicates links users.
+type DuplicateRule string
+
+const (
+	// DuplicatePhone links the users whose phone numbers have the same normalize.Phone key.
+	DuplicatePhone DuplicateRule = "phone"
+	// DuplicateNameCity links the users of the same city whose first names are similar.
+	DuplicateNameCity DuplicateRule = "name_city"
+)
+
+type DuplicatesQuery struct {
+	Rules []DuplicateRule
+	// MinSimilarity is the lowest similarity, from 0 to 1, of the first names DuplicateNameCity
+	// links, as scored by Suggest.
+	MinSimilarity float64
+}
+
+// DuplicateCluster is a group of users linked by the rules, directly or through one another.
+type DuplicateCluster struct {
+	// Users are sorted by ID.
+	Users []models.User
+	// Rules are the rules linking the users, in the order of the query.
+	Rules []DuplicateRule
+}
+
+// UpsertResult is the outcome of the upsert of a user.
+type UpsertResult struct {
+	// User is the user as stored.
+	User    models.User
+	Created bool
+	// Err is errors.AmbiguousPhone when several users hold the phone number, none of which is
+	// updated.
+	Err error
+}
+
+type Snapshot struct {
+	// LastInsertedID is the last ID allocated, which may belong to a deleted user.
+	LastInsertedID int
+	// Users are sorted by ID.
+	Users []models.User
+	// Merges are the merges made in the tenant, oldest first.
+	Merges []MergeRecord
+}
+
+// MergeRecord is a merge made in a tenant. Merge records are kept along with the users, unlike the
+// history of changes, which only holds the latest ones.
+type MergeRecord struct {
+	// Revision is the revision of the merge in the tenant it was made in.
+	Revision int64
+	MergedAt time.Time
+	// Survivor is the user kept, as it was before the merge.
+	Survivor models.User
+	// Merged is the user merged into Survivor, as it was before being deleted.
+	Merged models.User
+	// Result is Survivor after the merge.
+	Result models.User
+}
+
+// EventType tells how a change affected a user, or the users of a tenant.
+type EventType int
+
+const (
+	EventCreated EventType = iota + 1
+	EventUpdated
+	EventDeleted
+	// EventMerged replaces a user with the merge of it and another user, which is deleted.
+	EventMerged
+	// EventRestored loads the users of a tenant from a snapshot at once. It carries no user: those
+	// following the users of the tenant list them again.
+	EventRestored
+)
+
+// Event is a change made to a user. Every change of a tenant gets the next revision of the tenant.
+type Event struct {
+	Revision int64
+	Type     EventType
+	// User is the user after the change, or before it for deletions.
+	User models.User
+	// Previous is User before the change, for EventUpdated and EventMerged.
+	Previous *models.User
+	// Merged is the user merged into User, as it was before being deleted, for EventMerged.
+	Merged *models.User
+	// Restored is the number of users loaded, for EventRestored.
+	Restored int
+}
+
+// Observer is told about a change made to a user of tenant while the change is made.
+type Observer func(ctx context.Context, tenant string, event Event)
+
+// OutboxMessage announces a change to be delivered to other systems.
+type OutboxMessage struct {
+	// ID increases with every message, across tenants.
+	ID        int64
+	Tenant    string
+	Event     Event
+	CreatedAt time.Time
+}
+
+// CurrentRevision asks Changes for no past change, only for the revision to follow changes from.
+const CurrentRevision int64 = -1
+
+type Changes struct {
+	// Events are sorted by revision.
+	Events []Event
+	// Revision is the current revision, from which the next changes are to be asked.
+	Revision int64
+	// Next is closed at the next change of the tenant.
+	Next <-chan struct{}
+}
+
+type Usage struct {
+	// UsersByTenant holds the number of users of every tenant.
+	UsersByTenant map[string]int
+	// IndexSizes holds the number of entries of every index kept by the store, keyed by index name.
+	IndexSizes map[string]int
 }
@@ -10,6 +10,7 @@
 package store
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/ssshekhu53/user-detail-management/models"
@@ -39,83 +40,308 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 	return m.recorder
 }
 
+// AckOutbox mocks base method.
+func (m *MockUser) AckOutbox(id int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AckOutbox", id)
+}
+
+// AckOutbox indicates an expected call of AckOutbox.
+func (mr *MockUserMockRecorder) AckOutbox(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckOutbox", reflect.TypeOf((*MockUser)(nil).AckOutbox), id)
+}
+
+// Changes mocks base method.
+func (m *MockUser) Changes(ctx context.Context, filters *models.Filters, afterRevision int64) (Changes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes", ctx, filters, afterRevision)
+	ret0, _ := ret[0].(Changes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Changes indicates an expected call of Changes.
+func (mr *MockUserMockRecorder) Changes(ctx, filters, afterRevision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockUser)(nil).Changes), ctx, filters, afterRevision)
+}
+
+// Count mocks base method.
+func (m *MockUser) Count(ctx context.Context, filters *models.Filters) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filters)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserMockRecorder) Count(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUser)(nil).Count), ctx, filters)
+}
+
 // Create mocks base method.
-func (m *MockUser) Create(user *models.User) int {
+func (m *MockUser) Create(ctx context.Context, user *models.User) int {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, user)
 	ret0, _ := ret[0].(int)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserMockRecorder) Create(user any) *gomock.Call {
+func (mr *MockUserMockRecorder) Create(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUser)(nil).Create), ctx, user)
 }
 
 // Delete mocks base method.
-func (m *MockUser) Delete(id int) {
+func (m *MockUser) Delete(ctx context.Context, id int) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Delete", id)
+	m.ctrl.Call(m, "Delete", ctx, id)
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockUserMockRecorder) Delete(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), ctx, id)
+}
+
+// Exists mocks base method.
+func (m *MockUser) Exists(ctx context.Context, filters *models.Filters) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, filters)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockUserMockRecorder) Exists(ctx, filters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockUser)(nil).Exists), ctx, filters)
+}
+
+// FindDuplicates mocks base method.
+func (m *MockUser) FindDuplicates(ctx context.Context, query DuplicatesQuery) []DuplicateCluster {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", ctx, query)
+	ret0, _ := ret[0].([]DuplicateCluster)
+	return ret0
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockUserMockRecorder) FindDuplicates(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockUser)(nil).FindDuplicates), ctx, query)
 }
 
 // Get mocks base method.
-func (m *MockUser) Get(filters *models.Filters) []models.User {
+func (m *MockUser) Get(ctx context.Context, filters *models.Filters) []models.User {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", filters)
+	ret := m.ctrl.Call(m, "Get", ctx, filters)
 	ret0, _ := ret[0].([]models.User)
 	return ret0
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockUserMockRecorder) Get(filters any) *gomock.Call {
+func (mr *MockUserMockRecorder) Get(ctx, filters any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get), filters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get), ctx, filters)
 }
 
 // GetByID mocks base method.
-func (m *MockUser) GetByID(id int) (*models.User, error) {
+func (m *MockUser) GetByID(ctx context.Context, id int) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockUserMockRecorder) GetByID(id any) *gomock.Call {
+func (mr *MockUserMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUser)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUser)(nil).GetByID), ctx, id)
 }
 
 // GetByIDs mocks base method.
-func (m *MockUser) GetByIDs(ids []int) []models.User {
+func (m *MockUser) GetByIDs(ctx context.Context, ids []int) []models.User {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIDs", ids)
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
 	ret0, _ := ret[0].([]models.User)
 	return ret0
 }
 
 // GetByIDs indicates an expected call of GetByIDs.
-func (mr *MockUserMockRecorder) GetByIDs(ids any) *gomock.Call {
+func (mr *MockUserMockRecorder) GetByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUser)(nil).GetByIDs), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockUser)(nil).GetByIDs), ctx, ids)
+}
+
+// GetByPhone mocks base method.
+func (m *MockUser) GetByPhone(ctx context.Context, phone string) []models.User {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPhone", ctx, phone)
+	ret0, _ := ret[0].([]models.User)
+	return ret0
+}
+
+// GetByPhone indicates an expected call of GetByPhone.
+func (mr *MockUserMockRecorder) GetByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPhone", reflect.TypeOf((*MockUser)(nil).GetByPhone), ctx, phone)
+}
+
+// Merge mocks base method.
+func (m *MockUser) Merge(ctx context.Context, req models.MergeRequest) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, req)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockUserMockRecorder) Merge(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockUser)(nil).Merge), ctx, req)
+}
+
+// Merges mocks base method.
+func (m *MockUser) Merges(ctx context.Context, id int) []MergeRecord {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merges", ctx, id)
+	ret0, _ := ret[0].([]MergeRecord)
+	return ret0
+}
+
+// Merges indicates an expected call of Merges.
+func (mr *MockUserMockRecorder) Merges(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merges", reflect.TypeOf((*MockUser)(nil).Merges), ctx, id)
+}
+
+// Outbox mocks base method.
+func (m *MockUser) Outbox(afterID int64, limit int) ([]OutboxMessage, <-chan struct{}) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Outbox", afterID, limit)
+	ret0, _ := ret[0].([]OutboxMessage)
+	ret1, _ := ret[1].(<-chan struct{})
+	return ret0, ret1
+}
+
+// Outbox indicates an expected call of Outbox.
+func (mr *MockUserMockRecorder) Outbox(afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Outbox", reflect.TypeOf((*MockUser)(nil).Outbox), afterID, limit)
+}
+
+// Restore mocks base method.
+func (m *MockUser) Restore(ctx context.Context, snapshot Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserMockRecorder) Restore(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUser)(nil).Restore), ctx, snapshot)
+}
+
+// Scan mocks base method.
+func (m *MockUser) Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, filters, afterID, limit)
+	ret0, _ := ret[0].([]models.User)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockUserMockRecorder) Scan(ctx, filters, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockUser)(nil).Scan), ctx, filters, afterID, limit)
+}
+
+// Snapshot mocks base method.
+func (m *MockUser) Snapshot(ctx context.Context) Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(Snapshot)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockUserMockRecorder) Snapshot(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockUser)(nil).Snapshot), ctx)
+}
+
+// Stats mocks base method.
+func (m *MockUser) Stats(ctx context.Context, query StatsQuery) Stats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx, query)
+	ret0, _ := ret[0].(Stats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockUserMockRecorder) Stats(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockUser)(nil).Stats), ctx, query)
+}
+
+// Suggest mocks base method.
+func (m *MockUser) Suggest(ctx context.Context, query SuggestQuery) []Suggestion {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, query)
+	ret0, _ := ret[0].([]Suggestion)
+	return ret0
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockUserMockRecorder) Suggest(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockUser)(nil).Suggest), ctx, query)
 }
 
 // Update mocks base method.
-func (m *MockUser) Update(user *models.User) {
+func (m *MockUser) Update(ctx context.Context, user *models.User) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Update", user)
+	m.ctrl.Call(m, "Update", ctx, user)
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserMockRecorder) Update(user any) *gomock.Call {
+func (mr *MockUserMockRecorder) Update(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), ctx, user)
+}
+
+// Upsert mocks base method.
+func (m *MockUser) Upsert(ctx context.Context, users []models.User) []UpsertResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, users)
+	ret0, _ := ret[0].([]UpsertResult)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockUserMockRecorder) Upsert(ctx, users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockUser)(nil).Upsert), ctx, users)
+}
+
+// Usage mocks base method.
+func (m *MockUser) Usage() Usage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage")
+	ret0, _ := ret[0].(Usage)
+	return ret0
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockUserMockRecorder) Usage() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockUser)(nil).Usage))
 }
@@ -0,0 +1,164 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/normalize"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// clusters groups IDs linked by rules, as a disjoint-set forest.
+type clusters struct {
+	parent map[int]int
+	// rules holds the rules linking the IDs of every root.
+	rules map[int]map[store.DuplicateRule]struct{}
+}
+
+func newClusters() *clusters {
+	return &clusters{parent: make(map[int]int), rules: make(map[int]map[store.DuplicateRule]struct{})}
+}
+
+func (c *clusters) root(id int) int {
+	parent, ok := c.parent[id]
+	if !ok || parent == id {
+		return id
+	}
+
+	root := c.root(parent)
+	c.parent[id] = root
+
+	return root
+}
+
+// link puts a and b in the same cluster, recording that rule links them.
+func (c *clusters) link(a, b int, rule store.DuplicateRule) {
+	// The lowest ID is kept as root, so the root of a cluster is its first user.
+	root, other := c.root(a), c.root(b)
+	root, other = min(root, other), max(root, other)
+
+	c.parent[root] = root
+
+	if c.rules[root] == nil {
+		c.rules[root] = make(map[store.DuplicateRule]struct{})
+	}
+
+	if other != root {
+		c.parent[other] = root
+
+		for linked := range c.rules[other] {
+			c.rules[root][linked] = struct{}{}
+		}
+
+		delete(c.rules, other)
+	}
+
+	c.rules[root][rule] = struct{}{}
+}
+
+func (u *user) FindDuplicates(ctx context.Context, query store.DuplicatesQuery) []store.DuplicateCluster {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	duplicates := make([]store.DuplicateCluster, 0)
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return duplicates
+	}
+
+	c := newClusters()
+
+	for _, rule := range query.Rules {
+		switch rule {
+		case store.DuplicatePhone:
+			linkPhones(dir, c)
+		case store.DuplicateNameCity:
+			linkNamesInCities(dir, c, query.MinSimilarity)
+		}
+	}
+
+	members := make(map[int][]models.User)
+
+	for id := range c.parent {
+		root := c.root(id)
+		members[root] = append(members[root], dir.users[id])
+	}
+
+	for root, users := range members {
+		sort.Slice(users, func(i, j int) bool {
+			return users[i].ID < users[j].ID
+		})
+
+		cluster := store.DuplicateCluster{Users: users, Rules: make([]store.DuplicateRule, 0, len(c.rules[root]))}
+
+		for _, rule := range query.Rules {
+			if _, ok := c.rules[root][rule]; ok && !slices.Contains(cluster.Rules, rule) {
+				cluster.Rules = append(cluster.Rules, rule)
+			}
+		}
+
+		duplicates = append(duplicates, cluster)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Users[0].ID < duplicates[j].Users[0].ID
+	})
+
+	return duplicates
+}
+
+// linkPhones links the users of dir whose phone numbers have the same key.
+func linkPhones(dir *directory, c *clusters) {
+	first := make(map[string]int)
+
+	for id, usr := range dir.users {
+		key := normalize.Phone(usr.Phone)
+		if key == "" {
+			continue
+		}
+
+		if other, ok := first[key]; ok {
+			c.link(other, id, store.DuplicatePhone)
+		} else {
+			first[key] = id
+		}
+	}
+}
+
+// linkNamesInCities links the users of dir of the same city whose first names score at least
+// minSimilarity, looking similar names up in the index of first names.
+func linkNamesInCities(dir *directory, c *clusters, minSimilarity float64) {
+	for _, fname := range dir.fnames.sorted {
+		// Users holding the same first name are linked to each other, as its score with itself is 1.
+		for similar := range dir.fnames.similar(fname, minSimilarity) {
+			if similar < fname {
+				// The pair was linked from the other name.
+				continue
+			}
+
+			linkSameCity(dir, c, dir.fnames.ids(fname), dir.fnames.ids(similar))
+		}
+	}
+}
+
+// linkSameCity links the users of ids to the users of others living in the same city.
+func linkSameCity(dir *directory, c *clusters, ids, others map[int]struct{}) {
+	// Others are grouped by city once, so that every user of ids only meets those of its city.
+	byCity := make(map[string][]int)
+
+	for other := range others {
+		city := normalize.Key(dir.users[other].City)
+		byCity[city] = append(byCity[city], other)
+	}
+
+	for id := range ids {
+		for _, other := range byCity[normalize.Key(dir.users[id].City)] {
+			if other != id {
+				c.link(id, other, store.DuplicateNameCity)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+func Test_FindDuplicates(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	for _, usr := range []models.User{
+		{Fname: "John", City: "Boston", Phone: "555-010-0000"},
+		{Fname: "Jon", City: "boston", Phone: "5550100001"},
+		{Fname: "Jane", City: "Denver", Phone: "+1 (555) 010-0000"},
+		{Fname: "John", City: "Denver", Phone: "5550100003"},
+		{Fname: "José", City: "Zürich", Phone: "5550100004"},
+		{Fname: "JOSE", City: "Zurich", Phone: "5550100005"},
+		{Fname: "Alice", City: "Paris", Phone: "5550100006"},
+	} {
+		u.Create(ctx, &usr)
+	}
+
+	both := []store.DuplicateRule{store.DuplicatePhone, store.DuplicateNameCity}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		query     store.DuplicatesQuery
+		wantIDs   [][]int
+		wantRules [][]store.DuplicateRule
+	}{
+		{
+			"Phone",
+			ctx,
+			store.DuplicatesQuery{Rules: []store.DuplicateRule{store.DuplicatePhone}},
+			[][]int{{1, 3}},
+			[][]store.DuplicateRule{{store.DuplicatePhone}},
+		},
+		{
+			"Name and city",
+			ctx,
+			store.DuplicatesQuery{Rules: []store.DuplicateRule{store.DuplicateNameCity}, MinSimilarity: 0.6},
+			[][]int{{1, 2}, {5, 6}},
+			[][]store.DuplicateRule{{store.DuplicateNameCity}, {store.DuplicateNameCity}},
+		},
+		{
+			"Both rules chain clusters",
+			ctx,
+			store.DuplicatesQuery{Rules: both, MinSimilarity: 0.6},
+			[][]int{{1, 2, 3}, {5, 6}},
+			[][]store.DuplicateRule{both, {store.DuplicateNameCity}},
+		},
+		{
+			"Stricter similarity",
+			ctx,
+			store.DuplicatesQuery{Rules: []store.DuplicateRule{store.DuplicateNameCity}, MinSimilarity: 1},
+			[][]int{{5, 6}},
+			[][]store.DuplicateRule{{store.DuplicateNameCity}},
+		},
+		{"No rule", ctx, store.DuplicatesQuery{}, [][]int{}, [][]store.DuplicateRule{}},
+		{"Other tenant", tenant.NewContext(ctx, "acme"), store.DuplicatesQuery{Rules: both}, [][]int{}, [][]store.DuplicateRule{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, rules := [][]int{}, [][]store.DuplicateRule{}
+
+			for _, cluster := range u.FindDuplicates(tt.ctx, tt.query) {
+				clusterIDs := []int{}
+
+				for _, usr := range cluster.Users {
+					clusterIDs = append(clusterIDs, usr.ID)
+				}
+
+				ids = append(ids, clusterIDs)
+				rules = append(rules, cluster.Rules)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantRules, rules)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/normalize"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// statsGroup collects the heights of the users of a group.
+type statsGroup struct {
+	key     string
+	firstID int
+	heights []float64
+}
+
+func (u *user) Stats(ctx context.Context, query store.StatsQuery) store.Stats {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	stats := store.Stats{Groups: make([]store.StatsGroup, 0)}
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return stats
+	}
+
+	heights := make([]float64, 0)
+	groups := make(map[string]*statsGroup)
+
+	for _, usr := range dir.users {
+		if query.Filters != nil && !u.isMatch(&usr, query.Filters) {
+			continue
+		}
+
+		heights = append(heights, usr.Height)
+
+		if query.GroupBy == store.GroupByNone {
+			continue
+		}
+
+		id, key := groupKey(usr, query.GroupBy)
+
+		group, ok := groups[id]
+		if !ok {
+			group = &statsGroup{key: key, firstID: usr.ID}
+			groups[id] = group
+		}
+
+		// Cities spelled differently share a group, named after its first user.
+		if usr.ID < group.firstID {
+			group.key, group.firstID = key, usr.ID
+		}
+
+		group.heights = append(group.heights, usr.Height)
+	}
+
+	lo, hi := 0.0, 0.0
+	if len(heights) > 0 {
+		lo, hi = slices.Min(heights), slices.Max(heights)
+	}
+
+	stats.Total = newStatsGroup("", heights, lo, hi, query.HistogramBuckets)
+
+	for _, group := range groups {
+		stats.Groups = append(stats.Groups, newStatsGroup(group.key, group.heights, lo, hi, query.HistogramBuckets))
+	}
+
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		if stats.Groups[i].Count != stats.Groups[j].Count {
+			return stats.Groups[i].Count > stats.Groups[j].Count
+		}
+
+		return stats.Groups[i].Key < stats.Groups[j].Key
+	})
+
+	return stats
+}
+
+// groupKey returns the value identifying the group of usr, and the key naming it.
+func groupKey(usr models.User, groupBy store.GroupBy) (string, string) {
+	switch groupBy {
+	case store.GroupByCity:
+		return normalize.Key(usr.City), usr.City
+	case store.GroupByMarried:
+		return strconv.FormatBool(usr.Married), strconv.FormatBool(usr.Married)
+	}
+
+	return "", ""
+}
+
+// newStatsGroup aggregates heights, spreading them in buckets histogram buckets of equal width
+// from lo to hi.
+func newStatsGroup(key string, heights []float64, lo, hi float64, buckets int) store.StatsGroup {
+	group := store.StatsGroup{Key: key, Count: len(heights)}
+
+	if len(heights) == 0 {
+		return group
+	}
+
+	sorted := slices.Clone(heights)
+	slices.Sort(sorted)
+
+	sum := 0.0
+	for _, height := range sorted {
+		sum += height
+	}
+
+	group.Height = &store.HeightStats{
+		Min:       sorted[0],
+		Max:       sorted[len(sorted)-1],
+		Avg:       sum / float64(len(sorted)),
+		P50:       percentile(sorted, 0.5),
+		P90:       percentile(sorted, 0.9),
+		P95:       percentile(sorted, 0.95),
+		P99:       percentile(sorted, 0.99),
+		Histogram: histogram(sorted, lo, hi, buckets),
+	}
+
+	return group
+}
+
+// percentile interpolates the p-th quantile of sorted between its closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	i := int(math.Floor(rank))
+
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[i] + (sorted[i+1]-sorted[i])*(rank-float64(i))
+}
+
+// histogram counts the heights falling in each of buckets buckets of equal width from lo to hi.
+// A single bucket is returned when every height is the same.
+func histogram(heights []float64, lo, hi float64, buckets int) []store.HistogramBucket {
+	if buckets <= 0 {
+		return []store.HistogramBucket{}
+	}
+
+	if lo == hi {
+		return []store.HistogramBucket{{LowerBound: lo, UpperBound: hi, Count: len(heights)}}
+	}
+
+	width := (hi - lo) / float64(buckets)
+	histogram := make([]store.HistogramBucket, buckets)
+
+	for i := range histogram {
+		histogram[i].LowerBound = lo + float64(i)*width
+		histogram[i].UpperBound = lo + float64(i+1)*width
+	}
+
+	histogram[buckets-1].UpperBound = hi
+
+	for _, height := range heights {
+		i := min(int((height-lo)/width), buckets-1)
+
+		// Rounding may put a height right on a bound in the bucket below.
+		if i+1 < buckets && height >= histogram[i+1].LowerBound {
+			i++
+		}
+
+		histogram[i].Count++
+	}
+
+	return histogram
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+	"github.com/ssshekhu53/user-detail-management/utils"
+)
+
+func Test_Stats(t *testing.T) {
+	u := New()
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	for _, usr := range []models.User{
+		{Fname: "John", City: "Boston", Height: 170, Married: true},
+		{Fname: "Jane", City: "boston", Height: 160},
+		{Fname: "Jim", City: "Denver", Height: 180, Married: true},
+		{Fname: "Joe", City: "Denver", Height: 190},
+		{Fname: "Ann", City: "Austin", Height: 150, Married: true},
+	} {
+		u.Create(acme, &usr)
+	}
+
+	u.Create(context.Background(), &models.User{Fname: "Jim", City: "Denver", Height: 200})
+
+	t.Run("Total", func(t *testing.T) {
+		stats := u.Stats(acme, store.StatsQuery{HistogramBuckets: 4})
+
+		assert.Empty(t, stats.Groups)
+		assert.Equal(t, 5, stats.Total.Count)
+		require.NotNil(t, stats.Total.Height)
+
+		height := stats.Total.Height
+		assert.Equal(t, 150.0, height.Min)
+		assert.Equal(t, 190.0, height.Max)
+		assert.Equal(t, 170.0, height.Avg)
+		assert.Equal(t, 170.0, height.P50)
+		assert.InDelta(t, 186, height.P90, 1e-9)
+		assert.InDelta(t, 188, height.P95, 1e-9)
+		assert.InDelta(t, 189.6, height.P99, 1e-9)
+		assert.Equal(t, []store.HistogramBucket{
+			{LowerBound: 150, UpperBound: 160, Count: 1},
+			{LowerBound: 160, UpperBound: 170, Count: 1},
+			{LowerBound: 170, UpperBound: 180, Count: 1},
+			{LowerBound: 180, UpperBound: 190, Count: 2},
+		}, height.Histogram, "the last bucket holds its upper bound")
+	})
+
+	t.Run("By city", func(t *testing.T) {
+		stats := u.Stats(acme, store.StatsQuery{GroupBy: store.GroupByCity, HistogramBuckets: 4})
+
+		require.Len(t, stats.Groups, 3)
+		assert.Equal(t, 5, stats.Total.Count)
+
+		boston := stats.Groups[0]
+		assert.Equal(t, "Boston", boston.Key, "cities are grouped ignoring case, named after the first user")
+		assert.Equal(t, 2, boston.Count)
+		assert.Equal(t, 165.0, boston.Height.Avg)
+		assert.Equal(t, 165.0, boston.Height.P50)
+		assert.Equal(t, []int{0, 1, 1, 0}, bucketCounts(boston.Height.Histogram), "groups share the buckets of the total")
+
+		assert.Equal(t, "Denver", stats.Groups[1].Key)
+		assert.Equal(t, 2, stats.Groups[1].Count)
+		assert.Equal(t, "Austin", stats.Groups[2].Key)
+		assert.Equal(t, 1, stats.Groups[2].Count)
+		assert.Equal(t, 150.0, stats.Groups[2].Height.P99)
+	})
+
+	t.Run("By married with filters", func(t *testing.T) {
+		stats := u.Stats(acme, store.StatsQuery{
+			Filters:          &models.Filters{City: utils.StrPtr("Denver")},
+			GroupBy:          store.GroupByMarried,
+			HistogramBuckets: 2,
+		})
+
+		assert.Equal(t, 2, stats.Total.Count)
+		require.Len(t, stats.Groups, 2)
+		assert.Equal(t, store.StatsGroup{Key: "false", Count: 1, Height: &store.HeightStats{
+			Min: 190, Max: 190, Avg: 190, P50: 190, P90: 190, P95: 190, P99: 190,
+			Histogram: []store.HistogramBucket{{LowerBound: 180, UpperBound: 185}, {LowerBound: 185, UpperBound: 190, Count: 1}},
+		}}, stats.Groups[0])
+		assert.Equal(t, "true", stats.Groups[1].Key)
+	})
+
+	t.Run("Same heights", func(t *testing.T) {
+		stats := u.Stats(acme, store.StatsQuery{Filters: &models.Filters{Fname: utils.StrPtr("john")}, HistogramBuckets: 10})
+
+		assert.Equal(t, []store.HistogramBucket{{LowerBound: 170, UpperBound: 170, Count: 1}}, stats.Total.Height.Histogram)
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		stats := u.Stats(acme, store.StatsQuery{Filters: &models.Filters{City: utils.StrPtr("Paris")}, GroupBy: store.GroupByCity})
+
+		assert.Equal(t, store.Stats{Total: store.StatsGroup{}, Groups: []store.StatsGroup{}}, stats)
+	})
+
+	t.Run("Unknown tenant", func(t *testing.T) {
+		stats := u.Stats(tenant.NewContext(context.Background(), "globex"), store.StatsQuery{HistogramBuckets: 10})
+
+		assert.Equal(t, store.Stats{Groups: []store.StatsGroup{}}, stats)
+	})
+}
+
+func bucketCounts(histogram []store.HistogramBucket) []int {
+	counts := make([]int, 0, len(histogram))
+
+	for _, bucket := range histogram {
+		counts = append(counts, bucket.Count)
+	}
+
+	return counts
+}
+
+func Test_percentile(t *testing.T) {
+	assert.Equal(t, 5.0, percentile([]float64{5}, 0.99))
+	assert.Equal(t, 1.5, percentile([]float64{1, 2}, 0.5))
+	assert.Equal(t, 2.0, percentile([]float64{1, 2, 3}, 0.5))
+	assert.Equal(t, 3.0, percentile([]float64{1, 2, 3}, 1))
+}
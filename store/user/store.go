@@ -1,41 +1,277 @@
 package user
 
 import (
+	"context"
+	"slices"
 	"sort"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/logging"
 	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/normalize"
 	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
 )
 
-type user struct {
+// historySize is the number of changes kept per tenant for watchers to resume from.
+const historySize = 10000
+
+// minSimilarity is the lowest score of the values matched by similarity.
+const minSimilarity = 0.4
+
+// directory holds the users of a single tenant. IDs are allocated per directory, so every tenant
+// has its own ID sequence.
+type directory struct {
+	tenant         string
 	users          map[int]models.User
 	lastInsertedID int
+
+	fnames *textIndex
+	cities *textIndex
+	// phones holds the IDs of the users of every phone number, as normalized.
+	phones map[string]map[int]struct{}
+
+	// merges holds every merge, oldest first, and mergesByUser the indexes in merges of the merges
+	// of every user.
+	merges       []store.MergeRecord
+	mergesByUser map[int][]int
+
+	// revision is the revision of the last change, whose latest ones are kept in history.
+	revision int64
+	history  []store.Event
+	// changed is closed, and replaced, at every change.
+	changed chan struct{}
+}
+
+// outbox holds the messages announcing changes until they are delivered.
+type outbox struct {
+	enabled  bool
+	lastID   int64
+	messages []store.OutboxMessage
+	// written is closed, and replaced, at every message.
+	written chan struct{}
+}
+
+type user struct {
+	mu          sync.RWMutex
+	directories map[string]*directory
+	historySize int
+	outbox      outbox
+	observers   []store.Observer
+}
+
+// Option configures the store returned by New.
+type Option func(*user)
+
+// WithOutbox makes every change write a message to the outbox, to be delivered by a dispatcher
+// acknowledging them. The outbox grows without bound when messages are never acknowledged.
+func WithOutbox() Option {
+	return func(u *user) {
+		u.outbox.enabled = true
+	}
+}
+
+// WithObserver makes every change call observer with its event, revision included, from under the
+// lock of the change, so that observers see the changes in the order they are made. observer holds
+// up every call to the store until it returns, so it must not wait on I/O, such as by queueing the
+// work, and must not call the store.
+func WithObserver(observer store.Observer) Option {
+	return func(u *user) {
+		u.observers = append(u.observers, observer)
+	}
 }
 
-func New() store.User {
-	u := &user{}
-	u.users = make(map[int]models.User)
-	u.lastInsertedID = 0
+func New(opts ...Option) store.User {
+	u := &user{historySize: historySize}
+	u.directories = make(map[string]*directory)
+	u.outbox.written = make(chan struct{})
+
+	for _, opt := range opts {
+		opt(u)
+	}
 
 	return u
 }
 
-func (u *user) Create(userReq *models.User) int {
-	u.lastInsertedID += 1
+// record appends a change to the history of dir, with the next revision, waking up its watchers,
+// tells the observers about it and appends it to the outbox. It must be called with u.mu held.
+func (u *user) record(ctx context.Context, dir *directory, event store.Event) {
+	dir.revision++
+
+	event.Revision = dir.revision
+
+	dir.history = append(dir.history, event)
+	if len(dir.history) > u.historySize {
+		dir.history = dir.history[len(dir.history)-u.historySize:]
+	}
+
+	close(dir.changed)
+	dir.changed = make(chan struct{})
+
+	for _, observe := range u.observers {
+		observe(ctx, dir.tenant, event)
+	}
+
+	if !u.outbox.enabled {
+		return
+	}
+
+	u.outbox.lastID++
+	u.outbox.messages = append(u.outbox.messages, store.OutboxMessage{
+		ID:        u.outbox.lastID,
+		Tenant:    dir.tenant,
+		Event:     event,
+		CreatedAt: time.Now(),
+	})
+
+	close(u.outbox.written)
+	u.outbox.written = make(chan struct{})
+}
+
+// put stores usr in dir, replacing the user of the same ID, and indexes it.
+func (dir *directory) put(usr models.User) {
+	dir.remove(usr.ID)
 
-	userReq.ID = u.lastInsertedID
+	dir.users[usr.ID] = usr
+	dir.fnames.add(usr.ID, usr.Fname)
+	dir.cities.add(usr.ID, usr.City)
 
-	u.users[u.lastInsertedID] = *userReq
+	phone := normalize.Phone(usr.Phone)
+	if dir.phones[phone] == nil {
+		dir.phones[phone] = make(map[int]struct{})
+	}
 
-	return u.lastInsertedID
+	dir.phones[phone][usr.ID] = struct{}{}
 }
 
-func (u *user) Get(filters *models.Filters) []models.User {
+// remove deletes the user with the given ID from dir and its indexes.
+func (dir *directory) remove(id int) {
+	usr, ok := dir.users[id]
+	if !ok {
+		return
+	}
+
+	delete(dir.users, id)
+	dir.fnames.remove(id, usr.Fname)
+	dir.cities.remove(id, usr.City)
+
+	phone := normalize.Phone(usr.Phone)
+
+	delete(dir.phones[phone], id)
+
+	if len(dir.phones[phone]) == 0 {
+		delete(dir.phones, phone)
+	}
+}
+
+// addMerge keeps record among the merges of dir.
+func (dir *directory) addMerge(record store.MergeRecord) {
+	dir.merges = append(dir.merges, record)
+
+	for _, id := range []int{record.Survivor.ID, record.Merged.ID} {
+		dir.mergesByUser[id] = append(dir.mergesByUser[id], len(dir.merges)-1)
+	}
+}
+
+// candidates returns the IDs of the users of dir that may match filters, from the smallest index
+// of the fields they narrow on. It reports false when they narrow on no indexed field, and every
+// user may match.
+func (dir *directory) candidates(filters *models.Filters) (map[int]struct{}, bool) {
+	var (
+		ids     map[int]struct{}
+		indexed bool
+	)
+
+	for _, lookup := range []struct {
+		value *string
+		index *textIndex
+	}{{filters.Fname, dir.fnames}, {filters.City, dir.cities}} {
+		if lookup.value == nil {
+			continue
+		}
+
+		// Blank values are not indexed.
+		key := normalize.Key(*lookup.value)
+		if key == "" {
+			continue
+		}
+
+		if found := lookup.index.ids(key); !indexed || len(found) < len(ids) {
+			ids, indexed = found, true
+		}
+	}
+
+	return ids, indexed
+}
+
+func (dir *directory) textIndex(field store.SuggestField) *textIndex {
+	switch field {
+	case store.SuggestFname:
+		return dir.fnames
+	case store.SuggestCity:
+		return dir.cities
+	}
+
+	return nil
+}
+
+// directory returns the directory of the tenant in ctx, creating it when create is set. It must
+// be called with u.mu held.
+func (u *user) directory(ctx context.Context, create bool) *directory {
+	id := tenant.FromContext(ctx)
+
+	dir, ok := u.directories[id]
+	if !ok && create {
+		dir = &directory{
+			tenant:  id,
+			users:   make(map[int]models.User),
+			fnames:  newTextIndex(),
+			cities:  newTextIndex(),
+			phones:  make(map[string]map[int]struct{}),
+			changed: make(chan struct{}),
+
+			mergesByUser: make(map[int][]int),
+		}
+		u.directories[id] = dir
+
+		logging.FromContext(ctx).Debug("tenant directory created", "tenant", id)
+	}
+
+	return dir
+}
+
+func (u *user) Create(ctx context.Context, userReq *models.User) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dir := u.directory(ctx, true)
+
+	dir.lastInsertedID += 1
+
+	userReq.ID = dir.lastInsertedID
+
+	usr := normalized(*userReq)
+
+	dir.put(usr)
+	u.record(ctx, dir, store.Event{Type: store.EventCreated, User: usr})
+
+	return dir.lastInsertedID
+}
+
+func (u *user) Get(ctx context.Context, filters *models.Filters) []models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
 	users := make([]models.User, 0)
 
-	for _, usr := range u.users {
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return users
+	}
+
+	for _, usr := range dir.users {
 		if filters == nil || u.isMatch(&usr, filters) {
 			users = append(users, usr)
 		}
@@ -48,24 +284,37 @@ func (u *user) Get(filters *models.Filters) []models.User {
 	return users
 }
 
-func (u *user) GetByID(id int) (*models.User, error) {
-	if usr, ok := u.users[id]; ok {
-		return &usr, nil
+func (u *user) GetByID(ctx context.Context, id int) (*models.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if dir := u.directory(ctx, false); dir != nil {
+		if usr, ok := dir.users[id]; ok {
+			return &usr, nil
+		}
 	}
 
 	return nil, errors.UserNotFound{ID: id}
 }
 
-func (u *user) GetByIDs(ids []int) []models.User {
+func (u *user) GetByIDs(ctx context.Context, ids []int) []models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
 	users := make([]models.User, 0)
 
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return users
+	}
+
 	idsMap := make(map[int]bool)
 
 	for _, id := range ids {
 		idsMap[id] = true
 	}
 
-	for _, usr := range u.users {
+	for _, usr := range dir.users {
 		if _, ok := idsMap[usr.ID]; ok {
 			users = append(users, usr)
 		}
@@ -78,20 +327,481 @@ func (u *user) GetByIDs(ids []int) []models.User {
 	return users
 }
 
-func (u *user) Update(usr *models.User) {
-	u.users[usr.ID] = *usr
+func (u *user) GetByPhone(ctx context.Context, phone string) []models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]models.User, 0)
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return users
+	}
+
+	for id := range dir.phones[normalize.Phone(phone)] {
+		users = append(users, dir.users[id])
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users
+}
+
+func (u *user) Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]models.User, 0)
+
+	dir := u.directory(ctx, false)
+	if dir == nil || limit <= 0 {
+		return users
+	}
+
+	for id := afterID + 1; id <= dir.lastInsertedID && len(users) < limit; id++ {
+		usr, ok := dir.users[id]
+		if ok && (filters == nil || u.isMatch(&usr, filters)) {
+			users = append(users, usr)
+		}
+	}
+
+	return users
+}
+
+func (u *user) Count(ctx context.Context, filters *models.Filters) int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.count(ctx, filters, 0)
+}
+
+func (u *user) Exists(ctx context.Context, filters *models.Filters) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.count(ctx, filters, 1) > 0
+}
+
+// count returns the number of users matching filters, stopping at limit when it is positive. It
+// must be called with u.mu held.
+func (u *user) count(ctx context.Context, filters *models.Filters, limit int) int {
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return 0
+	}
+
+	if filters == nil || *filters == (models.Filters{}) {
+		return len(dir.users)
+	}
+
+	count := 0
+
+	// match counts usr when it matches, and reports whether limit is reached.
+	match := func(usr models.User) bool {
+		if u.isMatch(&usr, filters) {
+			count++
+		}
+
+		return limit > 0 && count == limit
+	}
+
+	ids, indexed := dir.candidates(filters)
+	if !indexed {
+		for _, usr := range dir.users {
+			if match(usr) {
+				break
+			}
+		}
+
+		return count
+	}
+
+	for id := range ids {
+		if match(dir.users[id]) {
+			break
+		}
+	}
+
+	return count
+}
+
+func (u *user) Update(ctx context.Context, usr *models.User) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return
+	}
+
+	if previous, ok := dir.users[usr.ID]; ok {
+		updated := normalized(*usr)
+
+		dir.put(updated)
+		u.record(ctx, dir, store.Event{Type: store.EventUpdated, User: updated, Previous: &previous})
+	}
+}
+
+func (u *user) Upsert(ctx context.Context, users []models.User) []store.UpsertResult {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dir := u.directory(ctx, true)
+
+	results := make([]store.UpsertResult, 0, len(users))
+
+	for _, usr := range users {
+		matches := dir.phones[normalize.Phone(usr.Phone)]
+
+		switch len(matches) {
+		case 0:
+			dir.lastInsertedID++
+
+			usr.ID = dir.lastInsertedID
+			usr = normalized(usr)
+
+			dir.put(usr)
+			u.record(ctx, dir, store.Event{Type: store.EventCreated, User: usr})
+
+			results = append(results, store.UpsertResult{User: usr, Created: true})
+
+		case 1:
+			for id := range matches {
+				usr.ID = id
+			}
+
+			previous := dir.users[usr.ID]
+			usr = normalized(usr)
+
+			dir.put(usr)
+			u.record(ctx, dir, store.Event{Type: store.EventUpdated, User: usr, Previous: &previous})
+
+			results = append(results, store.UpsertResult{User: usr})
+
+		default:
+			results = append(results, store.UpsertResult{Err: errors.AmbiguousPhone{Matches: len(matches)}})
+		}
+	}
+
+	return results
+}
+
+func (u *user) Delete(ctx context.Context, id int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return
+	}
+
+	if usr, ok := dir.users[id]; ok {
+		dir.remove(id)
+		u.record(ctx, dir, store.Event{Type: store.EventDeleted, User: usr})
+	}
 }
 
-func (u *user) Delete(id int) {
-	delete(u.users, id)
+func (u *user) Merge(ctx context.Context, req models.MergeRequest) (*models.User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if req.SurvivorID == req.MergedID {
+		return nil, errors.InvalidParams{Params: []string{"merged_id"}}
+	}
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return nil, errors.UserNotFound{ID: req.SurvivorID}
+	}
+
+	previous, ok := dir.users[req.SurvivorID]
+	if !ok {
+		return nil, errors.UserNotFound{ID: req.SurvivorID}
+	}
+
+	merged, ok := dir.users[req.MergedID]
+	if !ok {
+		return nil, errors.UserNotFound{ID: req.MergedID}
+	}
+
+	survivor := normalized(req.Merge(previous, merged))
+
+	dir.remove(req.MergedID)
+	dir.put(survivor)
+	u.record(ctx, dir, store.Event{Type: store.EventMerged, User: survivor, Previous: &previous, Merged: &merged})
+
+	dir.addMerge(store.MergeRecord{
+		Revision: dir.revision,
+		MergedAt: time.Now(),
+		Survivor: previous,
+		Merged:   merged,
+		Result:   survivor,
+	})
+
+	return &survivor, nil
+}
+
+func (u *user) Merges(ctx context.Context, id int) []store.MergeRecord {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	merges := make([]store.MergeRecord, 0)
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return merges
+	}
+
+	for _, i := range dir.mergesByUser[id] {
+		merges = append(merges, dir.merges[i])
+	}
+
+	return merges
+}
+
+func (u *user) Snapshot(ctx context.Context) store.Snapshot {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	snapshot := store.Snapshot{Users: make([]models.User, 0)}
+
+	dir := u.directory(ctx, false)
+	if dir == nil {
+		return snapshot
+	}
+
+	snapshot.LastInsertedID = dir.lastInsertedID
+	snapshot.Merges = slices.Clone(dir.merges)
+
+	for _, usr := range dir.users {
+		snapshot.Users = append(snapshot.Users, usr)
+	}
+
+	sort.Slice(snapshot.Users, func(i, j int) bool {
+		return snapshot.Users[i].ID < snapshot.Users[j].ID
+	})
+
+	return snapshot
+}
+
+func (u *user) Restore(ctx context.Context, snapshot store.Snapshot) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	dir := u.directory(ctx, true)
+
+	if len(dir.users) > 0 {
+		return errors.StoreNotEmpty{Users: len(dir.users)}
+	}
+
+	// IDs handed out before the restore, even to users deleted since, are never reused.
+	lastInsertedID := max(dir.lastInsertedID, snapshot.LastInsertedID)
+
+	for _, usr := range snapshot.Users {
+		usr = normalized(usr)
+
+		dir.put(usr)
+		lastInsertedID = max(lastInsertedID, usr.ID)
+	}
+
+	dir.lastInsertedID = lastInsertedID
+
+	for _, record := range snapshot.Merges {
+		dir.addMerge(record)
+	}
+
+	// A single change stands for the users loaded, so that a large restore does not flood the
+	// observers and the outbox, nor push every other change out of the history.
+	u.record(ctx, dir, store.Event{Type: store.EventRestored, Restored: len(snapshot.Users)})
+
+	return nil
+}
+
+func (u *user) Changes(ctx context.Context, filters *models.Filters, afterRevision int64) (store.Changes, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// The directory is created so that watchers of a tenant without users learn about its first
+	// change.
+	dir := u.directory(ctx, true)
+
+	changes := store.Changes{Events: make([]store.Event, 0), Revision: dir.revision, Next: dir.changed}
+
+	if afterRevision == store.CurrentRevision {
+		return changes, nil
+	}
+
+	if afterRevision < 0 || afterRevision > dir.revision {
+		return store.Changes{}, errors.InvalidParams{Params: []string{"after_revision"}}
+	}
+
+	oldest := dir.revision - int64(len(dir.history))
+	if afterRevision < oldest {
+		return store.Changes{}, errors.RevisionCompacted{Revision: afterRevision, Oldest: oldest}
+	}
+
+	for _, event := range dir.history[afterRevision-oldest:] {
+		if filters == nil || u.isEventMatch(&event, filters) {
+			changes.Events = append(changes.Events, event)
+		}
+	}
+
+	return changes, nil
+}
+
+// isEventMatch reports whether event changes a user matching filters before or after the change,
+// so that watchers learn about users leaving the filters as well as entering them. Merges are
+// changes to the merged user as well, and restores may change any user.
+func (u *user) isEventMatch(event *store.Event, filters *models.Filters) bool {
+	if event.Type == store.EventRestored {
+		return true
+	}
+
+	for _, usr := range []*models.User{&event.User, event.Previous, event.Merged} {
+		if usr != nil && u.isMatch(usr, filters) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *user) Suggest(ctx context.Context, query store.SuggestQuery) []store.Suggestion {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	suggestions := make([]store.Suggestion, 0)
+
+	dir := u.directory(ctx, false)
+	text := normalize.Key(query.Text)
+
+	if dir == nil || text == "" || query.Limit <= 0 {
+		return suggestions
+	}
+
+	fields := query.Fields
+	if len(fields) == 0 {
+		fields = []store.SuggestField{store.SuggestFname, store.SuggestCity}
+	}
+
+	// best holds the best suggestion of every matching user, across fields.
+	best := make(map[int]store.Suggestion)
+
+	for _, field := range fields {
+		index := dir.textIndex(field)
+		if index == nil {
+			continue
+		}
+
+		var scores map[string]float64
+
+		if query.Prefix {
+			scores = index.prefix(text)
+		} else {
+			scores = index.similar(text, minSimilarity)
+		}
+
+		for value, score := range scores {
+			for id := range index.entries[value].ids {
+				if suggestion, ok := best[id]; ok && suggestion.Score >= score {
+					continue
+				}
+
+				best[id] = store.Suggestion{User: dir.users[id], Score: score, Field: field}
+			}
+		}
+	}
+
+	for _, suggestion := range best {
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+
+		return suggestions[i].User.ID < suggestions[j].User.ID
+	})
+
+	if len(suggestions) > query.Limit {
+		suggestions = suggestions[:query.Limit]
+	}
+
+	return suggestions
+}
+
+func (u *user) Outbox(afterID int64, limit int) ([]store.OutboxMessage, <-chan struct{}) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	messages := u.outbox.messages[sort.Search(len(u.outbox.messages), func(i int) bool {
+		return u.outbox.messages[i].ID > afterID
+	}):]
+
+	if len(messages) > limit {
+		messages = messages[:max(limit, 0)]
+	}
+
+	return append([]store.OutboxMessage{}, messages...), u.outbox.written
+}
+
+func (u *user) AckOutbox(id int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	acked := sort.Search(len(u.outbox.messages), func(i int) bool {
+		return u.outbox.messages[i].ID > id
+	})
+
+	// The acknowledged messages are released when appending next reallocates the slice.
+	u.outbox.messages = u.outbox.messages[acked:]
+}
+
+func (u *user) Usage() store.Usage {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	usage := store.Usage{
+		UsersByTenant: make(map[string]int),
+		IndexSizes:    map[string]int{"tenant": len(u.directories)},
+	}
+
+	for id, dir := range u.directories {
+		usage.UsersByTenant[id] = len(dir.users)
+		usage.IndexSizes["id"] += len(dir.users)
+		usage.IndexSizes["fname"] += dir.fnames.len()
+		usage.IndexSizes["city"] += dir.cities.len()
+		usage.IndexSizes["phone"] += len(dir.phones)
+	}
+
+	if u.outbox.enabled {
+		usage.IndexSizes["outbox"] = len(u.outbox.messages)
+	}
+
+	return usage
+}
+
+// normalized returns usr with its first name and city in canonical form, as they are stored.
+func normalized(usr models.User) models.User {
+	usr.Fname = normalize.Text(usr.Fname)
+	usr.City = normalize.Text(usr.City)
+
+	return usr
 }
 
 func (u *user) isMatch(usr *models.User, filters *models.Filters) bool {
-	if filters.Fname != nil && !strings.EqualFold(usr.Fname, *filters.Fname) {
+	if filters.Fname != nil && !normalize.Equal(usr.Fname, *filters.Fname) {
+		return false
+	}
+
+	if filters.City != nil && !normalize.Equal(usr.City, *filters.City) {
 		return false
 	}
 
-	if filters.City != nil && !strings.EqualFold(usr.City, *filters.City) {
+	if filters.Phone != nil && normalize.Phone(usr.Phone) != normalize.Phone(*filters.Phone) {
 		return false
 	}
 
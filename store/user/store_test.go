@@ -1,17 +1,23 @@
 package user
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ssshekhu53/user-detail-management/errors"
 	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
 	"github.com/ssshekhu53/user-detail-management/utils"
 )
 
 func Test_Create(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 
 	userReq := &models.User{
 		Fname:   "John",
@@ -21,36 +27,37 @@ func Test_Create(t *testing.T) {
 		Married: false,
 	}
 
-	id := u.Create(userReq)
+	id := u.Create(ctx, userReq)
 
 	assert.Equal(t, int(1), id)
-	assert.Equal(t, u.users[id].Fname, userReq.Fname)
-	assert.Equal(t, u.users[id].City, userReq.City)
-	assert.Equal(t, u.users[id].Phone, userReq.Phone)
-	assert.Equal(t, u.users[id].Height, userReq.Height)
-	assert.Equal(t, u.users[id].Married, userReq.Married)
+	assert.Equal(t, u.directories[tenant.Default].users[id].Fname, userReq.Fname)
+	assert.Equal(t, u.directories[tenant.Default].users[id].City, userReq.City)
+	assert.Equal(t, u.directories[tenant.Default].users[id].Phone, userReq.Phone)
+	assert.Equal(t, u.directories[tenant.Default].users[id].Height, userReq.Height)
+	assert.Equal(t, u.directories[tenant.Default].users[id].Married, userReq.Married)
 }
 
 func Test_Get(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 	userReq1 := &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
 	userReq2 := &models.User{Fname: "Jane", City: "San Francisco", Phone: "0987654321", Height: 5.5, Married: true}
 
-	u.Create(userReq1)
-	u.Create(userReq2)
+	u.Create(ctx, userReq1)
+	u.Create(ctx, userReq2)
 
 	tests := []struct {
 		name    string
 		filters *models.Filters
 		want    []models.User
 	}{
-		{"Get all users", nil, []models.User{u.users[1], u.users[2]}},
-		{"Filter by City", &models.Filters{City: utils.StrPtr("New York")}, []models.User{u.users[1]}},
+		{"Get all users", nil, []models.User{u.directories[tenant.Default].users[1], u.directories[tenant.Default].users[2]}},
+		{"Filter by City", &models.Filters{City: utils.StrPtr("New York")}, []models.User{u.directories[tenant.Default].users[1]}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			users := u.Get(tt.filters)
+			users := u.Get(ctx, tt.filters)
 
 			assert.ElementsMatch(t, tt.want, users)
 		})
@@ -59,14 +66,15 @@ func Test_Get(t *testing.T) {
 
 func Test_GetByID(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 	userReq := &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
-	id := u.Create(userReq)
+	id := u.Create(ctx, userReq)
 
-	usr, err := u.GetByID(id)
+	usr, err := u.GetByID(ctx, id)
 	assert.NoError(t, err)
-	assert.Equal(t, u.users[id], *usr)
+	assert.Equal(t, u.directories[tenant.Default].users[id], *usr)
 
-	_, err = u.GetByID(999)
+	_, err = u.GetByID(ctx, 999)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.UserNotFound{}, err)
@@ -74,58 +82,338 @@ func Test_GetByID(t *testing.T) {
 
 func Test_GetByIDs(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 	userReq1 := &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
 	userReq2 := &models.User{Fname: "Jane", City: "San Francisco", Phone: "0987654321", Height: 5.5, Married: true}
 
-	u.Create(userReq1)
-	u.Create(userReq2)
+	u.Create(ctx, userReq1)
+	u.Create(ctx, userReq2)
 
 	tests := []struct {
 		name string
 		ids  []int
 		want []models.User
 	}{
-		{"Users exists", []int{1, 2}, []models.User{u.users[1], u.users[2]}},
+		{"Users exists", []int{1, 2}, []models.User{u.directories[tenant.Default].users[1], u.directories[tenant.Default].users[2]}},
 		{"No users exists", []int{3, 4}, []models.User{}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			users := u.GetByIDs(tt.ids)
+			users := u.GetByIDs(ctx, tt.ids)
 
 			assert.ElementsMatch(t, tt.want, users)
 		})
 	}
 }
 
+func Test_GetByPhone(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	u.Create(ctx, &models.User{Fname: "John", Phone: "1234567890"})
+	u.Create(ctx, &models.User{Fname: "Jane", Phone: "0987654321"})
+	u.Create(ctx, &models.User{Fname: "Jim", Phone: "1234567890"})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		phone   string
+		wantIDs []int
+	}{
+		{"Several users", ctx, "1234567890", []int{1, 3}},
+		{"Single user", ctx, "0987654321", []int{2}},
+		{"Formatted phone", ctx, "+1 (098) 765-4321", []int{2}},
+		{"No user", ctx, "5555555555", []int{}},
+		{"Other tenant", tenant.NewContext(ctx, "acme"), "1234567890", []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []int{}
+
+			for _, usr := range u.GetByPhone(tt.ctx, tt.phone) {
+				ids = append(ids, usr.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	for _, city := range []string{"Boston", "Denver", "Boston", "Boston", "Denver"} {
+		u.Create(ctx, &models.User{Fname: "John", City: city})
+	}
+
+	u.Delete(ctx, 3)
+
+	boston := "boston"
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		filters *models.Filters
+		afterID int
+		limit   int
+		wantIDs []int
+	}{
+		{"First page", ctx, nil, 0, 2, []int{1, 2}},
+		{"Skips deleted users", ctx, nil, 2, 2, []int{4, 5}},
+		{"Last page", ctx, nil, 4, 2, []int{5}},
+		{"Past the end", ctx, nil, 5, 2, []int{}},
+		{"Filters", ctx, &models.Filters{City: &boston}, 0, 10, []int{1, 4}},
+		{"Zero limit", ctx, nil, 0, 0, []int{}},
+		{"Other tenant", tenant.NewContext(ctx, "acme"), nil, 0, 10, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []int{}
+
+			for _, usr := range u.Scan(tt.ctx, tt.filters, tt.afterID, tt.limit) {
+				ids = append(ids, usr.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func Test_CountExists(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	u.Create(ctx, &models.User{Fname: "John", City: "Boston", Phone: "0000000000", Height: 5.9})
+	u.Create(ctx, &models.User{Fname: "Jane", City: "Boston", Phone: "0000000001", Height: 5.5})
+	u.Create(ctx, &models.User{Fname: "John", City: "Denver", Phone: "0000000002", Height: 5.9})
+	u.Create(ctx, &models.User{Fname: "José", City: "Zürich", Phone: "0000000003", Height: 6.1})
+	u.Delete(ctx, 2)
+
+	blank := ""
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		filters *models.Filters
+		want    int
+	}{
+		{"Every user", ctx, nil, 3},
+		{"No filter", ctx, &models.Filters{}, 3},
+		{"Indexed field", ctx, &models.Filters{Fname: utils.StrPtr("john")}, 2},
+		{"Indexed fields", ctx, &models.Filters{Fname: utils.StrPtr("John"), City: utils.StrPtr("Denver")}, 1},
+		{"Indexed and scanned fields", ctx, &models.Filters{City: utils.StrPtr("Boston"), Height: utils.Float64Ptr(5.5)}, 0},
+		{"Accents ignored", ctx, &models.Filters{Fname: utils.StrPtr("jose"), City: utils.StrPtr("ZURICH")}, 1},
+		{"Scanned field", ctx, &models.Filters{Height: utils.Float64Ptr(5.9)}, 2},
+		{"Phone", ctx, &models.Filters{Phone: utils.StrPtr("0000000002")}, 1},
+		{"Phone formatted", ctx, &models.Filters{Phone: utils.StrPtr("+1 (000) 000-0003")}, 1},
+		{"Unknown phone", ctx, &models.Filters{Phone: utils.StrPtr("1234567890")}, 0},
+		{"Indexed field and phone", ctx, &models.Filters{Fname: utils.StrPtr("John"), Phone: utils.StrPtr("0000000003")}, 0},
+		{"Unknown value", ctx, &models.Filters{City: utils.StrPtr("Paris")}, 0},
+		{"Blank value", ctx, &models.Filters{Fname: &blank}, 0},
+		{"Other tenant", tenant.NewContext(ctx, "acme"), nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, u.Count(tt.ctx, tt.filters))
+			assert.Equal(t, len(u.Get(tt.ctx, tt.filters)), u.Count(tt.ctx, tt.filters), "Count agrees with Get")
+			assert.Equal(t, tt.want > 0, u.Exists(tt.ctx, tt.filters))
+		})
+	}
+}
+
+func Test_CountSkipsNonMatchingUsers(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	// Only one of many users matches, so Count walks past non-matching users before reaching it.
+	for i := 0; i < 50; i++ {
+		u.Create(ctx, &models.User{Fname: "John", City: "Boston", Phone: fmt.Sprintf("%010d", i), Height: 170})
+	}
+
+	u.Create(ctx, &models.User{Fname: "John", City: "Boston", Phone: "1000000000", Height: 180})
+
+	for _, filters := range []*models.Filters{
+		{Height: utils.Float64Ptr(180)},
+		{City: utils.StrPtr("Boston"), Height: utils.Float64Ptr(180)},
+	} {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, 1, u.Count(ctx, filters))
+			assert.True(t, u.Exists(ctx, filters))
+		}
+	}
+}
+
 func Test_Update(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 	usrCreateReq := &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
-	id := u.Create(usrCreateReq)
+	id := u.Create(ctx, usrCreateReq)
 
 	usrUpdateReq := &models.User{ID: id, Fname: "Johnny", City: "Los Angeles", Phone: "0987654321", Height: 6.0, Married: true}
 
 	updatedUser := &models.User{ID: id, Fname: "Johnny", City: "Los Angeles", Phone: "0987654321", Height: 6.0, Married: true}
-	u.Update(usrUpdateReq)
+	u.Update(ctx, usrUpdateReq)
 
-	usr := u.users[id]
+	usr := u.directories[tenant.Default].users[id]
 
 	assert.Equal(t, *updatedUser, usr)
 }
 
+func Test_Upsert(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	u.Create(ctx, &models.User{Fname: "John", Phone: "1234567890"})
+	u.Create(ctx, &models.User{Fname: "Jane", Phone: "5555555555"})
+	u.Create(ctx, &models.User{Fname: "Janet", Phone: "555-555-5555"})
+
+	results := u.Upsert(ctx, []models.User{
+		{Fname: "johnny", Phone: "+1 (123) 456-7890"},
+		{Fname: "Jim", Phone: "0987654321"},
+		{Fname: "Jimmy", Phone: "098 765 4321"},
+		{Fname: "Jill", Phone: "5555555555"},
+	})
+
+	assert.Equal(t, []store.UpsertResult{
+		{User: models.User{ID: 1, Fname: "johnny", Phone: "+1 (123) 456-7890"}},
+		{User: models.User{ID: 4, Fname: "Jim", Phone: "0987654321"}, Created: true},
+		{User: models.User{ID: 4, Fname: "Jimmy", Phone: "098 765 4321"}},
+		{Err: errors.AmbiguousPhone{Matches: 2}},
+	}, results, "phones are compared as normalized, and a user updates the one created earlier in the batch")
+
+	changes, err := u.Changes(ctx, nil, 0)
+	assert.NoError(t, err)
+
+	assert.Len(t, changes.Events, 6, "the ambiguous user changes nothing")
+	assert.Len(t, u.GetByPhone(ctx, "5555555555"), 2)
+}
+
 func Test_Delete(t *testing.T) {
 	u := New().(*user)
+	ctx := context.Background()
 	userReq := &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
-	id := u.Create(userReq)
+	id := u.Create(ctx, userReq)
 
-	u.Delete(id)
+	u.Delete(ctx, id)
 
-	usr, ok := u.users[id]
+	usr, ok := u.directories[tenant.Default].users[id]
 
 	assert.Empty(t, usr)
 	assert.False(t, ok)
 }
 
+func Test_Merge(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	u.Create(ctx, &models.User{Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9})
+	u.Create(ctx, &models.User{Fname: "Jon", City: "Denver", Phone: "5550100001", Height: 5.8, Married: true})
+	u.Create(ctx, &models.User{Fname: "Jane", City: "Denver", Phone: "5550100002", Height: 5.5})
+
+	req := models.MergeRequest{SurvivorID: 1, MergedID: 2, City: models.MergeFromMerged, Married: models.MergeFromMerged}
+	want := models.User{ID: 1, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9, Married: true}
+
+	got, err := u.Merge(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, &want, got)
+	assert.Equal(t, want, u.directories[tenant.Default].users[1])
+	assert.NotContains(t, u.directories[tenant.Default].users, 2)
+	assert.Empty(t, u.directories[tenant.Default].fnames.ids("jon"), "the merged user is no longer indexed")
+	assert.Equal(t, 2, u.Count(ctx, &models.Filters{City: utils.StrPtr("denver")}))
+
+	changes, err := u.Changes(ctx, &models.Filters{Fname: utils.StrPtr("jon")}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []store.Event{{
+		Revision: 4,
+		Type:     store.EventMerged,
+		User:     models.User{ID: 1, Fname: "John", City: "Denver", Phone: "5550100000", Height: 5.9, Married: true},
+		Previous: &models.User{ID: 1, Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9},
+		Merged:   &models.User{ID: 2, Fname: "Jon", City: "Denver", Phone: "5550100001", Height: 5.8, Married: true},
+	}}, changes.Events, "merges are changes to the merged user")
+
+	merges := u.Merges(ctx, 2)
+	if assert.Len(t, merges, 1) {
+		assert.Equal(t, int64(4), merges[0].Revision)
+		assert.False(t, merges[0].MergedAt.IsZero())
+		assert.Equal(t, models.User{ID: 1, Fname: "John", City: "Boston", Phone: "5550100000", Height: 5.9}, merges[0].Survivor)
+		assert.Equal(t, models.User{ID: 2, Fname: "Jon", City: "Denver", Phone: "5550100001", Height: 5.8, Married: true}, merges[0].Merged)
+		assert.Equal(t, want, merges[0].Result)
+	}
+
+	assert.Equal(t, merges, u.Merges(ctx, 1), "merges are listed for the survivor as well")
+	assert.Empty(t, u.Merges(ctx, 3))
+	assert.Empty(t, u.Merges(tenant.NewContext(ctx, "acme"), 1))
+
+	for _, tt := range []struct {
+		ctx context.Context
+		req models.MergeRequest
+		err error
+	}{
+		{ctx, models.MergeRequest{SurvivorID: 1, MergedID: 2}, errors.UserNotFound{ID: 2}},
+		{ctx, models.MergeRequest{SurvivorID: 7, MergedID: 3}, errors.UserNotFound{ID: 7}},
+		{ctx, models.MergeRequest{SurvivorID: 1, MergedID: 1}, errors.InvalidParams{Params: []string{"merged_id"}}},
+		{tenant.NewContext(ctx, "acme"), models.MergeRequest{SurvivorID: 1, MergedID: 3}, errors.UserNotFound{ID: 1}},
+	} {
+		got, err := u.Merge(tt.ctx, tt.req)
+		assert.Nil(t, got)
+		assert.Equal(t, tt.err, err)
+	}
+
+	assert.Equal(t, int64(4), u.directories[tenant.Default].revision, "failed merges are not recorded")
+	assert.Len(t, u.Merges(ctx, 1), 1, "failed merges are not kept")
+}
+
+func Test_SnapshotRestore(t *testing.T) {
+	u := New().(*user)
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	u.Create(acme, &models.User{Fname: "John"})
+	u.Create(acme, &models.User{Fname: "Jane"})
+	u.Create(acme, &models.User{Fname: "Jim"})
+	_, err := u.Merge(acme, models.MergeRequest{SurvivorID: 1, MergedID: 3})
+	require.NoError(t, err)
+
+	snapshot := u.Snapshot(acme)
+
+	assert.Equal(t, store.Snapshot{
+		LastInsertedID: 3,
+		Users:          []models.User{{ID: 1, Fname: "John"}, {ID: 2, Fname: "Jane"}},
+		Merges:         u.Merges(acme, 3),
+	}, snapshot)
+	assert.Len(t, snapshot.Merges, 1)
+
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	assert.Equal(t, store.Snapshot{Users: []models.User{}}, u.Snapshot(globex))
+	assert.NoError(t, u.Restore(globex, snapshot))
+	assert.Equal(t, snapshot, u.Snapshot(globex))
+	assert.Equal(t, snapshot.Merges, u.Merges(globex, 1), "merges are restored")
+
+	// The ID of the merged user is not reused.
+	assert.Equal(t, 4, u.Create(globex, &models.User{Fname: "Jack"}))
+
+	assert.Equal(t, errors.StoreNotEmpty{Users: 2}, u.Restore(acme, snapshot))
+}
+
+func Test_RestoreKeepsLastInsertedID(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		u.Delete(ctx, u.Create(ctx, &models.User{Fname: "John"}))
+	}
+
+	assert.NoError(t, u.Restore(ctx, store.Snapshot{LastInsertedID: 2, Users: []models.User{{ID: 2, Fname: "Jane"}}}))
+
+	// IDs handed out before the restore are not reused either.
+	assert.Equal(t, 6, u.Create(ctx, &models.User{Fname: "Jim"}))
+}
+
 func Test_isMatch(t *testing.T) {
 	u := New().(*user)
 	usr := &models.User{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9, Married: false}
@@ -151,3 +439,391 @@ func Test_isMatch(t *testing.T) {
 		})
 	}
 }
+
+func Test_TenantIsolation(t *testing.T) {
+	u := New().(*user)
+	acme := tenant.NewContext(context.Background(), "acme")
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	acmeID := u.Create(acme, &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9})
+	globexID := u.Create(globex, &models.User{Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9})
+	secondGlobexID := u.Create(globex, &models.User{Fname: "Jane", City: "Boston", Phone: "0987654321", Height: 5.5})
+
+	t.Run("Separate ID sequences", func(t *testing.T) {
+		assert.Equal(t, 1, acmeID)
+		assert.Equal(t, 1, globexID)
+		assert.Equal(t, 2, secondGlobexID)
+	})
+
+	t.Run("Get only returns the tenant's users", func(t *testing.T) {
+		assert.Equal(t, []models.User{{ID: 1, Fname: "John", City: "New York", Phone: "1234567890", Height: 5.9}}, u.Get(acme, nil))
+		assert.Len(t, u.Get(globex, nil), 2)
+		assert.Empty(t, u.Get(context.Background(), nil))
+	})
+
+	t.Run("Search does not leak across tenants", func(t *testing.T) {
+		assert.Empty(t, u.Get(acme, &models.Filters{Fname: utils.StrPtr("Jane")}))
+	})
+
+	t.Run("GetByID and GetByIDs are scoped", func(t *testing.T) {
+		_, err := u.GetByID(acme, secondGlobexID)
+		assert.Equal(t, errors.UserNotFound{ID: secondGlobexID}, err)
+
+		assert.Len(t, u.GetByIDs(acme, []int{1, 2}), 1)
+	})
+
+	t.Run("Update and Delete are scoped", func(t *testing.T) {
+		u.Update(acme, &models.User{ID: secondGlobexID, Fname: "Mallory"})
+		u.Delete(acme, globexID)
+
+		_, err := u.GetByID(acme, secondGlobexID)
+		assert.Error(t, err)
+
+		usr, err := u.GetByID(globex, secondGlobexID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane", usr.Fname)
+
+		_, err = u.GetByID(globex, globexID)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_Usage(t *testing.T) {
+	u := New().(*user)
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	assert.Equal(t, store.Usage{UsersByTenant: map[string]int{}, IndexSizes: map[string]int{"tenant": 0}}, u.Usage())
+
+	u.Create(acme, &models.User{Fname: "John", City: "Boston"})
+	u.Create(acme, &models.User{Fname: "Jane", City: "boston"})
+	u.Create(context.Background(), &models.User{Fname: "Jim", City: "Boston"})
+
+	assert.Equal(t, store.Usage{
+		UsersByTenant: map[string]int{"acme": 2, tenant.Default: 1},
+		IndexSizes:    map[string]int{"tenant": 2, "id": 3, "fname": 3, "city": 2, "phone": 2},
+	}, u.Usage(), "cities are indexed once per tenant, ignoring case")
+}
+
+func Test_Changes(t *testing.T) {
+	u := New().(*user)
+	ctx := context.Background()
+
+	start, err := u.Changes(ctx, nil, store.CurrentRevision)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), start.Revision)
+	assert.Empty(t, start.Events)
+
+	u.Create(ctx, &models.User{Fname: "John", City: "Boston"})
+
+	select {
+	case <-start.Next:
+	default:
+		t.Fatal("watchers not woken up by a change")
+	}
+
+	u.Create(ctx, &models.User{Fname: "Jane", City: "Denver"})
+	u.Update(ctx, &models.User{ID: 1, Fname: "John", City: "Denver"})
+	u.Update(ctx, &models.User{ID: 7, Fname: "Jim"})
+	u.Delete(ctx, 2)
+	u.Delete(ctx, 2)
+
+	changes, err := u.Changes(ctx, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), changes.Revision)
+	assert.Equal(t, []store.Event{
+		{Revision: 2, Type: store.EventCreated, User: models.User{ID: 2, Fname: "Jane", City: "Denver"}},
+		{
+			Revision: 3, Type: store.EventUpdated, User: models.User{ID: 1, Fname: "John", City: "Denver"},
+			Previous: &models.User{ID: 1, Fname: "John", City: "Boston"},
+		},
+		{Revision: 4, Type: store.EventDeleted, User: models.User{ID: 2, Fname: "Jane", City: "Denver"}},
+	}, changes.Events)
+
+	changes, err = u.Changes(ctx, &models.Filters{Fname: utils.StrPtr("john")}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), changes.Revision)
+	assert.Equal(t, []int64{3}, revisions(changes.Events))
+
+	changes, err = u.Changes(ctx, &models.Filters{City: utils.StrPtr("boston")}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, revisions(changes.Events), "users leaving the filters are changes to them")
+
+	changes, err = u.Changes(ctx, nil, 4)
+	assert.NoError(t, err)
+	assert.Empty(t, changes.Events)
+
+	_, err = u.Changes(ctx, nil, 5)
+	assert.Equal(t, errors.InvalidParams{Params: []string{"after_revision"}}, err)
+
+	_, err = u.Changes(ctx, nil, -2)
+	assert.Equal(t, errors.InvalidParams{Params: []string{"after_revision"}}, err)
+
+	changes, err = u.Changes(tenant.NewContext(ctx, "acme"), nil, store.CurrentRevision)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), changes.Revision, "revisions are per tenant")
+
+	changes, err = u.Changes(ctx, nil, store.CurrentRevision)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), changes.Revision)
+	assert.Empty(t, changes.Events)
+}
+
+func revisions(events []store.Event) []int64 {
+	revisions := make([]int64, 0, len(events))
+
+	for _, event := range events {
+		revisions = append(revisions, event.Revision)
+	}
+
+	return revisions
+}
+
+func Test_ChangesCompacted(t *testing.T) {
+	u := New().(*user)
+	u.historySize = 2
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		u.Create(ctx, &models.User{Fname: "John"})
+	}
+
+	changes, err := u.Changes(ctx, nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, changes.Events, 2)
+
+	_, err = u.Changes(ctx, nil, 0)
+	assert.Equal(t, errors.RevisionCompacted{Revision: 0, Oldest: 1}, err)
+}
+
+func Test_RestoreRecordsSingleChange(t *testing.T) {
+	u := New(WithOutbox()).(*user)
+	u.historySize = 2
+	ctx := context.Background()
+
+	u.Delete(ctx, u.Create(ctx, &models.User{Fname: "Jim"}))
+
+	users := make([]models.User, 0, 5)
+	for id := 2; id <= 6; id++ {
+		users = append(users, models.User{ID: id, Fname: "John"})
+	}
+
+	assert.NoError(t, u.Restore(ctx, store.Snapshot{LastInsertedID: 6, Users: users}))
+
+	restored := store.Event{Revision: 3, Type: store.EventRestored, Restored: 5}
+
+	changes, err := u.Changes(ctx, nil, 1)
+	assert.NoError(t, err, "restores do not push the earlier changes out of the history")
+	assert.Equal(t, []store.Event{restored}, changes.Events[1:])
+
+	changes, err = u.Changes(ctx, &models.Filters{Fname: utils.StrPtr("Jane")}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []store.Event{restored}, changes.Events, "restores are sent to every watcher")
+
+	messages, _ := u.Outbox(0, 10)
+	assert.Len(t, messages, 3)
+}
+
+func Test_Outbox(t *testing.T) {
+	u := New(WithOutbox()).(*user)
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	messages, written := u.Outbox(0, 10)
+	assert.Empty(t, messages)
+
+	u.Create(acme, &models.User{Fname: "John"})
+
+	select {
+	case <-written:
+	default:
+		t.Fatal("dispatcher not woken up by a change")
+	}
+
+	u.Create(context.Background(), &models.User{Fname: "Jane"})
+	u.Update(acme, &models.User{ID: 1, Fname: "Jim"})
+	u.Delete(acme, 1)
+
+	messages, _ = u.Outbox(0, 10)
+	assert.Len(t, messages, 4)
+
+	for i, message := range messages {
+		assert.Equal(t, int64(i+1), message.ID)
+		assert.False(t, message.CreatedAt.IsZero())
+	}
+
+	assert.Equal(t, "acme", messages[0].Tenant)
+	assert.Equal(t, store.Event{Revision: 1, Type: store.EventCreated, User: models.User{ID: 1, Fname: "John"}}, messages[0].Event)
+	assert.Equal(t, tenant.Default, messages[1].Tenant)
+	assert.Equal(t, store.Event{Revision: 3, Type: store.EventDeleted, User: models.User{ID: 1, Fname: "Jim"}}, messages[3].Event)
+
+	messages, _ = u.Outbox(1, 2)
+	assert.Equal(t, []int64{2, 3}, outboxIDs(messages))
+
+	u.AckOutbox(2)
+
+	messages, _ = u.Outbox(0, 10)
+	assert.Equal(t, []int64{3, 4}, outboxIDs(messages))
+	assert.Equal(t, 2, u.Usage().IndexSizes["outbox"])
+
+	u.AckOutbox(4)
+
+	messages, _ = u.Outbox(0, 10)
+	assert.Empty(t, messages)
+
+	disabled := New()
+	disabled.Create(acme, &models.User{Fname: "John"})
+
+	messages, _ = disabled.Outbox(0, 10)
+	assert.Empty(t, messages)
+	assert.NotContains(t, disabled.Usage().IndexSizes, "outbox")
+}
+
+func outboxIDs(messages []store.OutboxMessage) []int64 {
+	ids := make([]int64, 0, len(messages))
+
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	return ids
+}
+
+func Test_Suggest(t *testing.T) {
+	u := New()
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	john := models.User{Fname: "John", City: "Boston"}
+	johnny := models.User{Fname: "Johnny", City: "Denver"}
+	jane := models.User{Fname: "Jane", City: "Johnstown"}
+	bob := models.User{Fname: "Bob", City: "Boston"}
+
+	for _, usr := range []*models.User{&john, &johnny, &jane, &bob} {
+		u.Create(acme, usr)
+	}
+
+	u.Create(context.Background(), &models.User{Fname: "John", City: "Boston"})
+
+	ids := func(suggestions []store.Suggestion) []int {
+		ids := make([]int, 0, len(suggestions))
+
+		for _, suggestion := range suggestions {
+			ids = append(ids, suggestion.User.ID)
+		}
+
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		query store.SuggestQuery
+		want  []store.Suggestion
+	}{
+		{
+			name:  "Misspelled first name",
+			query: store.SuggestQuery{Text: "Jon", Fields: []store.SuggestField{store.SuggestFname}, Limit: 10},
+			want:  []store.Suggestion{{User: john, Score: 6.0 / 9, Field: store.SuggestFname}},
+		},
+		{
+			name:  "Exact match first",
+			query: store.SuggestQuery{Text: "JOHN ", Fields: []store.SuggestField{store.SuggestFname}, Limit: 10},
+			want: []store.Suggestion{
+				{User: john, Score: 1, Field: store.SuggestFname},
+				{User: johnny, Score: 8.0 / 12, Field: store.SuggestFname},
+			},
+		},
+		{
+			name:  "Prefix",
+			query: store.SuggestQuery{Text: "jo", Prefix: true, Limit: 10},
+			want: []store.Suggestion{
+				{User: john, Score: 0.5, Field: store.SuggestFname},
+				{User: johnny, Score: 2.0 / 6, Field: store.SuggestFname},
+				{User: jane, Score: 2.0 / 9, Field: store.SuggestCity},
+			},
+		},
+		{
+			name:  "Best field wins",
+			query: store.SuggestQuery{Text: "bos", Prefix: true, Limit: 10},
+			want: []store.Suggestion{
+				{User: john, Score: 0.5, Field: store.SuggestCity},
+				{User: bob, Score: 0.5, Field: store.SuggestCity},
+			},
+		},
+		{
+			name:  "Limit",
+			query: store.SuggestQuery{Text: "jo", Prefix: true, Limit: 2},
+			want: []store.Suggestion{
+				{User: john, Score: 0.5, Field: store.SuggestFname},
+				{User: johnny, Score: 2.0 / 6, Field: store.SuggestFname},
+			},
+		},
+		{"No match", store.SuggestQuery{Text: "xyz", Limit: 10}, []store.Suggestion{}},
+		{"Blank text", store.SuggestQuery{Text: " ", Limit: 10}, []store.Suggestion{}},
+		{"No limit", store.SuggestQuery{Text: "john"}, []store.Suggestion{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, u.Suggest(acme, tt.query))
+		})
+	}
+
+	t.Run("Follows changes", func(t *testing.T) {
+		query := store.SuggestQuery{Text: "jo", Prefix: true, Fields: []store.SuggestField{store.SuggestFname}, Limit: 10}
+
+		u.Update(acme, &models.User{ID: john.ID, Fname: "Jack", City: "Boston"})
+		u.Delete(acme, johnny.ID)
+
+		assert.Empty(t, u.Suggest(acme, query))
+
+		u.Update(acme, &models.User{ID: jane.ID, Fname: "Joanna", City: "Johnstown"})
+
+		assert.Equal(t, []int{jane.ID}, ids(u.Suggest(acme, query)))
+	})
+
+	t.Run("Scoped to the tenant", func(t *testing.T) {
+		assert.Equal(t, []int{1}, ids(u.Suggest(context.Background(), store.SuggestQuery{Text: "john", Limit: 10})))
+		assert.Empty(t, u.Suggest(tenant.NewContext(context.Background(), "globex"), store.SuggestQuery{Text: "john", Limit: 10}))
+	})
+
+	t.Run("Restored users are indexed", func(t *testing.T) {
+		globex := tenant.NewContext(context.Background(), "globex")
+
+		assert.NoError(t, u.Restore(globex, store.Snapshot{LastInsertedID: 1, Users: []models.User{{ID: 1, Fname: "Joe"}}}))
+		assert.Equal(t, []int{1}, ids(u.Suggest(globex, store.SuggestQuery{Text: "jo", Prefix: true, Limit: 10})))
+	})
+}
+
+func Test_Normalization(t *testing.T) {
+	u := New()
+	ctx := context.Background()
+
+	id := u.Create(ctx, &models.User{Fname: "Jose\u0301", City: "  São\u00a0 Paulo "})
+
+	usr, err := u.GetByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jos\u00e9", usr.Fname, "names are stored in NFC")
+	assert.Equal(t, "São Paulo", usr.City, "whitespace is trimmed and collapsed")
+
+	for _, fname := range []string{"José", "Jose\u0301", "JOSE", "jose"} {
+		assert.Len(t, u.Get(ctx, &models.Filters{Fname: utils.StrPtr(fname)}), 1, "search matches %q", fname)
+	}
+
+	assert.Len(t, u.Get(ctx, &models.Filters{City: utils.StrPtr("sao paulo")}), 1)
+	assert.Empty(t, u.Get(ctx, &models.Filters{City: utils.StrPtr("saopaulo")}))
+
+	u.Update(ctx, &models.User{ID: id, Fname: "Zoe\u0308", City: "São Paulo"})
+
+	usr, _ = u.GetByID(ctx, id)
+	assert.Equal(t, "Zo\u00eb", usr.Fname)
+
+	suggestions := u.Suggest(ctx, store.SuggestQuery{Text: "ZOE", Limit: 10})
+	if assert.Len(t, suggestions, 1) {
+		assert.Equal(t, 1.0, suggestions[0].Score, "suggestions ignore accents")
+	}
+
+	assert.NoError(t, u.Restore(tenant.NewContext(ctx, "acme"), store.Snapshot{
+		LastInsertedID: 1, Users: []models.User{{ID: 1, Fname: "Rene\u0301e", City: "Paris"}},
+	}))
+
+	usr, _ = u.GetByID(tenant.NewContext(ctx, "acme"), 1)
+	assert.Equal(t, "Ren\u00e9e", usr.Fname, "restored users are normalized")
+}
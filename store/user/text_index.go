@@ -0,0 +1,156 @@
+package user
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ssshekhu53/user-detail-management/normalize"
+)
+
+// textIndex indexes the distinct values of a text field of the users of a directory, for prefix
+// and fuzzy lookups. Values are indexed by their normalize.Key, so lookups ignore case and accents.
+type textIndex struct {
+	entries map[string]*textEntry
+	// sorted holds the values in order, for prefix lookups.
+	sorted []string
+	// grams holds the values containing every bigram, for fuzzy lookups.
+	grams map[string]map[string]struct{}
+}
+
+type textEntry struct {
+	// ids holds the IDs of the users holding the value.
+	ids map[int]struct{}
+	// grams is the number of distinct bigrams of the value.
+	grams int
+}
+
+func newTextIndex() *textIndex {
+	return &textIndex{entries: make(map[string]*textEntry), grams: make(map[string]map[string]struct{})}
+}
+
+// bigrams returns the distinct pairs of consecutive runes of value, padded with a space on both
+// ends so that the first and last runes weigh as much as the others.
+func bigrams(value string) []string {
+	runes := []rune(" " + value + " ")
+	grams := make([]string, 0, len(runes)-1)
+
+	for i := 0; i+1 < len(runes); i++ {
+		gram := string(runes[i : i+2])
+		if !slices.Contains(grams, gram) {
+			grams = append(grams, gram)
+		}
+	}
+
+	return grams
+}
+
+func (x *textIndex) add(id int, value string) {
+	value = normalize.Key(value)
+	if value == "" {
+		return
+	}
+
+	entry, ok := x.entries[value]
+	if !ok {
+		grams := bigrams(value)
+		entry = &textEntry{ids: make(map[int]struct{}), grams: len(grams)}
+		x.entries[value] = entry
+
+		i, _ := slices.BinarySearch(x.sorted, value)
+		x.sorted = slices.Insert(x.sorted, i, value)
+
+		for _, gram := range grams {
+			if x.grams[gram] == nil {
+				x.grams[gram] = make(map[string]struct{})
+			}
+
+			x.grams[gram][value] = struct{}{}
+		}
+	}
+
+	entry.ids[id] = struct{}{}
+}
+
+func (x *textIndex) remove(id int, value string) {
+	value = normalize.Key(value)
+
+	entry, ok := x.entries[value]
+	if !ok {
+		return
+	}
+
+	delete(entry.ids, id)
+
+	if len(entry.ids) > 0 {
+		return
+	}
+
+	delete(x.entries, value)
+
+	if i, found := slices.BinarySearch(x.sorted, value); found {
+		x.sorted = slices.Delete(x.sorted, i, i+1)
+	}
+
+	for _, gram := range bigrams(value) {
+		delete(x.grams[gram], value)
+
+		if len(x.grams[gram]) == 0 {
+			delete(x.grams, gram)
+		}
+	}
+}
+
+// ids returns the IDs of the users holding value, a normalize.Key, or nil when none does. The
+// returned map must not be modified.
+func (x *textIndex) ids(value string) map[int]struct{} {
+	if entry, ok := x.entries[value]; ok {
+		return entry.ids
+	}
+
+	return nil
+}
+
+// prefix scores the values starting with text, a normalize.Key, by the share of the value it covers,
+// so that the shortest completions rank first.
+func (x *textIndex) prefix(text string) map[string]float64 {
+	scores := make(map[string]float64)
+	length := float64(utf8.RuneCountInString(text))
+
+	for i := sort.SearchStrings(x.sorted, text); i < len(x.sorted) && strings.HasPrefix(x.sorted[i], text); i++ {
+		scores[x.sorted[i]] = length / float64(utf8.RuneCountInString(x.sorted[i]))
+	}
+
+	return scores
+}
+
+// similar scores the values by their Dice coefficient with text, a normalize.Key: twice the number
+// of bigrams they share over the total number of bigrams of both, from 0 to 1 for equal values.
+// Values scoring below minScore are left out.
+func (x *textIndex) similar(text string, minScore float64) map[string]float64 {
+	grams := bigrams(text)
+	shared := make(map[string]int)
+
+	for _, gram := range grams {
+		for value := range x.grams[gram] {
+			shared[value]++
+		}
+	}
+
+	scores := make(map[string]float64)
+
+	for value, n := range shared {
+		score := 2 * float64(n) / float64(len(grams)+x.entries[value].grams)
+		if score >= minScore {
+			scores[value] = score
+		}
+	}
+
+	return scores
+}
+
+// len returns the number of distinct values indexed.
+func (x *textIndex) len() int {
+	return len(x.entries)
+}
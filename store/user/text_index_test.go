@@ -0,0 +1,54 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bigrams(t *testing.T) {
+	assert.Equal(t, []string{" j", "jo", "oh", "hn", "n "}, bigrams("john"))
+	assert.Equal(t, []string{" a", "aa", "a "}, bigrams("aaa"), "bigrams are distinct")
+	assert.Equal(t, []string{" j", "jo", "os", "sé", "é "}, bigrams("josé"))
+	assert.Equal(t, []string{"  "}, bigrams(""))
+}
+
+func Test_textIndex(t *testing.T) {
+	x := newTextIndex()
+
+	x.add(1, "John")
+	x.add(2, " john ")
+	x.add(3, "Johnny")
+	x.add(4, "Jane")
+	x.add(5, "")
+
+	assert.Equal(t, 3, x.len(), "values are indexed once, normalized, and empty values are skipped")
+	assert.Equal(t, []string{"jane", "john", "johnny"}, x.sorted)
+
+	t.Run("Prefix", func(t *testing.T) {
+		assert.Equal(t, map[string]float64{"john": 1, "johnny": 4.0 / 6}, x.prefix("john"))
+		assert.Equal(t, map[string]float64{"jane": 0.25, "john": 0.25, "johnny": 1.0 / 6}, x.prefix("j"))
+		assert.Empty(t, x.prefix("k"))
+	})
+
+	t.Run("Similar", func(t *testing.T) {
+		assert.Equal(t, map[string]float64{"john": 6.0 / 9, "johnny": 4.0 / 11, "jane": 2.0 / 9}, x.similar("jon", 0))
+		assert.Equal(t, map[string]float64{"john": 6.0 / 9}, x.similar("jon", 0.4))
+		assert.Equal(t, map[string]float64{"john": 0.4}, x.similar("jhon", 0.4))
+		assert.Equal(t, map[string]float64{"john": 1}, x.similar("john", 0.9))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		x.remove(1, "John")
+		assert.Equal(t, 3, x.len(), "values are kept while a user holds them")
+
+		x.remove(2, "JOHN")
+		x.remove(9, "Jane")
+		x.remove(9, "Unknown")
+
+		assert.Equal(t, 2, x.len())
+		assert.Equal(t, []string{"jane", "johnny"}, x.sorted)
+		assert.Equal(t, map[string]float64{"johnny": 4.0 / 6}, x.prefix("john"))
+		assert.NotContains(t, x.grams, "hn ", "bigrams of removed values are dropped")
+	})
+}
@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant used when a call does not name one, which keeps single tenant
+// deployments working unchanged.
+const Default = "default"
+
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+type tenantKey struct{}
+
+// Valid reports whether id is an acceptable tenant ID: 1 to 63 letters, digits, '_', '.' or '-',
+// starting with a letter or digit.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant stored in ctx, or Default when there is none.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Default
+}
@@ -0,0 +1,173 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// Enabled reports whether any TLS setting has been provided.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.ClientCAFile != ""
+}
+
+// New builds a server side *tls.Config. The certificate, key and client CA bundle are re-read
+// from disk whenever their modification time changes, so rotated files are picked up on the
+// next handshake without restarting the server.
+func New(cfg Config) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("tls: both cert file and key file are required")
+	}
+
+	r := &reloader{cfg: cfg, modTimes: make(map[string]time.Time)}
+
+	err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.getCertificate,
+	}
+
+	if cfg.ClientCAFile != "" {
+		// Chain verification is done in verifyClientCertificate so that it always runs against
+		// the latest client CA bundle.
+		tlsCfg.ClientAuth = tls.RequireAnyClientCert
+		tlsCfg.VerifyPeerCertificate = r.verifyClientCertificate
+	}
+
+	return tlsCfg, nil
+}
+
+type reloader struct {
+	cfg Config
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+func (r *reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+
+	return files
+}
+
+func (r *reloader) load() error {
+	modTimes := make(map[string]time.Time)
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: loading key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+
+		clientCAs = x509.NewCertPool()
+
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+
+	return nil
+}
+
+// reloadIfChanged reloads the files if any of them has been modified. A failed reload (for
+// instance while a rotation is only half written) keeps the previously loaded material.
+func (r *reloader) reloadIfChanged() {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return
+		}
+
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			_ = r.load()
+
+			return
+		}
+	}
+}
+
+func (r *reloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reloadIfChanged()
+
+	return r.cert, nil
+}
+
+func (r *reloader) verifyClientCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	r.mu.Lock()
+	roots := r.clientCAs
+	r.mu.Unlock()
+
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("tls: client certificate required")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("tls: parsing client certificate: %w", err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("tls: verifying client certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, serial int64, parent *testCert, isCA bool, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "user-detail-management-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+
+	if isCA {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	}
+
+	parentCert, parentKey := tmpl, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// handshake performs a TLS handshake over a loopback connection and returns the certificate
+// presented by the server along with the server side handshake error.
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) (*x509.Certificate, error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+
+			return
+		}
+
+		server := tls.Server(conn, serverCfg)
+		err = server.Handshake()
+		conn.Close()
+		serverErr <- err
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	client := tls.Client(conn, clientCfg)
+	clientErr := client.Handshake()
+
+	// A client certificate rejected by the server is only observed by the client on its first
+	// read, so the server side result is the one that matters.
+	err = <-serverErr
+	if err != nil {
+		return nil, err
+	}
+
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	return client.ConnectionState().PeerCertificates[0], nil
+}
+
+type testFiles struct {
+	dir      string
+	ca       *testCert
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+func newTestFiles(t *testing.T) *testFiles {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, nil, true, 0)
+	server := newTestCert(t, 2, ca, false, x509.ExtKeyUsageServerAuth)
+
+	f := &testFiles{
+		dir:      dir,
+		ca:       ca,
+		certFile: filepath.Join(dir, "server.crt"),
+		keyFile:  filepath.Join(dir, "server.key"),
+		caFile:   filepath.Join(dir, "ca.crt"),
+	}
+
+	modTime := time.Now().Add(-time.Minute)
+
+	writeFile(t, f.certFile, server.certPEM, modTime)
+	writeFile(t, f.keyFile, server.keyPEM, modTime)
+	writeFile(t, f.caFile, ca.certPEM, modTime)
+
+	return f
+}
+
+func (f *testFiles) clientConfig() *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(f.ca.cert)
+
+	return &tls.Config{RootCAs: roots, ServerName: "localhost"}
+}
+
+func Test_ConfigEnabled(t *testing.T) {
+	assert.False(t, Config{}.Enabled())
+	assert.True(t, Config{CertFile: "server.crt"}.Enabled())
+	assert.True(t, Config{ClientCAFile: "ca.crt"}.Enabled())
+}
+
+func Test_New(t *testing.T) {
+	f := newTestFiles(t)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"Cert and key", Config{CertFile: f.certFile, KeyFile: f.keyFile}, false},
+		{"Cert, key and client CA", Config{CertFile: f.certFile, KeyFile: f.keyFile, ClientCAFile: f.caFile}, false},
+		{"Missing key", Config{CertFile: f.certFile}, true},
+		{"Non existent cert", Config{CertFile: filepath.Join(f.dir, "missing.crt"), KeyFile: f.keyFile}, true},
+		{"Mismatched key pair", Config{CertFile: f.caFile, KeyFile: f.keyFile}, true},
+		{"Invalid client CA bundle", Config{CertFile: f.certFile, KeyFile: f.keyFile, ClientCAFile: f.keyFile}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := New(tt.cfg)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, cfg)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, cfg)
+		})
+	}
+}
+
+func Test_ServerTLS(t *testing.T) {
+	f := newTestFiles(t)
+
+	cfg, err := New(Config{CertFile: f.certFile, KeyFile: f.keyFile})
+	require.NoError(t, err)
+
+	cert, err := handshake(t, cfg, f.clientConfig())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cert.SerialNumber.Int64())
+}
+
+func Test_CertificateReload(t *testing.T) {
+	f := newTestFiles(t)
+
+	cfg, err := New(Config{CertFile: f.certFile, KeyFile: f.keyFile})
+	require.NoError(t, err)
+
+	rotated := newTestCert(t, 3, f.ca, false, x509.ExtKeyUsageServerAuth)
+	now := time.Now()
+
+	writeFile(t, f.certFile, rotated.certPEM, now)
+	writeFile(t, f.keyFile, rotated.keyPEM, now)
+
+	cert, err := handshake(t, cfg, f.clientConfig())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), cert.SerialNumber.Int64())
+}
+
+func Test_CertificateReloadKeepsPreviousOnFailure(t *testing.T) {
+	f := newTestFiles(t)
+
+	cfg, err := New(Config{CertFile: f.certFile, KeyFile: f.keyFile})
+	require.NoError(t, err)
+
+	writeFile(t, f.certFile, []byte("not a certificate"), time.Now())
+
+	cert, err := handshake(t, cfg, f.clientConfig())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cert.SerialNumber.Int64())
+}
+
+func Test_MutualTLS(t *testing.T) {
+	f := newTestFiles(t)
+
+	cfg, err := New(Config{CertFile: f.certFile, KeyFile: f.keyFile, ClientCAFile: f.caFile})
+	require.NoError(t, err)
+
+	otherCA := newTestCert(t, 10, nil, true, 0)
+
+	tests := []struct {
+		name       string
+		clientCert *testCert
+		wantErr    bool
+	}{
+		{"No client certificate", nil, true},
+		{"Client certificate signed by trusted CA", newTestCert(t, 4, f.ca, false, x509.ExtKeyUsageClientAuth), false},
+		{"Client certificate signed by unknown CA", newTestCert(t, 5, otherCA, false, x509.ExtKeyUsageClientAuth), true},
+		{"Certificate without client auth usage", newTestCert(t, 6, f.ca, false, x509.ExtKeyUsageServerAuth), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCfg := f.clientConfig()
+
+			if tt.clientCert != nil {
+				clientCfg.Certificates = []tls.Certificate{tt.clientCert.tlsCertificate(t)}
+			}
+
+			_, err := handshake(t, cfg, clientCfg)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ClientCAReload(t *testing.T) {
+	f := newTestFiles(t)
+
+	cfg, err := New(Config{CertFile: f.certFile, KeyFile: f.keyFile, ClientCAFile: f.caFile})
+	require.NoError(t, err)
+
+	newCA := newTestCert(t, 20, nil, true, 0)
+	client := newTestCert(t, 21, newCA, false, x509.ExtKeyUsageClientAuth)
+
+	clientCfg := f.clientConfig()
+	clientCfg.Certificates = []tls.Certificate{client.tlsCertificate(t)}
+
+	_, err = handshake(t, cfg, clientCfg)
+	assert.Error(t, err)
+
+	writeFile(t, f.caFile, append(f.ca.certPEM, newCA.certPEM...), time.Now())
+
+	_, err = handshake(t, cfg, clientCfg)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,320 @@
+package tracing
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/redact"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+const storeTracerName = "github.com/ssshekhu53/user-detail-management/store"
+
+type tracedStore struct {
+	store.User
+
+	tracer trace.Tracer
+}
+
+// TraceStore returns a store.User recording a span for every operation applied through it.
+func TraceStore(userStore store.User, tp trace.TracerProvider) store.User {
+	return &tracedStore{User: userStore, tracer: tp.Tracer(storeTracerName)}
+}
+
+func (s *tracedStore) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("tenant", tenant.FromContext(ctx)))
+
+	return s.tracer.Start(ctx, "store.User/"+operation, trace.WithAttributes(attrs...))
+}
+
+func (s *tracedStore) Create(ctx context.Context, user *models.User) int {
+	ctx, span := s.start(ctx, "Create")
+	defer span.End()
+
+	id := s.User.Create(ctx, user)
+
+	span.SetAttributes(attribute.Int("user.id", id))
+
+	return id
+}
+
+func (s *tracedStore) Get(ctx context.Context, filters *models.Filters) []models.User {
+	ctx, span := s.start(ctx, "Get", attribute.StringSlice("user.filter_fields", FilterFields(filters)))
+	defer span.End()
+
+	users := s.User.Get(ctx, filters)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (s *tracedStore) GetByID(ctx context.Context, id int) (*models.User, error) {
+	ctx, span := s.start(ctx, "GetByID", attribute.Int("user.id", id))
+	defer span.End()
+
+	user, err := s.User.GetByID(ctx, id)
+	if err != nil {
+		msg := redact.Text(err.Error())
+
+		span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", msg)))
+		span.SetStatus(codes.Error, msg)
+	}
+
+	return user, err
+}
+
+func (s *tracedStore) GetByIDs(ctx context.Context, ids []int) []models.User {
+	ctx, span := s.start(ctx, "GetByIDs", attribute.Int("user.id_count", len(ids)))
+	defer span.End()
+
+	users := s.User.GetByIDs(ctx, ids)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (s *tracedStore) GetByPhone(ctx context.Context, phone string) []models.User {
+	ctx, span := s.start(ctx, "GetByPhone")
+	defer span.End()
+
+	users := s.User.GetByPhone(ctx, phone)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (s *tracedStore) Scan(ctx context.Context, filters *models.Filters, afterID, limit int) []models.User {
+	ctx, span := s.start(ctx, "Scan",
+		attribute.StringSlice("user.filter_fields", FilterFields(filters)),
+		attribute.Int("user.after_id", afterID),
+		attribute.Int("user.limit", limit),
+	)
+	defer span.End()
+
+	users := s.User.Scan(ctx, filters, afterID, limit)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(users)))
+
+	return users
+}
+
+func (s *tracedStore) Count(ctx context.Context, filters *models.Filters) int {
+	ctx, span := s.start(ctx, "Count", attribute.StringSlice("user.filter_fields", FilterFields(filters)))
+	defer span.End()
+
+	count := s.User.Count(ctx, filters)
+
+	span.SetAttributes(attribute.Int("user.result_count", count))
+
+	return count
+}
+
+func (s *tracedStore) Exists(ctx context.Context, filters *models.Filters) bool {
+	ctx, span := s.start(ctx, "Exists", attribute.StringSlice("user.filter_fields", FilterFields(filters)))
+	defer span.End()
+
+	exists := s.User.Exists(ctx, filters)
+
+	span.SetAttributes(attribute.Bool("user.exists", exists))
+
+	return exists
+}
+
+func (s *tracedStore) Update(ctx context.Context, user *models.User) {
+	ctx, span := s.start(ctx, "Update", attribute.Int("user.id", user.ID))
+	defer span.End()
+
+	s.User.Update(ctx, user)
+}
+
+func (s *tracedStore) Upsert(ctx context.Context, users []models.User) []store.UpsertResult {
+	ctx, span := s.start(ctx, "Upsert", attribute.Int("user.count", len(users)))
+	defer span.End()
+
+	return s.User.Upsert(ctx, users)
+}
+
+func (s *tracedStore) Delete(ctx context.Context, id int) {
+	ctx, span := s.start(ctx, "Delete", attribute.Int("user.id", id))
+	defer span.End()
+
+	s.User.Delete(ctx, id)
+}
+
+func (s *tracedStore) Snapshot(ctx context.Context) store.Snapshot {
+	ctx, span := s.start(ctx, "Snapshot")
+	defer span.End()
+
+	snapshot := s.User.Snapshot(ctx)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(snapshot.Users)))
+
+	return snapshot
+}
+
+func (s *tracedStore) Restore(ctx context.Context, snapshot store.Snapshot) error {
+	ctx, span := s.start(ctx, "Restore", attribute.Int("user.count", len(snapshot.Users)))
+	defer span.End()
+
+	err := s.User.Restore(ctx, snapshot)
+	if err != nil {
+		msg := redact.Text(err.Error())
+
+		span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", msg)))
+		span.SetStatus(codes.Error, msg)
+	}
+
+	return err
+}
+
+func (s *tracedStore) Changes(ctx context.Context, filters *models.Filters, afterRevision int64) (store.Changes, error) {
+	ctx, span := s.start(ctx, "Changes",
+		attribute.StringSlice("user.filter_fields", FilterFields(filters)),
+		attribute.Int64("user.after_revision", afterRevision),
+	)
+	defer span.End()
+
+	changes, err := s.User.Changes(ctx, filters, afterRevision)
+	if err != nil {
+		msg := redact.Text(err.Error())
+
+		span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", msg)))
+		span.SetStatus(codes.Error, msg)
+
+		return changes, err
+	}
+
+	span.SetAttributes(attribute.Int("user.result_count", len(changes.Events)), attribute.Int64("user.revision", changes.Revision))
+
+	return changes, nil
+}
+
+func (s *tracedStore) Suggest(ctx context.Context, query store.SuggestQuery) []store.Suggestion {
+	// The text is a name or a city, so only its length is recorded.
+	ctx, span := s.start(ctx, "Suggest",
+		attribute.StringSlice("user.suggest_fields", SuggestFields(query.Fields)),
+		attribute.Bool("user.suggest_prefix", query.Prefix),
+		attribute.Int("user.suggest_text_length", utf8.RuneCountInString(query.Text)),
+		attribute.Int("user.limit", query.Limit),
+	)
+	defer span.End()
+
+	suggestions := s.User.Suggest(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(suggestions)))
+
+	return suggestions
+}
+
+func (s *tracedStore) Stats(ctx context.Context, query store.StatsQuery) store.Stats {
+	ctx, span := s.start(ctx, "Stats",
+		attribute.StringSlice("user.filter_fields", FilterFields(query.Filters)),
+		attribute.String("user.group_by", string(query.GroupBy)),
+	)
+	defer span.End()
+
+	stats := s.User.Stats(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.result_count", stats.Total.Count), attribute.Int("user.group_count", len(stats.Groups)))
+
+	return stats
+}
+
+func (s *tracedStore) FindDuplicates(ctx context.Context, query store.DuplicatesQuery) []store.DuplicateCluster {
+	ctx, span := s.start(ctx, "FindDuplicates",
+		attribute.StringSlice("user.duplicate_rules", DuplicateRules(query.Rules)),
+		attribute.Float64("user.min_similarity", query.MinSimilarity),
+	)
+	defer span.End()
+
+	clusters := s.User.FindDuplicates(ctx, query)
+
+	span.SetAttributes(attribute.Int("user.cluster_count", len(clusters)))
+
+	return clusters
+}
+
+func (s *tracedStore) Merge(ctx context.Context, req models.MergeRequest) (*models.User, error) {
+	ctx, span := s.start(ctx, "Merge", attribute.Int("user.id", req.SurvivorID), attribute.Int("user.merged_id", req.MergedID))
+	defer span.End()
+
+	user, err := s.User.Merge(ctx, req)
+	if err != nil {
+		msg := redact.Text(err.Error())
+
+		span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.message", msg)))
+		span.SetStatus(codes.Error, msg)
+	}
+
+	return user, err
+}
+
+func (s *tracedStore) Merges(ctx context.Context, id int) []store.MergeRecord {
+	ctx, span := s.start(ctx, "Merges", attribute.Int("user.id", id))
+	defer span.End()
+
+	merges := s.User.Merges(ctx, id)
+
+	span.SetAttributes(attribute.Int("user.result_count", len(merges)))
+
+	return merges
+}
+
+// DuplicateRules names the rules of a duplicates query.
+func DuplicateRules(rules []store.DuplicateRule) []string {
+	names := make([]string, 0, len(rules))
+
+	for _, rule := range rules {
+		names = append(names, string(rule))
+	}
+
+	return names
+}
+
+// SuggestFields names the fields matched by a suggest query.
+func SuggestFields(fields []store.SuggestField) []string {
+	names := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		names = append(names, string(field))
+	}
+
+	return names
+}
+
+// FilterFields names the fields filters narrows on. Only the names are recorded on spans so that
+// traces never hold personal data.
+func FilterFields(filters *models.Filters) []string {
+	if filters == nil {
+		return []string{}
+	}
+
+	fields := []string{}
+
+	if filters.Fname != nil {
+		fields = append(fields, "fname")
+	}
+
+	if filters.City != nil {
+		fields = append(fields, "city")
+	}
+
+	if filters.Phone != nil {
+		fields = append(fields, "phone")
+	}
+
+	if filters.Height != nil {
+		fields = append(fields, "height")
+	}
+
+	return fields
+}
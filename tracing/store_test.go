@@ -0,0 +1,211 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+	"github.com/ssshekhu53/user-detail-management/utils"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func Test_TraceStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	recorder := tracetest.NewSpanRecorder()
+	traced := TraceStore(mockStore, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	ctx := tenant.NewContext(context.Background(), "acme")
+	filters := &models.Filters{Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston")}
+
+	mockStore.EXPECT().Get(gomock.Any(), filters).Return([]models.User{{ID: 1}, {ID: 2}})
+	mockStore.EXPECT().GetByID(gomock.Any(), 3).Return(nil, errors.UserNotFound{ID: 3})
+	mockStore.EXPECT().Create(gomock.Any(), gomock.Any()).Return(4)
+
+	assert.Len(t, traced.Get(ctx, filters), 2)
+
+	_, err := traced.GetByID(ctx, 3)
+	assert.Error(t, err)
+
+	assert.Equal(t, 4, traced.Create(ctx, &models.User{Fname: "John"}))
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	get := spanAttributes(spans[0])
+	assert.Equal(t, "store.User/Get", spans[0].Name())
+	assert.Equal(t, []string{"fname", "city"}, get["user.filter_fields"].AsStringSlice())
+	assert.Equal(t, int64(2), get["user.result_count"].AsInt64())
+	assert.Equal(t, "acme", get["tenant"].AsString())
+
+	assert.Equal(t, "store.User/GetByID", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	assert.Equal(t, "store.User/Create", spans[2].Name())
+	assert.Equal(t, int64(4), spanAttributes(spans[2])["user.id"].AsInt64())
+}
+
+func Test_FilterFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters *models.Filters
+		want    []string
+	}{
+		{"No filters", nil, []string{}},
+		{"Empty filters", &models.Filters{}, []string{}},
+		{"All fields", &models.Filters{Fname: utils.StrPtr("John"), City: utils.StrPtr("Boston"), Phone: utils.StrPtr("1234567890"), Height: utils.Float64Ptr(5.9)}, []string{"fname", "city", "phone", "height"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FilterFields(tt.filters))
+		})
+	}
+}
+
+func Test_TraceStoreSuggest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	recorder := tracetest.NewSpanRecorder()
+	traced := TraceStore(mockStore, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	query := store.SuggestQuery{Text: "José", Fields: []store.SuggestField{store.SuggestFname}, Prefix: true, Limit: 5}
+
+	mockStore.EXPECT().Suggest(gomock.Any(), query).Return([]store.Suggestion{{User: models.User{ID: 1}, Score: 1}})
+
+	assert.Len(t, traced.Suggest(context.Background(), query), 1)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "store.User/Suggest", spans[0].Name())
+	assert.Equal(t, []string{"fname"}, attrs["user.suggest_fields"].AsStringSlice())
+	assert.True(t, attrs["user.suggest_prefix"].AsBool())
+	assert.Equal(t, int64(4), attrs["user.suggest_text_length"].AsInt64())
+	assert.Equal(t, int64(5), attrs["user.limit"].AsInt64())
+	assert.Equal(t, int64(1), attrs["user.result_count"].AsInt64())
+
+	for _, kv := range spans[0].Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "José", "the text is not recorded")
+	}
+}
+
+func Test_TraceStoreStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	recorder := tracetest.NewSpanRecorder()
+	traced := TraceStore(mockStore, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	city := "Boston"
+	query := store.StatsQuery{Filters: &models.Filters{City: &city}, GroupBy: store.GroupByMarried, HistogramBuckets: 10}
+
+	mockStore.EXPECT().Stats(gomock.Any(), query).Return(store.Stats{
+		Total:  store.StatsGroup{Count: 3},
+		Groups: []store.StatsGroup{{Key: "true", Count: 2}, {Key: "false", Count: 1}},
+	})
+
+	assert.Equal(t, 3, traced.Stats(context.Background(), query).Total.Count)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "store.User/Stats", spans[0].Name())
+	assert.Equal(t, []string{"city"}, attrs["user.filter_fields"].AsStringSlice())
+	assert.Equal(t, "married", attrs["user.group_by"].AsString())
+	assert.Equal(t, int64(3), attrs["user.result_count"].AsInt64())
+	assert.Equal(t, int64(2), attrs["user.group_count"].AsInt64())
+}
+
+func Test_TraceStoreCountExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	recorder := tracetest.NewSpanRecorder()
+	traced := TraceStore(mockStore, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	city := "Boston"
+	filters := &models.Filters{City: &city}
+
+	mockStore.EXPECT().Count(gomock.Any(), filters).Return(2)
+	mockStore.EXPECT().Exists(gomock.Any(), filters).Return(true)
+
+	assert.Equal(t, 2, traced.Count(context.Background(), filters))
+	assert.True(t, traced.Exists(context.Background(), filters))
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+
+	count, exists := spanAttributes(spans[0]), spanAttributes(spans[1])
+	assert.Equal(t, "store.User/Count", spans[0].Name())
+	assert.Equal(t, []string{"city"}, count["user.filter_fields"].AsStringSlice())
+	assert.Equal(t, int64(2), count["user.result_count"].AsInt64())
+	assert.Equal(t, "store.User/Exists", spans[1].Name())
+	assert.Equal(t, []string{"city"}, exists["user.filter_fields"].AsStringSlice())
+	assert.True(t, exists["user.exists"].AsBool())
+}
+
+func Test_TraceStoreDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := store.NewMockUser(ctrl)
+	recorder := tracetest.NewSpanRecorder()
+	traced := TraceStore(mockStore, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	ctx := context.Background()
+	query := store.DuplicatesQuery{Rules: []store.DuplicateRule{store.DuplicatePhone}, MinSimilarity: 0.6}
+	req := models.MergeRequest{SurvivorID: 1, MergedID: 2}
+
+	mockStore.EXPECT().FindDuplicates(gomock.Any(), query).Return([]store.DuplicateCluster{{Users: []models.User{{ID: 1}, {ID: 2}}}})
+	mockStore.EXPECT().Merge(gomock.Any(), req).Return(nil, errors.UserNotFound{ID: 2})
+
+	assert.Len(t, traced.FindDuplicates(ctx, query), 1)
+	_, err := traced.Merge(ctx, req)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		return
+	}
+
+	duplicates, merge := spanAttributes(spans[0]), spanAttributes(spans[1])
+	assert.Equal(t, "store.User/FindDuplicates", spans[0].Name())
+	assert.Equal(t, []string{"phone"}, duplicates["user.duplicate_rules"].AsStringSlice())
+	assert.Equal(t, 0.6, duplicates["user.min_similarity"].AsFloat64())
+	assert.Equal(t, int64(1), duplicates["user.cluster_count"].AsInt64())
+	assert.Equal(t, "store.User/Merge", spans[1].Name())
+	assert.Equal(t, int64(1), merge["user.id"].AsInt64())
+	assert.Equal(t, int64(2), merge["user.merged_id"].AsInt64())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
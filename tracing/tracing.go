@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	// Exporter selects where spans are sent: ExporterStdout, ExporterFile or ExporterOTLP. Tracing
+	// is disabled when it is empty.
+	Exporter string
+	// File is the path spans are appended to by ExporterFile.
+	File        string
+	ServiceName string
+}
+
+func (c Config) Enabled() bool {
+	return c.Exporter != ""
+}
+
+// New builds a tracer provider exporting to the configured destination. The OTLP exporter is
+// configured through the standard OTEL_EXPORTER_OTLP_* environment variables. Callers must
+// Shutdown the provider to flush pending spans.
+func New(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err := newWriterExporter(os.Stdout)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+
+	case ExporterFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("tracing: the file exporter needs a file")
+		}
+
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+
+		exporter, err := newWriterExporter(f)
+		if err != nil {
+			f.Close()
+
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithSyncer(fileExporter{SpanExporter: exporter, file: f}))
+
+	case ExporterOTLP:
+		exporter, err := otlptracegrpc.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+func newWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// fileExporter closes the file spans are written to once the provider shuts it down.
+type fileExporter struct {
+	sdktrace.SpanExporter
+
+	file *os.File
+}
+
+func (e fileExporter) Shutdown(ctx context.Context) error {
+	if err := e.SpanExporter.Shutdown(ctx); err != nil {
+		e.file.Close()
+
+		return err
+	}
+
+	return e.file.Close()
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"Stdout exporter", Config{Exporter: ExporterStdout}, false},
+		{"File exporter", Config{Exporter: ExporterFile, File: filepath.Join(dir, "spans.json")}, false},
+		{"OTLP exporter", Config{Exporter: ExporterOTLP}, false},
+		{"File exporter without file", Config{Exporter: ExporterFile}, true},
+		{"File exporter with unwritable file", Config{Exporter: ExporterFile, File: filepath.Join(dir, "missing", "spans.json")}, true},
+		{"Unknown exporter", Config{Exporter: "zipkin"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := New(context.Background(), tt.cfg)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, tp)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, tp.Shutdown(context.Background()))
+		})
+	}
+}
+
+func Test_FileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	tp, err := New(context.Background(), Config{Exporter: ExporterFile, File: file, ServiceName: "test-service"})
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"Name":"operation"`)
+	assert.Contains(t, string(data), "test-service")
+}
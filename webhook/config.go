@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	DefaultTimeout        = 10 * time.Second
+	DefaultMaxDeadLetters = 10000
+)
+
+// AllTenants, listed in the tenants of an endpoint, sends it the events of every tenant.
+const AllTenants = "*"
+
+// Endpoint is a URL the events of Tenants are POSTed to, signed with Secret.
+type Endpoint struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+	// Tenants are the tenants whose events the endpoint receives, or AllTenants.
+	Tenants []string `json:"tenants"`
+}
+
+// receives reports whether the events of tenant are sent to e.
+func (e Endpoint) receives(tenant string) bool {
+	return slices.Contains(e.Tenants, tenant) || slices.Contains(e.Tenants, AllTenants)
+}
+
+type Config struct {
+	Endpoints []Endpoint
+	// MaxAttempts is how many times a delivery is tried before it is dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt. It doubles on every attempt, up to
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds every attempt.
+	Timeout time.Duration
+	// MaxDeadLetters is how many dead letters are kept, across endpoints and tenants. The oldest are
+	// dropped to make room for new ones.
+	MaxDeadLetters int
+}
+
+// fileConfig is the JSON form of Config, with durations such as "1s" or "2m30s".
+type fileConfig struct {
+	Endpoints      []Endpoint `json:"endpoints"`
+	MaxAttempts    int        `json:"max_attempts"`
+	InitialBackoff string     `json:"initial_backoff"`
+	MaxBackoff     string     `json:"max_backoff"`
+	Timeout        string     `json:"timeout"`
+	MaxDeadLetters int        `json:"max_dead_letters"`
+}
+
+// LoadConfig reads a JSON configuration file. Unset settings take their default value.
+func LoadConfig(file string) (Config, error) {
+	var raw fileConfig
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Config{}, fmt.Errorf("webhook: %w", err)
+	}
+
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("webhook: parsing %s: %w", file, err)
+	}
+
+	cfg := Config{Endpoints: raw.Endpoints, MaxAttempts: raw.MaxAttempts, MaxDeadLetters: raw.MaxDeadLetters}
+
+	durations := []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"initial_backoff", raw.InitialBackoff, &cfg.InitialBackoff},
+		{"max_backoff", raw.MaxBackoff, &cfg.MaxBackoff},
+		{"timeout", raw.Timeout, &cfg.Timeout},
+	}
+
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+
+		*d.dst, err = time.ParseDuration(d.value)
+		if err != nil || *d.dst <= 0 {
+			return Config{}, fmt.Errorf("webhook: invalid %s %q", d.name, d.value)
+		}
+	}
+
+	if len(cfg.Endpoints) == 0 {
+		return Config{}, fmt.Errorf("webhook: no endpoint configured")
+	}
+
+	for _, endpoint := range cfg.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Config{}, fmt.Errorf("webhook: invalid endpoint URL %q", endpoint.URL)
+		}
+
+		if endpoint.Secret == "" {
+			return Config{}, fmt.Errorf("webhook: no secret for %s", endpoint.URL)
+		}
+
+		// Endpoints name the tenants they receive, so that none receives the users of every tenant
+		// by mistake.
+		if len(endpoint.Tenants) == 0 {
+			return Config{}, fmt.Errorf("webhook: no tenants for %s", endpoint.URL)
+		}
+
+		for _, id := range endpoint.Tenants {
+			if id != AllTenants && !tenant.Valid(id) {
+				return Config{}, fmt.Errorf("webhook: invalid tenant %q for %s", id, endpoint.URL)
+			}
+		}
+	}
+
+	if cfg.MaxAttempts < 0 {
+		return Config{}, fmt.Errorf("webhook: max_attempts must not be negative")
+	}
+
+	if cfg.MaxDeadLetters < 0 {
+		return Config{}, fmt.Errorf("webhook: max_dead_letters must not be negative")
+	}
+
+	return cfg, nil
+}
+
+// withDefaults fills the unset settings of c with their default value.
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultInitialBackoff
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+
+	if c.MaxDeadLetters <= 0 {
+		c.MaxDeadLetters = DefaultMaxDeadLetters
+	}
+
+	return c
+}
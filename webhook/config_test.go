@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+		return file
+	}
+
+	cfg, err := LoadConfig(write("valid.json", `{
+		"endpoints": [{"url": "https://crm.example.com/hooks/users", "secret": "s3cret", "tenants": ["acme", "*"]}],
+		"max_attempts": 3,
+		"max_dead_letters": 50,
+		"initial_backoff": "500ms",
+		"timeout": "2s"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		Endpoints:      []Endpoint{{URL: "https://crm.example.com/hooks/users", Secret: "s3cret", Tenants: []string{"acme", AllTenants}}},
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		Timeout:        2 * time.Second,
+		MaxDeadLetters: 50,
+	}, cfg)
+
+	assert.Equal(t, Config{
+		Endpoints:      cfg.Endpoints,
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     DefaultMaxBackoff,
+		Timeout:        2 * time.Second,
+		MaxDeadLetters: 50,
+	}, cfg.withDefaults())
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"No endpoint", `{"endpoints": []}`, "webhook: no endpoint configured"},
+		{"Invalid URL", `{"endpoints": [{"url": "crm.example.com", "secret": "s"}]}`, `webhook: invalid endpoint URL "crm.example.com"`},
+		{"No secret", `{"endpoints": [{"url": "https://crm.example.com"}]}`, "webhook: no secret for https://crm.example.com"},
+		{"Invalid duration", `{"endpoints": [{"url": "https://crm.example.com", "secret": "s", "tenants": ["*"]}], "max_backoff": "soon"}`, `webhook: invalid max_backoff "soon"`},
+		{"No tenants", `{"endpoints": [{"url": "https://crm.example.com", "secret": "s"}]}`, "webhook: no tenants for https://crm.example.com"},
+		{"Invalid tenant", `{"endpoints": [{"url": "https://crm.example.com", "secret": "s", "tenants": ["ac me"]}]}`, `webhook: invalid tenant "ac me" for https://crm.example.com`},
+		{"Negative dead letters", `{"endpoints": [{"url": "https://crm.example.com", "secret": "s", "tenants": ["*"]}], "max_dead_letters": -1}`, "webhook: max_dead_letters must not be negative"},
+		{"Negative attempts", `{"endpoints": [{"url": "https://crm.example.com", "secret": "s", "tenants": ["*"]}], "max_attempts": -1}`, "webhook: max_attempts must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(write("invalid.json", tt.content))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
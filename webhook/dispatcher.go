@@ -0,0 +1,390 @@
+// Package webhook delivers the changes written to the store outbox to HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ssshekhu53/user-detail-management/backoff"
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/events"
+	"github.com/ssshekhu53/user-detail-management/logging"
+	"github.com/ssshekhu53/user-detail-management/store"
+)
+
+// batchSize is the number of outbox messages read at once.
+const batchSize = 100
+
+// newEvent returns the event POSTed for message, the JSON body of the requests. It is identified by
+// the ID of message, so that it keeps the same ID across endpoints and retries.
+func newEvent(message store.OutboxMessage) events.Event {
+	event := events.FromChange(message.Tenant, message.Event)
+	event.ID = strconv.FormatInt(message.ID, 10)
+	event.OccurredAt = message.CreatedAt.UTC()
+
+	return event
+}
+
+// DeadLetter is an event an endpoint failed to receive in every attempt.
+type DeadLetter struct {
+	ID       int64
+	Endpoint string
+	// MessageID is the ID of the outbox message announcing Event, which Event carries as its ID.
+	MessageID int64
+	Event     events.Event
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+}
+
+// permanentError is a delivery failure retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (p permanentError) Error() string {
+	return p.err.Error()
+}
+
+type Dispatcher struct {
+	cfg       Config
+	userStore store.User
+	client    *http.Client
+	now       func() time.Time
+	// sleep waits for d or until ctx is done. It is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu sync.Mutex
+	// cursors holds, for every endpoint, the ID of the last outbox message it is done with.
+	cursors []int64
+	// deadLetters holds at most cfg.MaxDeadLetters dead letters, by ID.
+	deadLetters      map[int64]DeadLetter
+	lastDeadLetterID int64
+	// oldestDeadLetterID is at most the ID of the oldest dead letter held.
+	oldestDeadLetterID int64
+}
+
+// New returns a dispatcher POSTing the messages of the outbox of userStore to the endpoints of cfg
+// receiving their tenant. Messages are acknowledged once every endpoint received them, skipped them
+// or dead-lettered them.
+func New(userStore store.User, cfg Config) *Dispatcher {
+	cfg = cfg.withDefaults()
+
+	return &Dispatcher{
+		cfg:         cfg,
+		userStore:   userStore,
+		client:      &http.Client{},
+		now:         time.Now,
+		sleep:       backoff.Sleep,
+		cursors:     make([]int64, len(cfg.Endpoints)),
+		deadLetters: make(map[int64]DeadLetter),
+	}
+}
+
+// Run delivers the outbox, every endpoint in order and independently of the others, until ctx is
+// done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := range d.cfg.Endpoints {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			d.deliverOutbox(ctx, i)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliverOutbox(ctx context.Context, endpoint int) {
+	target := d.cfg.Endpoints[endpoint]
+	logger := logging.FromContext(ctx).With("endpoint", target.URL)
+
+	d.mu.Lock()
+	cursor := d.cursors[endpoint]
+	d.mu.Unlock()
+
+	for {
+		messages, written := d.userStore.Outbox(cursor, batchSize)
+
+		for _, message := range messages {
+			if target.receives(message.Tenant) {
+				if !d.deliverMessage(ctx, logger, target, message) {
+					return
+				}
+			}
+
+			cursor = message.ID
+			d.advance(endpoint, cursor)
+		}
+
+		if len(messages) == batchSize {
+			continue
+		}
+
+		select {
+		case <-written:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverMessage delivers message to endpoint, dead-lettering it when every attempt fails. It
+// reports false when ctx is done before the delivery ends.
+func (d *Dispatcher) deliverMessage(ctx context.Context, logger *slog.Logger, endpoint Endpoint, message store.OutboxMessage) bool {
+	event := newEvent(message)
+
+	attempts, err := d.deliverWithRetries(ctx, endpoint, event)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err == nil {
+		logger.Debug("webhook delivered", "event_id", event.ID, "attempts", attempts)
+
+		return true
+	}
+
+	id, dropped := d.deadLetter(DeadLetter{
+		Endpoint:  endpoint.URL,
+		MessageID: message.ID,
+		Event:     event,
+		Attempts:  attempts,
+		LastError: err.Error(),
+	})
+
+	logger.Warn("webhook delivery dead-lettered", "event_id", event.ID, "dead_letter_id", id, "attempts", attempts, "error", err)
+
+	if dropped != 0 {
+		logger.Warn("dead letter dropped", "dead_letter_id", dropped, "max_dead_letters", d.cfg.MaxDeadLetters)
+	}
+
+	return true
+}
+
+// advance records that endpoint is done with the outbox messages up to cursor, and acknowledges
+// the messages every endpoint is done with.
+func (d *Dispatcher) advance(endpoint int, cursor int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.cursors[endpoint] = cursor
+
+	d.userStore.AckOutbox(slices.Min(d.cursors))
+}
+
+// deliverWithRetries POSTs event to endpoint until it succeeds, fails for good, or every attempt
+// is used, waiting longer after every failed attempt. It returns the number of attempts made.
+func (d *Dispatcher) deliverWithRetries(ctx context.Context, endpoint Endpoint, event events.Event) (int, error) {
+	delay := d.cfg.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := d.deliver(ctx, endpoint, event)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if _, ok := err.(permanentError); ok || attempt >= d.cfg.MaxAttempts {
+			return attempt, err
+		}
+
+		if sleepErr := d.sleep(ctx, backoff.Jitter(delay)); sleepErr != nil {
+			return attempt, err
+		}
+
+		delay = min(2*delay, d.cfg.MaxBackoff)
+	}
+}
+
+// deliver makes a single attempt at POSTing event to endpoint. Responses with a 4xx status other
+// than 408 and 429 fail for good.
+func (d *Dispatcher) deliver(ctx context.Context, endpoint Endpoint, event events.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return permanentError{err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err: err}
+	}
+
+	timestamp := d.now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, event.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err: err}
+	}
+
+	return err
+}
+
+// deadLetter keeps deadLetter under the next ID, which it returns, dropping the oldest dead letter
+// when cfg.MaxDeadLetters are held already. It returns the ID of the dropped dead letter, or 0.
+func (d *Dispatcher) deadLetter(deadLetter DeadLetter) (id, dropped int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lastDeadLetterID++
+
+	deadLetter.ID = d.lastDeadLetterID
+	deadLetter.FailedAt = d.now()
+
+	d.deadLetters[deadLetter.ID] = deadLetter
+
+	if len(d.deadLetters) > d.cfg.MaxDeadLetters {
+		// IDs increase, so the oldest dead letter is the first one held from oldestDeadLetterID on.
+		for ; dropped == 0; d.oldestDeadLetterID++ {
+			if _, ok := d.deadLetters[d.oldestDeadLetterID]; ok {
+				dropped = d.oldestDeadLetterID
+				delete(d.deadLetters, dropped)
+			}
+		}
+	}
+
+	return deadLetter.ID, dropped
+}
+
+// DeadLetters returns the dead letters of the events of tenant, sorted by ID.
+func (d *Dispatcher) DeadLetters(tenant string) []DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deadLetters := make([]DeadLetter, 0)
+
+	for _, deadLetter := range d.deadLetters {
+		if deadLetter.Event.Tenant == tenant {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+
+	slices.SortFunc(deadLetters, func(a, b DeadLetter) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return deadLetters
+}
+
+// Replay makes a single attempt at delivering again the dead letters of tenant with the given IDs,
+// or all of them when ids is empty. Delivered dead letters are dropped; the others are returned,
+// with their attempt count and last error updated. It fails with errors.DeadLetterNotFound,
+// without replaying anything, when an ID is not one of the dead letters of tenant.
+func (d *Dispatcher) Replay(ctx context.Context, tenant string, ids []int64) (delivered []int64, failed []DeadLetter, err error) {
+	replayed := d.DeadLetters(tenant)
+
+	if len(ids) > 0 {
+		byID := make(map[int64]DeadLetter, len(replayed))
+
+		for _, deadLetter := range replayed {
+			byID[deadLetter.ID] = deadLetter
+		}
+
+		replayed = replayed[:0]
+
+		for _, id := range ids {
+			deadLetter, ok := byID[id]
+			if !ok {
+				return nil, nil, errors.DeadLetterNotFound{ID: id}
+			}
+
+			replayed = append(replayed, deadLetter)
+		}
+	}
+
+	delivered, failed = make([]int64, 0), make([]DeadLetter, 0)
+
+	for _, deadLetter := range replayed {
+		err := d.deliver(ctx, d.endpoint(deadLetter.Endpoint), deadLetter.Event)
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		deadLetter, ok := d.replayed(deadLetter.ID, err)
+		if !ok {
+			// The dead letter was dropped by a concurrent replay.
+			continue
+		}
+
+		if err != nil {
+			failed = append(failed, deadLetter)
+		} else {
+			delivered = append(delivered, deadLetter.ID)
+		}
+	}
+
+	logging.FromContext(ctx).Info("dead letters replayed", "delivered", len(delivered), "failed", len(failed))
+
+	return delivered, failed, nil
+}
+
+// replayed drops the dead letter with the given ID when err is nil, and records the failed attempt
+// otherwise. It returns the dead letter, updated, and whether it was still held.
+func (d *Dispatcher) replayed(id int64, err error) (DeadLetter, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deadLetter, ok := d.deadLetters[id]
+	if !ok {
+		return DeadLetter{}, false
+	}
+
+	if err == nil {
+		delete(d.deadLetters, id)
+
+		return deadLetter, true
+	}
+
+	deadLetter.Attempts++
+	deadLetter.LastError = err.Error()
+	deadLetter.FailedAt = d.now()
+
+	d.deadLetters[id] = deadLetter
+
+	return deadLetter, true
+}
+
+func (d *Dispatcher) endpoint(url string) Endpoint {
+	for _, endpoint := range d.cfg.Endpoints {
+		if endpoint.URL == url {
+			return endpoint
+		}
+	}
+
+	return Endpoint{URL: url}
+}
@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssshekhu53/user-detail-management/errors"
+	"github.com/ssshekhu53/user-detail-management/events"
+	"github.com/ssshekhu53/user-detail-management/models"
+	"github.com/ssshekhu53/user-detail-management/store"
+	storeUser "github.com/ssshekhu53/user-detail-management/store/user"
+	"github.com/ssshekhu53/user-detail-management/tenant"
+)
+
+// receiver is a webhook endpoint answering with the next of statuses, then with 200 OK.
+type receiver struct {
+	*httptest.Server
+
+	secret string
+
+	mu       sync.Mutex
+	statuses []int
+	events   []events.Event
+	ids      []string
+	invalid  int
+}
+
+func newReceiver(t *testing.T, secret string, statuses ...int) *receiver {
+	t.Helper()
+
+	r := &receiver{secret: secret, statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) serve(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !Verify(r.secret, req.Header.Get(TimestampHeader), req.Header.Get(SignatureHeader), body) ||
+		req.Header.Get("Content-Type") != "application/json" {
+		r.invalid++
+	}
+
+	r.ids = append(r.ids, req.Header.Get(IDHeader))
+
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+
+			return
+		}
+	}
+
+	var event events.Event
+	_ = json.Unmarshal(body, &event)
+
+	r.events = append(r.events, event)
+}
+
+func (r *receiver) received() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]events.Event{}, r.events...)
+}
+
+// startDispatcher runs a dispatcher of the outbox of userStore, retrying without waiting, until
+// the test ends.
+func startDispatcher(t *testing.T, userStore store.User, cfg Config) *Dispatcher {
+	t.Helper()
+
+	d := New(userStore, cfg)
+	d.sleep = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		d.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return d
+}
+
+func eventTypesOf(received []events.Event) []string {
+	types := make([]string, 0, len(received))
+
+	for _, event := range received {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func Test_DispatcherDelivers(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	acme := tenant.NewContext(context.Background(), "acme")
+	crm, index := newReceiver(t, "crm-secret"), newReceiver(t, "index-secret")
+
+	startDispatcher(t, userStore, Config{Endpoints: []Endpoint{
+		{URL: crm.URL, Secret: "crm-secret", Tenants: []string{AllTenants}},
+		{URL: index.URL, Secret: "index-secret", Tenants: []string{AllTenants}},
+	}})
+
+	id := userStore.Create(acme, &models.User{Fname: "John", City: "Boston"})
+	userStore.Update(acme, &models.User{ID: id, Fname: "John", City: "Denver"})
+	userStore.Delete(acme, id)
+
+	for _, r := range []*receiver{crm, index} {
+		assert.Eventually(t, func() bool { return len(r.received()) == 3 }, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"user.created", "user.updated", "user.deleted"}, eventTypesOf(r.received()))
+		assert.Equal(t, []string{"1", "2", "3"}, r.ids)
+		assert.Zero(t, r.invalid)
+	}
+
+	event := crm.received()[1]
+	assert.Equal(t, "2", event.ID)
+	assert.Equal(t, events.SchemaVersion, event.SchemaVersion)
+	assert.Equal(t, "acme", event.Tenant)
+	assert.Equal(t, int64(2), event.Revision)
+	assert.Equal(t, models.User{ID: id, Fname: "John", City: "Denver"}, event.User)
+	assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Minute)
+
+	assert.Eventually(t, func() bool {
+		messages, _ := userStore.Outbox(0, batchSize)
+
+		return len(messages) == 0
+	}, 5*time.Second, 10*time.Millisecond, "delivered messages are acknowledged")
+}
+
+func Test_DispatcherDeliversMerges(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	ctx := context.Background()
+	crm := newReceiver(t, "crm-secret")
+
+	startDispatcher(t, userStore, Config{Endpoints: []Endpoint{{URL: crm.URL, Secret: "crm-secret", Tenants: []string{AllTenants}}}})
+
+	john := userStore.Create(ctx, &models.User{Fname: "John", City: "Boston"})
+	jon := userStore.Create(ctx, &models.User{Fname: "Jon", City: "Boston"})
+	_, err := userStore.Merge(ctx, models.MergeRequest{SurvivorID: john, MergedID: jon})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(crm.received()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	event := crm.received()[2]
+	assert.Equal(t, "user.merged", event.Type)
+	assert.Equal(t, models.User{ID: john, Fname: "John", City: "Boston"}, event.User)
+	assert.Equal(t, &models.User{ID: jon, Fname: "Jon", City: "Boston"}, event.MergedUser)
+	assert.Nil(t, crm.received()[0].MergedUser)
+}
+
+func Test_DispatcherRetries(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	r := newReceiver(t, "secret", http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	d := startDispatcher(t, userStore, Config{Endpoints: []Endpoint{{URL: r.URL, Secret: "secret", Tenants: []string{AllTenants}}}})
+
+	userStore.Create(context.Background(), &models.User{Fname: "John"})
+
+	assert.Eventually(t, func() bool { return len(r.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"1", "1", "1"}, r.ids, "every attempt carries the event ID")
+	assert.Empty(t, d.DeadLetters(tenant.Default))
+}
+
+func Test_DispatcherDeadLetters(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	acme := tenant.NewContext(context.Background(), "acme")
+
+	rejecting := newReceiver(t, "secret", http.StatusBadRequest)
+	failing := newReceiver(t, "secret", http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	healthy := newReceiver(t, "secret")
+
+	d := startDispatcher(t, userStore, Config{
+		Endpoints: []Endpoint{
+			{URL: rejecting.URL, Secret: "secret", Tenants: []string{AllTenants}},
+			{URL: failing.URL, Secret: "secret", Tenants: []string{AllTenants}},
+			{URL: healthy.URL, Secret: "secret", Tenants: []string{AllTenants}},
+		},
+		MaxAttempts: 2,
+	})
+
+	userStore.Create(acme, &models.User{Fname: "John"})
+	userStore.Create(acme, &models.User{Fname: "Jane"})
+
+	assert.Eventually(t, func() bool { return len(d.DeadLetters("acme")) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return len(healthy.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	deadLetters := d.DeadLetters("acme")
+	byEndpoint := make(map[string][]DeadLetter)
+
+	for _, deadLetter := range deadLetters {
+		byEndpoint[deadLetter.Endpoint] = append(byEndpoint[deadLetter.Endpoint], deadLetter)
+	}
+
+	require.Len(t, byEndpoint[rejecting.URL], 1)
+	assert.Equal(t, 1, byEndpoint[rejecting.URL][0].Attempts, "4xx responses are not retried")
+	assert.Equal(t, "unexpected status 400 Bad Request", byEndpoint[rejecting.URL][0].LastError)
+	assert.Equal(t, int64(1), byEndpoint[rejecting.URL][0].MessageID)
+	assert.Equal(t, "1", byEndpoint[rejecting.URL][0].Event.ID)
+
+	require.Len(t, byEndpoint[failing.URL], 2)
+	assert.Equal(t, 2, byEndpoint[failing.URL][0].Attempts)
+	assert.Equal(t, "unexpected status 502 Bad Gateway", byEndpoint[failing.URL][0].LastError)
+	assert.Equal(t, "unexpected status 503 Service Unavailable", byEndpoint[failing.URL][1].LastError)
+
+	assert.Empty(t, d.DeadLetters(tenant.Default), "dead letters are scoped to their tenant")
+
+	assert.Eventually(t, func() bool {
+		messages, _ := userStore.Outbox(0, batchSize)
+
+		return len(messages) == 0
+	}, 5*time.Second, 10*time.Millisecond, "dead-lettered messages are acknowledged")
+
+	// The failing endpoint answers 503 to the first replay.
+	delivered, failed, err := d.Replay(context.Background(), "acme", []int64{byEndpoint[failing.URL][1].ID})
+	require.NoError(t, err)
+	assert.Empty(t, delivered)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Equal(t, "unexpected status 503 Service Unavailable", failed[0].LastError)
+
+	_, _, err = d.Replay(context.Background(), "acme", []int64{byEndpoint[failing.URL][1].ID, 42})
+	assert.Equal(t, errors.DeadLetterNotFound{ID: 42}, err)
+
+	_, _, err = d.Replay(context.Background(), tenant.Default, []int64{byEndpoint[failing.URL][1].ID})
+	assert.Equal(t, errors.DeadLetterNotFound{ID: byEndpoint[failing.URL][1].ID}, err)
+
+	delivered, failed, err = d.Replay(context.Background(), "acme", nil)
+	require.NoError(t, err)
+	assert.Len(t, delivered, 3)
+	assert.Empty(t, failed)
+	assert.Empty(t, d.DeadLetters("acme"))
+
+	assert.Equal(t, []string{"user.created", "user.created"}, eventTypesOf(rejecting.received()))
+	assert.Equal(t, []string{"user.created", "user.created"}, eventTypesOf(failing.received()))
+}
+
+func Test_DispatcherScopesTenants(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	acme, globex := tenant.NewContext(context.Background(), "acme"), tenant.NewContext(context.Background(), "globex")
+	crm, audit := newReceiver(t, "secret"), newReceiver(t, "secret")
+
+	startDispatcher(t, userStore, Config{Endpoints: []Endpoint{
+		{URL: crm.URL, Secret: "secret", Tenants: []string{"acme"}},
+		{URL: audit.URL, Secret: "secret", Tenants: []string{AllTenants}},
+	}})
+
+	userStore.Create(globex, &models.User{Fname: "Jane"})
+	userStore.Create(acme, &models.User{Fname: "John"})
+
+	assert.Eventually(t, func() bool { return len(audit.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return len(crm.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "acme", crm.received()[0].Tenant, "endpoints only receive the events of their tenants")
+	assert.Equal(t, []string{"2"}, crm.ids)
+
+	assert.Eventually(t, func() bool {
+		messages, _ := userStore.Outbox(0, batchSize)
+
+		return len(messages) == 0
+	}, 5*time.Second, 10*time.Millisecond, "skipped messages are acknowledged")
+}
+
+func Test_DispatcherDropsOldestDeadLetters(t *testing.T) {
+	userStore := storeUser.New(storeUser.WithOutbox())
+	rejecting := newReceiver(t, "secret", http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest)
+
+	d := startDispatcher(t, userStore, Config{
+		Endpoints:      []Endpoint{{URL: rejecting.URL, Secret: "secret", Tenants: []string{AllTenants}}},
+		MaxDeadLetters: 2,
+	})
+
+	for _, fname := range []string{"John", "Jane", "Jim"} {
+		userStore.Create(context.Background(), &models.User{Fname: fname})
+	}
+
+	assert.Eventually(t, func() bool {
+		messages, _ := userStore.Outbox(0, batchSize)
+
+		return len(messages) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	deadLetters := d.DeadLetters(tenant.Default)
+	require.Len(t, deadLetters, 2)
+	assert.Equal(t, []int64{2, 3}, []int64{deadLetters[0].ID, deadLetters[1].ID})
+
+	_, _, err := d.Replay(context.Background(), tenant.Default, []int64{1})
+	assert.Equal(t, errors.DeadLetterNotFound{ID: 1}, err)
+}
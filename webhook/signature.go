@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// IDHeader carries the ID of the event, the same across the retries of a delivery, so that
+	// receivers can drop duplicates.
+	IDHeader = "X-Webhook-Id"
+	// TimestampHeader carries the Unix time the request was signed at.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader carries the signature of the request, "sha256=" followed by the hex encoded
+	// HMAC-SHA256, keyed with the endpoint secret, of the timestamp, a dot and the body.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Sign returns the value of the SignatureHeader of a request with body signed at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature, the value of the SignatureHeader, matches the timestamp, the
+// value of the TimestampHeader, and body. Receivers should also reject old timestamps, so that
+// captured requests cannot be replayed.
+func Verify(secret, timestamp, signature string, body []byte) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SignVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("s3cret", 1700000000, body)
+
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac s3cret
+	assert.Equal(t, "sha256=ee0658aa4e37018df69c24227df01e0f680eb3b87c7f1f9bd936e283cfe01d9b", signature)
+
+	assert.True(t, Verify("s3cret", "1700000000", signature, body))
+	assert.False(t, Verify("other", "1700000000", signature, body))
+	assert.False(t, Verify("s3cret", "1700000001", signature, body))
+	assert.False(t, Verify("s3cret", "1700000000", signature, []byte(`{"id":2}`)))
+	assert.False(t, Verify("s3cret", "yesterday", signature, body))
+}